@@ -3,11 +3,14 @@ package argus
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/eleven-am/argus/internal/analyzer"
 	internalaws "github.com/eleven-am/argus/internal/aws"
+	"github.com/eleven-am/argus/internal/domain"
 )
 
 // NewAccountContext creates an account context for cross-account AWS access.
@@ -18,7 +21,8 @@ func NewAccountContext(cfg aws.Config, roleARNPattern string) *AccountContext {
 }
 
 // TestReachability analyzes network connectivity between two AWS resources.
-// It tests both directions (source→dest and dest→source) for bidirectional validation.
+// It tests the request (source→dest) and its reply (dest→source); security groups
+// and stateful firewall rules allow replies, while NACLs are checked on the reply's ports.
 // Returns a ReachabilityResult containing path traces and any blocking components.
 // Use the helper functions (EC2, RDS, Lambda, etc.) to create ResourceRef values.
 func TestReachability(ctx context.Context, source, dest ResourceRef, accountCtx *AccountContext) (ReachabilityResult, error) {
@@ -36,6 +40,45 @@ func TestReachability(ctx context.Context, source, dest ResourceRef, accountCtx
 	return result, nil
 }
 
+// TestReachabilityWithFlow is like TestReachability but tests a specific flow
+// (protocol, destination port or range, optional source port) instead of the
+// destination's default routing target.
+// Example: TestReachabilityWithFlow(ctx, EC2(acct, "i-123"), RDS(acct, "db"), TCP(5432), accountCtx)
+func TestReachabilityWithFlow(ctx context.Context, source, dest ResourceRef, flow FlowSpec, accountCtx *AccountContext) (ReachabilityResult, error) {
+	sourceComponent, err := source.resolve(ctx, accountCtx)
+	if err != nil {
+		return ReachabilityResult{}, fmt.Errorf("resolve source: %w", err)
+	}
+
+	destComponent, err := dest.resolve(ctx, accountCtx)
+	if err != nil {
+		return ReachabilityResult{}, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	result := analyzer.TestReachabilityWithFlow(ctx, sourceComponent, destComponent, flow, accountCtx, nil)
+	return result, nil
+}
+
+// TestDualStackReachability tests flow between two resources over IPv4 and over
+// IPv6 and reports both results side by side. Resources are addressed by their
+// private IPv4 address and by their first IPv6 address; a resource without an
+// IPv6 address makes the IPv6 result blocked.
+// Example: TestDualStackReachability(ctx, EC2(acct, "i-123"), ALB(acct, "web"), TCP(443), accountCtx)
+func TestDualStackReachability(ctx context.Context, source, dest ResourceRef, flow FlowSpec, accountCtx *AccountContext) (DualStackResult, error) {
+	sourceComponent, err := source.resolve(ctx, accountCtx)
+	if err != nil {
+		return DualStackResult{}, fmt.Errorf("resolve source: %w", err)
+	}
+
+	destComponent, err := dest.resolve(ctx, accountCtx)
+	if err != nil {
+		return DualStackResult{}, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	result := analyzer.TestDualStack(ctx, sourceComponent, destComponent, accountCtx, nil, Options{Flow: flow})
+	return result, nil
+}
+
 // TestReachabilityAllPaths finds all possible network paths between two AWS resources.
 // Unlike TestReachability which stops at the first successful path, this explores all routes.
 // Useful for understanding redundant paths, identifying all blocking points, or auditing.
@@ -54,3 +97,142 @@ func TestReachabilityAllPaths(ctx context.Context, source, dest ResourceRef, acc
 	result := analyzer.TestReachabilityAllPaths(ctx, sourceComponent, destComponent, accountCtx)
 	return result, nil
 }
+
+// TestReachabilityAllPathsWithFlow is like TestReachabilityAllPaths but tests a specific flow.
+func TestReachabilityAllPathsWithFlow(ctx context.Context, source, dest ResourceRef, flow FlowSpec, accountCtx *AccountContext) (AllPathsResult, error) {
+	sourceComponent, err := source.resolve(ctx, accountCtx)
+	if err != nil {
+		return AllPathsResult{}, fmt.Errorf("resolve source: %w", err)
+	}
+
+	destComponent, err := dest.resolve(ctx, accountCtx)
+	if err != nil {
+		return AllPathsResult{}, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	result := analyzer.TestReachabilityAllPathsWithFlow(ctx, sourceComponent, destComponent, flow, accountCtx, nil)
+	return result, nil
+}
+
+// Analyze tests reachability within the bounds set by opts: the flow to test,
+// first-path or all-paths strategy, maximum path depth and path count, and a
+// timeout per AWS lookup. Both strategies report an AllPathsResult. When a limit
+// or ctx's deadline is hit, the paths found so far are returned with Truncated
+// set rather than an error.
+// Example: Analyze(ctx, src, dst, accountCtx, Options{Flow: TCP(443), MaxDepth: 40, PerCallTimeout: 5 * time.Second})
+func Analyze(ctx context.Context, source, dest ResourceRef, accountCtx *AccountContext, opts Options) (AllPathsResult, error) {
+	sourceComponent, err := source.resolve(ctx, accountCtx)
+	if err != nil {
+		return AllPathsResult{}, fmt.Errorf("resolve source: %w", err)
+	}
+
+	destComponent, err := dest.resolve(ctx, accountCtx)
+	if err != nil {
+		return AllPathsResult{}, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	result := analyzer.Analyze(ctx, sourceComponent, destComponent, accountCtx, nil, opts)
+	return result, nil
+}
+
+// TestReachabilityMatrix tests every source against every destination for each
+// flow; pass no flows to use each destination's default port. Each ResourceRef
+// is resolved once and cells run concurrently, sharing AWS lookups and the
+// sub-paths they have in common. A reference that cannot be resolved fails only
+// its own cells, with the error in MatrixCell.Err.
+// Example: TestReachabilityMatrix(ctx, []ResourceRef{EC2(acct, "i-1"), EC2(acct, "i-2")}, []ResourceRef{RDS(acct, "db")}, []FlowSpec{TCP(5432)}, accountCtx)
+func TestReachabilityMatrix(ctx context.Context, sources, dests []ResourceRef, flows []FlowSpec, accountCtx *AccountContext) (MatrixResult, error) {
+	return TestReachabilityMatrixWithOptions(ctx, sources, dests, flows, accountCtx, Options{})
+}
+
+// TestReachabilityMatrixWithOptions is TestReachabilityMatrix within the
+// bounds set by opts: how many cells run at once, maximum path depth and path
+// count, and a timeout per AWS lookup. Each cell tests its own flow, so
+// opts.Flow is ignored.
+// Example: TestReachabilityMatrixWithOptions(ctx, apps, dbs, []FlowSpec{TCP(5432)}, accountCtx, Options{Concurrency: 4, PerCallTimeout: 5 * time.Second})
+func TestReachabilityMatrixWithOptions(ctx context.Context, sources, dests []ResourceRef, flows []FlowSpec, accountCtx *AccountContext, opts Options) (MatrixResult, error) {
+	resolved := resolveRefs(ctx, accountCtx, sources, dests)
+
+	sourceComponents := make([]domain.Component, len(sources))
+	for i, ref := range sources {
+		sourceComponents[i] = resolved[ref].component
+	}
+	destComponents := make([]domain.Component, len(dests))
+	for i, ref := range dests {
+		destComponents[i] = resolved[ref].component
+	}
+
+	result := analyzer.TestReachabilityMatrix(ctx, sourceComponents, destComponents, flows, accountCtx, nil, opts)
+	for i := range result.Cells {
+		cell := &result.Cells[i]
+		if err := resolved[sources[cell.SourceIndex]].err; err != nil {
+			cell.Err = fmt.Errorf("resolve source: %w", err)
+		} else if err := resolved[dests[cell.DestinationIndex]].err; err != nil {
+			cell.Err = fmt.Errorf("resolve destination: %w", err)
+		}
+	}
+	result.Summarize()
+	return result, nil
+}
+
+type resolvedRef struct {
+	component domain.Component
+	err       error
+}
+
+// resolveRefs resolves each distinct reference once, concurrently.
+func resolveRefs(ctx context.Context, accountCtx *AccountContext, refLists ...[]ResourceRef) map[ResourceRef]resolvedRef {
+	resolved := make(map[ResourceRef]resolvedRef)
+	seen := make(map[ResourceRef]bool)
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(DefaultConcurrency)
+	for _, refs := range refLists {
+		for _, ref := range refs {
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			g.Go(func() error {
+				component, err := ref.resolve(ctx, accountCtx)
+				mu.Lock()
+				resolved[ref] = resolvedRef{component: component, err: err}
+				mu.Unlock()
+				return nil
+			})
+		}
+	}
+	g.Wait()
+	return resolved
+}
+
+// FindSources lists the resources that can reach dest with flow: network
+// interfaces, EC2 instances, Lambda functions and EKS pods in dest's VPC, in
+// VPCs connected to it through peering or a transit gateway, and in security
+// groups its inbound rules reference. Each source carries the path that lets
+// it through; candidates whose analysis could not be completed are listed in
+// Undecided.
+// Example: FindSources(ctx, RDS(acct, "orders"), TCP(5432), accountCtx)
+func FindSources(ctx context.Context, dest ResourceRef, flow FlowSpec, accountCtx *AccountContext) (SourcesResult, error) {
+	destComponent, err := dest.resolve(ctx, accountCtx)
+	if err != nil {
+		return SourcesResult{}, fmt.Errorf("resolve destination: %w", err)
+	}
+
+	return analyzer.FindSources(ctx, destComponent, accountCtx, nil, Options{Flow: flow})
+}
+
+// FindInternetExposure lists the resources in vpcID that can be reached from
+// the internet and the ports they expose. Only rules open to all of 0.0.0.0/0
+// or ::/0 count; a port open to a narrower range is not reported.
+// Example: FindInternetExposure(ctx, acct, "vpc-0abc", accountCtx)
+func FindInternetExposure(ctx context.Context, accountID, vpcID string, accountCtx *AccountContext) (ExposureResult, error) {
+	return analyzer.FindInternetExposure(ctx, accountID, "", vpcID, accountCtx, nil, Options{})
+}
+
+// FindInternetExposureInRegion is FindInternetExposure for a VPC outside the
+// account context's default region.
+// Example: FindInternetExposureInRegion(ctx, acct, "eu-west-1", "vpc-0abc", accountCtx)
+func FindInternetExposureInRegion(ctx context.Context, accountID, region, vpcID string, accountCtx *AccountContext) (ExposureResult, error) {
+	return analyzer.FindInternetExposure(ctx, accountID, region, vpcID, accountCtx, nil, Options{})
+}
@@ -0,0 +1,27 @@
+package argus
+
+import "github.com/eleven-am/argus/internal/domain"
+
+// Ephemeral port ranges for FlowSpec.Ephemeral. Replies are evaluated against
+// the client's ephemeral range unless the flow sets an explicit SourcePort.
+var (
+	EphemeralDefault = domain.EphemeralDefault
+	EphemeralLinux   = domain.EphemeralLinux
+	EphemeralWindows = domain.EphemeralWindows
+)
+
+// TCP creates a flow for a single TCP destination port (e.g., TCP(5432) for Postgres).
+func TCP(port int) FlowSpec {
+	return FlowSpec{Protocol: "tcp", FromPort: port}
+}
+
+// UDP creates a flow for a single UDP destination port.
+func UDP(port int) FlowSpec {
+	return FlowSpec{Protocol: "udp", FromPort: port}
+}
+
+// PortRange creates a flow covering every destination port between fromPort and toPort.
+// Filters only allow the flow when a single rule admits the whole range.
+func PortRange(protocol string, fromPort, toPort int) FlowSpec {
+	return FlowSpec{Protocol: protocol, FromPort: fromPort, ToPort: toPort}
+}
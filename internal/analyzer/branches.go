@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// newBranchLimit returns the semaphore shared by every fan-out of an analysis.
+// The goroutine driving the analysis counts towards the limit, so a limit of
+// one (or less) yields a nil semaphore and strictly sequential exploration.
+func newBranchLimit(concurrency int) *semaphore.Weighted {
+	if concurrency <= 1 {
+		return nil
+	}
+	return semaphore.NewWeighted(int64(concurrency - 1))
+}
+
+// forEachBranch calls explore for branches 0..n-1. Branches run on their own
+// goroutine while the limit has capacity and inline otherwise, so nested
+// fan-outs can never deadlock waiting on each other.
+//
+// When explore returns true, every later branch is abandoned: those not yet
+// started are skipped and those in flight have their context cancelled. Earlier
+// branches always run to completion, so callers that scan the results in order
+// see the same outcome as a sequential walk.
+func forEachBranch(path *pathContext, n int, explore func(i int, branch *pathContext) bool) {
+	if path.limit == nil || n < 2 {
+		for i := 0; i < n; i++ {
+			if path.Context().Err() != nil {
+				return
+			}
+			if explore(i, path) {
+				return
+			}
+		}
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		cancels   = make([]context.CancelFunc, 0, n)
+		stopAfter = n
+	)
+
+	stop := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if i >= stopAfter {
+			return
+		}
+		stopAfter = i
+		for j := i + 1; j < len(cancels); j++ {
+			cancels[j]()
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if path.Context().Err() != nil {
+			break
+		}
+
+		mu.Lock()
+		if i > stopAfter {
+			mu.Unlock()
+			break
+		}
+		branchCtx, cancel := context.WithCancel(path.Context())
+		cancels = append(cancels, cancel)
+		mu.Unlock()
+
+		branch := path.withContext(branchCtx)
+		if path.limit.TryAcquire(1) {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer path.limit.Release(1)
+				if explore(i, branch) {
+					stop(i)
+				}
+			}(i)
+			continue
+		}
+
+		if explore(i, branch) {
+			stop(i)
+		}
+	}
+
+	wg.Wait()
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
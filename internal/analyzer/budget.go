@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// budget enforces the limits of one leg of an analysis and records the first
+// reason it was cut short. A nil budget imposes no limits.
+type budget struct {
+	maxDepth    int
+	maxPaths    int
+	callTimeout time.Duration
+	paths       atomic.Int64
+
+	mu     sync.Mutex
+	reason string
+}
+
+func newBudget(opts domain.Options) *budget {
+	return &budget{
+		maxDepth:    opts.MaxDepth,
+		maxPaths:    opts.MaxPaths,
+		callTimeout: opts.PerCallTimeout,
+	}
+}
+
+func (b *budget) truncate(reason string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.reason == "" {
+		b.reason = reason
+	}
+}
+
+func (b *budget) truncation() (bool, string) {
+	if b == nil {
+		return false, ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reason != "", b.reason
+}
+
+// checkDepth returns an error once a path grows beyond MaxDepth hops.
+func (b *budget) checkDepth(depth int) error {
+	if b == nil || b.maxDepth <= 0 || depth <= b.maxDepth {
+		return nil
+	}
+	reason := fmt.Sprintf("maximum depth of %d hops reached", b.maxDepth)
+	b.truncate(reason)
+	return errors.New(reason)
+}
+
+// takePath claims a slot for a completed trace, returning false once MaxPaths
+// traces have been collected.
+func (b *budget) takePath() bool {
+	if b == nil || b.maxPaths <= 0 {
+		return true
+	}
+	if b.paths.Add(1) <= int64(b.maxPaths) {
+		return true
+	}
+	b.truncatePaths()
+	return false
+}
+
+// hasRoom reports whether more traces can be collected. Exploration that is
+// skipped because the limit was reached truncates the result.
+func (b *budget) hasRoom() bool {
+	if b == nil || b.maxPaths <= 0 || b.paths.Load() < int64(b.maxPaths) {
+		return true
+	}
+	b.truncatePaths()
+	return false
+}
+
+func (b *budget) truncatePaths() {
+	b.truncate(fmt.Sprintf("maximum of %d paths reached", b.maxPaths))
+}
+
+func (b *budget) timeout() time.Duration {
+	if b == nil {
+		return 0
+	}
+	return b.callTimeout
+}
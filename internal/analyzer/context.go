@@ -2,29 +2,48 @@ package analyzer
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
 
 type analyzerContext struct {
 	ctx        context.Context
+	mu         sync.RWMutex
 	visited    map[string]bool
 	accountCtx domain.AccountContext
+	memo       *memoTable
+	limit      *semaphore.Weighted
+	budget     *budget
 }
 
+// NewAnalyzerContext returns a context that explores branches sequentially.
 func NewAnalyzerContext(ctx context.Context, accountCtx domain.AccountContext) domain.AnalyzerContext {
+	return newAnalyzerContext(ctx, accountCtx, newMemoTable(), nil)
+}
+
+func newAnalyzerContext(ctx context.Context, accountCtx domain.AccountContext, memo *memoTable, limit *semaphore.Weighted) *analyzerContext {
 	return &analyzerContext{
 		ctx:        ctx,
 		visited:    make(map[string]bool),
 		accountCtx: accountCtx,
+		memo:       memo,
+		limit:      limit,
 	}
 }
 
 func (a *analyzerContext) MarkVisited(component domain.Component) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.visited[component.GetID()] = true
 }
 
 func (a *analyzerContext) IsVisited(component domain.Component) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.visited[component.GetID()]
 }
 
@@ -35,3 +54,123 @@ func (a *analyzerContext) GetAccountContext() domain.AccountContext {
 func (a *analyzerContext) Context() context.Context {
 	return a.ctx
 }
+
+// pathContext scopes loop detection to the path being explored. A component
+// counts as visited only if it already appears on this path handling the same
+// flow, so one explored on a failed sibling branch can still be entered from
+// another branch.
+type pathContext struct {
+	domain.AnalyzerContext
+	ctx    context.Context
+	target domain.RoutingTarget
+	path   *pathNode
+	depth  int
+	memo   *memoTable
+	limit  *semaphore.Weighted
+	budget *budget
+}
+
+type pathNode struct {
+	key    string
+	parent *pathNode
+}
+
+func newPathContext(analyzerCtx domain.AnalyzerContext, target domain.RoutingTarget) *pathContext {
+	path := &pathContext{AnalyzerContext: analyzerCtx, ctx: analyzerCtx.Context(), target: target}
+	if base, ok := analyzerCtx.(*analyzerContext); ok {
+		path.memo = base.memo
+		path.limit = base.limit
+		path.budget = base.budget
+	}
+	return path
+}
+
+func (p *pathContext) Context() context.Context {
+	return p.ctx
+}
+
+// withContext returns a copy of the path whose AWS calls run under ctx, letting
+// a fan-out cancel a branch without affecting its siblings.
+func (p *pathContext) withContext(ctx context.Context) *pathContext {
+	next := *p
+	next.ctx = ctx
+	return &next
+}
+
+// enter returns the context for the path extended by component handling
+// target. The receiver is left untouched so sibling branches never see each
+// other's hops.
+func (p *pathContext) enter(component domain.Component, target domain.RoutingTarget) *pathContext {
+	next := *p
+	next.target = target
+	next.path = &pathNode{key: stateKey(component, target), parent: p.path}
+	next.depth++
+	return &next
+}
+
+// translated records that the flow leaving the last component entered is
+// target, so the next hops are checked for loops against the flow they will
+// actually handle.
+func (p *pathContext) translated(target domain.RoutingTarget) {
+	p.target = target
+}
+
+// stopped returns the error that ends this path before its next hop: the
+// analysis being cancelled or the path exceeding MaxDepth. A branch abandoned
+// because a sibling already reached the destination also stops, but does not
+// mark the result as truncated.
+func (p *pathContext) stopped() error {
+	if err := p.ctx.Err(); err != nil {
+		if p.AnalyzerContext.Context().Err() != nil {
+			p.budget.truncate("analysis interrupted: " + err.Error())
+		}
+		return err
+	}
+	return p.budget.checkDepth(p.depth)
+}
+
+// collect returns trace as a completed path, or nothing once MaxPaths traces
+// have been collected.
+func (p *pathContext) collect(trace *domain.PathTrace) []*domain.PathTrace {
+	if !p.budget.takePath() {
+		return nil
+	}
+	return []*domain.PathTrace{trace}
+}
+
+// nextHops expands component through the memo table, bounding the call by the
+// budget's per-call timeout.
+func (p *pathContext) nextHops(component domain.Component, target domain.RoutingTarget) ([]domain.Component, error) {
+	if err := peeringTransit(component, target); err != nil {
+		return nil, err
+	}
+
+	timeout := p.budget.timeout()
+	if timeout <= 0 {
+		return p.memo.nextHops(component, target, p)
+	}
+
+	callCtx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+
+	hops, err := p.memo.nextHops(component, target, p.withContext(callCtx))
+	if err != nil && p.ctx.Err() == nil && callCtx.Err() != nil {
+		p.budget.truncate(fmt.Sprintf("%s did not respond within %s", component.GetID(), timeout))
+		return nil, fmt.Errorf("lookup timed out after %s: %w", timeout, err)
+	}
+	return hops, err
+}
+
+func (p *pathContext) MarkVisited(component domain.Component) {
+	p.path = &pathNode{key: stateKey(component, p.target), parent: p.path}
+}
+
+func (p *pathContext) IsVisited(component domain.Component) bool {
+	key := stateKey(component, p.target)
+	for node := p.path; node != nil; node = node.parent {
+		if node.key == key {
+			return true
+		}
+	}
+	return false
+}
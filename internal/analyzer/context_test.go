@@ -38,7 +38,7 @@ func (m *mockAccountContext) AssumeRole(accountID string) (domain.AWSCredentials
 	return domain.AWSCredentials{}, nil
 }
 
-func (m *mockAccountContext) GetClient(accountID string) (domain.AWSClient, error) {
+func (m *mockAccountContext) GetClient(accountID, region string) (domain.AWSClient, error) {
 	return nil, nil
 }
 
@@ -114,3 +114,26 @@ func TestAnalyzerContext_GetAccountContext(t *testing.T) {
 		t.Error("GetAccountContext should return the same AccountContext")
 	}
 }
+
+func TestPathContext_IsVisitedScopedToPathAndFlow(t *testing.T) {
+	base := NewAnalyzerContext(context.Background(), nil)
+	target := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+	hub := &mockComponent{id: "tgw-hub"}
+
+	root := newPathContext(base, target)
+	branch := root.enter(hub, target)
+
+	if !branch.IsVisited(hub) {
+		t.Error("hub should be visited on the path that entered it")
+	}
+	if root.IsVisited(hub) {
+		t.Error("entering a branch should not mark the hub on its parent path")
+	}
+
+	translated := target
+	translated.SourceIP = "10.0.9.9"
+	other := &pathContext{AnalyzerContext: base, target: translated, path: branch.path}
+	if other.IsVisited(hub) {
+		t.Error("same component handling a different flow should not count as a loop")
+	}
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/eleven-am/argus/internal/components"
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// internetFamily is one of the address families the internet reaches an
+// interface over. source is the whole internet in that family, and sourceIP
+// the address that stands for it when the flow is traversed.
+type internetFamily struct {
+	sourceIP string
+	source   string
+	address  func(eni *domain.ENIData) string
+}
+
+var internetFamilies = []internetFamily{
+	{
+		sourceIP: "0.0.0.0",
+		source:   "0.0.0.0/0",
+		address: func(eni *domain.ENIData) string {
+			if eni.PublicIP == "" {
+				return ""
+			}
+			return eni.PrivateIP
+		},
+	},
+	{
+		sourceIP: "::",
+		source:   "::/0",
+		address: func(eni *domain.ENIData) string {
+			if len(eni.IPv6Addresses) == 0 {
+				return ""
+			}
+			return eni.IPv6Addresses[0]
+		},
+	},
+}
+
+type exposureCell struct {
+	exposure int
+	family   internetFamily
+	eni      *domain.ENIData
+	address  string
+	flow     domain.FlowSpec
+	result   domain.ReachabilityResult
+}
+
+// FindInternetExposure lists the resources in vpcID, in region, that the
+// internet can open connections to, and on which ports. A port counts as
+// exposed when traffic from 0.0.0.0/0 or ::/0 gets through the internet
+// gateway, the subnet's network ACL and the interface's security groups, and
+// the replies get back out. Internet-facing load balancers are reported as
+// themselves. Public API Gateway APIs linked into the VPC are listed as
+// undecided on port 443, since whether a request gets through depends on the
+// API's authorization and resource policy. Interfaces and APIs that cannot be
+// looked up are listed as undecided alongside everything else found.
+func FindInternetExposure(ctx context.Context, accountID, region, vpcID string, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) (domain.ExposureResult, error) {
+	shared := newSharedState(accountCtx, resolver, opts)
+	analyzerCtx := newAnalyzerContext(ctx, shared.accountCtx, shared.memo, shared.limit)
+
+	public, err := components.PublicInterfaces(analyzerCtx, accountID, region, vpcID)
+	if err != nil {
+		return domain.ExposureResult{}, fmt.Errorf("find public interfaces: %w", err)
+	}
+
+	exposures := make([]domain.Exposure, len(public))
+	var cells []*exposureCell
+	for i, iface := range public {
+		exposures[i] = domain.Exposure{
+			ResourceID:   iface.Owner.GetID(),
+			ResourceType: iface.Owner.GetComponentType(),
+			PublicIP:     iface.ENI.PublicIP,
+		}
+		if exposures[i].PublicIP == "" && len(iface.ENI.IPv6Addresses) > 0 {
+			exposures[i].PublicIP = iface.ENI.IPv6Addresses[0]
+		}
+
+		for _, family := range internetFamilies {
+			address := family.address(iface.ENI)
+			if address == "" {
+				continue
+			}
+			if iface.Err != nil {
+				exposures[i].Undecided = append(exposures[i].Undecided, undecidedPorts(family.source, fmt.Sprintf("owner of %s: %v", iface.ENI.ID, domain.ClassifyError(iface.Err))))
+				continue
+			}
+			flows, err := components.InternetFlows(analyzerCtx, iface.ENI, accountID, family.source)
+			if err != nil {
+				exposures[i].Undecided = append(exposures[i].Undecided, undecidedPorts(family.source, fmt.Sprintf("open ports of %s: %v", iface.ENI.ID, domain.ClassifyError(err))))
+				continue
+			}
+			for _, flow := range flows {
+				cells = append(cells, &exposureCell{exposure: i, family: family, eni: iface.ENI, address: address, flow: flow})
+			}
+		}
+	}
+
+	var g errgroup.Group
+	g.SetLimit(opts.EffectiveConcurrency())
+	for _, cell := range cells {
+		cellOpts := opts
+		cellOpts.Flow = cell.flow
+		g.Go(func() error {
+			// Components are built per cell because interfaces record the
+			// address they were last traversed with.
+			addressed := *cell.eni
+			addressed.PrivateIP = cell.address
+			destination := components.NewNetworkInterfaceFromData(&addressed, accountID)
+			source := components.NewInternetSource(cell.family.sourceIP, accountID, region, cell.eni.VPCID)
+			cell.result = shared.testReachability(ctx, source, destination, cellOpts)
+			return nil
+		})
+	}
+	g.Wait()
+
+	for _, cell := range cells {
+		exposure := &exposures[cell.exposure]
+		switch cell.result.Verdict {
+		case domain.VerdictReachable:
+			exposure.Ports = mergeExposedPorts(exposure.Ports, cell)
+		case domain.VerdictUnknown:
+			exposure.Undecided = mergeExposedPorts(exposure.Undecided, cell)
+		}
+	}
+
+	apis, err := apisLinkedTo(ctx, accountCtx, accountID, region, vpcID)
+	if err != nil {
+		exposures = append(exposures, domain.Exposure{
+			ResourceID:   fmt.Sprintf("%s:%s", accountID, vpcID),
+			ResourceType: "APIGateway",
+			Undecided: []domain.ExposedPorts{{
+				Protocol: "tcp",
+				FromPort: 443,
+				ToPort:   443,
+				Source:   "0.0.0.0/0",
+				Reason:   fmt.Sprintf("find APIs linked to %s: %v", vpcID, domain.ClassifyError(err)),
+			}},
+		})
+	}
+	for i := range apis {
+		if apis[i].EndpointType == "PRIVATE" {
+			continue
+		}
+		api := components.NewAPIGateway(&apis[i], accountID)
+		exposures = append(exposures, domain.Exposure{
+			ResourceID:   api.GetID(),
+			ResourceType: api.GetComponentType(),
+			Undecided: []domain.ExposedPorts{{
+				Protocol: "tcp",
+				FromPort: 443,
+				ToPort:   443,
+				Source:   "0.0.0.0/0",
+				Reason:   "public API endpoint; the API's authorization and resource policy are not evaluated",
+			}},
+		})
+	}
+
+	var result domain.ExposureResult
+	for _, exposure := range exposures {
+		if len(exposure.Ports) > 0 || len(exposure.Undecided) > 0 {
+			result.Exposures = append(result.Exposures, exposure)
+		}
+	}
+	return result, nil
+}
+
+// apisLinkedTo returns the API Gateway APIs linked into vpcID.
+func apisLinkedTo(ctx context.Context, accountCtx domain.AccountContext, accountID, region, vpcID string) ([]domain.APIGatewayData, error) {
+	client, err := accountCtx.GetClient(accountID, region)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetAPIGatewaysByVPC(ctx, vpcID)
+}
+
+// undecidedPorts is every port of an interface whose exposure to source could
+// not be worked out, and why.
+func undecidedPorts(source, reason string) domain.ExposedPorts {
+	return domain.ExposedPorts{
+		Protocol: "-1",
+		FromPort: 0,
+		ToPort:   65535,
+		Source:   source,
+		Reason:   reason,
+	}
+}
+
+// mergeExposedPorts adds cell's ports to ports, extending the last range when
+// cell continues it so adjacent flows read as one range. The paths of the
+// first flow in a range stand for the whole range.
+func mergeExposedPorts(ports []domain.ExposedPorts, cell *exposureCell) []domain.ExposedPorts {
+	from, to := cell.flow.FromPort, max(cell.flow.ToPort, cell.flow.FromPort)
+	if n := len(ports); n > 0 {
+		last := &ports[n-1]
+		if last.Protocol == cell.flow.Protocol && last.Source == cell.family.source && last.ToPort+1 == from {
+			last.ToPort = to
+			return ports
+		}
+	}
+	return append(ports, domain.ExposedPorts{
+		Protocol:   cell.flow.Protocol,
+		FromPort:   from,
+		ToPort:     to,
+		Source:     cell.family.source,
+		Path:       cell.result.ForwardPath,
+		ReturnPath: cell.result.ReturnPath,
+	})
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// TestDualStack tests the flow in opts once over IPv4 and once over IPv6. The
+// two runs share the resolver and memo table.
+func TestDualStack(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) domain.DualStackResult {
+	shared := newSharedState(accountCtx, resolver, opts)
+
+	v4, v6 := opts, opts
+	v4.Flow.Family = domain.AddressFamilyIPv4
+	v6.Flow.Family = domain.AddressFamilyIPv6
+
+	var result domain.DualStackResult
+	var g errgroup.Group
+	g.Go(func() error {
+		result.IPv4 = shared.testReachability(ctx, source, destination, v4)
+		return nil
+	})
+	g.Go(func() error {
+		result.IPv6 = shared.testReachability(ctx, source, destination, v6)
+		return nil
+	})
+	g.Wait()
+	return result
+}
+
+// flowFamily returns the IP version flow uses between source and destination.
+// Without an explicit family, an endpoint that is itself an IPv6 address makes
+// the flow IPv6.
+func flowFamily(flow domain.FlowSpec, source, destination domain.Component) domain.AddressFamily {
+	if flow.Family != "" {
+		return flow.Family
+	}
+	if isIPv6(source.GetRoutingTarget().IP) || isIPv6(destination.GetRoutingTarget().IP) {
+		return domain.AddressFamilyIPv6
+	}
+	return domain.AddressFamilyIPv4
+}
+
+// familyTarget returns c's routing target addressed over family. The boolean
+// is false when c has an address, but none in family.
+func familyTarget(c domain.Component, family domain.AddressFamily) (domain.RoutingTarget, bool) {
+	target := c.GetRoutingTarget()
+	if target.IP == "" || isIPv6(target.IP) == (family == domain.AddressFamilyIPv6) {
+		return target, true
+	}
+	if dualStack, ok := c.(domain.DualStackComponent); ok && family == domain.AddressFamilyIPv6 {
+		if addresses := dualStack.GetIPv6Addresses(); len(addresses) > 0 {
+			target.IP = addresses[0]
+			return target, true
+		}
+	}
+	return target, false
+}
+
+// unaddressable returns the result of a flow that cannot start because
+// endpoint has no address of the flow's IP version, which is the version
+// endpoint's own address is not. The flow is blocked when endpoint reports its
+// IPv6 addresses, and undecided when it cannot, as it may still have one.
+func unaddressable(endpoint domain.Component) (domain.PathResult, *domain.PathTrace) {
+	trace := domain.NewPathTrace()
+	hop := domain.HopFromComponent(endpoint, domain.HopLineage{}, inferHopAction(endpoint), "")
+	trace.AddHop(hop)
+	version := "IPv6"
+	if isIPv6(endpoint.GetRoutingTarget().IP) {
+		version = "IPv4"
+	}
+	var err error = &domain.BlockingError{
+		ComponentID: endpoint.GetID(),
+		Reason:      fmt.Sprintf("%s has no %s address", endpoint.GetID(), version),
+	}
+	if _, ok := endpoint.(domain.DualStackComponent); !ok && version == "IPv6" {
+		err = fmt.Errorf("%s addresses of %s are not known", version, endpoint.GetID())
+	}
+	return blockAt(trace, hop, endpoint, err), trace
+}
+
+func isIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
+}
@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// TestReachabilityMatrix tests every source against every destination for
+// each flow, with no flows meaning the destination's default routing target.
+// Cells run concurrently under opts' concurrency limit and share the resolver
+// and memo table, so components and subtrees common to several cells are only
+// fetched and walked once. A nil source or destination marks a reference the
+// caller could not resolve: its cells are left without a result for the caller
+// to fill in.
+func TestReachabilityMatrix(ctx context.Context, sources, destinations []domain.Component, flows []domain.FlowSpec, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) domain.MatrixResult {
+	if len(flows) == 0 {
+		flows = []domain.FlowSpec{{}}
+	}
+
+	shared := newSharedState(accountCtx, resolver, opts)
+	result := domain.NewMatrixResult(len(sources), len(destinations), flows)
+
+	var g errgroup.Group
+	g.SetLimit(opts.EffectiveConcurrency())
+	for i := range result.Cells {
+		cell := &result.Cells[i]
+		source, destination := sources[cell.SourceIndex], destinations[cell.DestinationIndex]
+		if source == nil || destination == nil {
+			continue
+		}
+
+		cellOpts := opts
+		cellOpts.Flow = cell.Flow
+		g.Go(func() error {
+			cell.Result = shared.testReachability(ctx, source, destination, cellOpts)
+			return nil
+		})
+	}
+	g.Wait()
+
+	result.Summarize()
+	return result
+}
@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// memoTable caches sub-results of a traversal keyed on (component, flow), so a
+// subgraph reached through several branches is only fetched and walked once.
+type memoTable struct {
+	mu         sync.Mutex
+	expansions map[string]*expansion
+	failures   map[string]failure
+}
+
+// expansion is the result of a component's GetNextHops. done is closed once
+// hops and err are set, so concurrent branches wait for a single fetch.
+type expansion struct {
+	done chan struct{}
+	hops []domain.Component
+	err  error
+}
+
+// failure is a subtree that was fully explored without reaching the
+// destination. Only subtrees whose outcome did not depend on the path taken to
+// reach them are recorded, i.e. no branch was cut by loop detection.
+type failure struct {
+	result  domain.PathResult
+	hops    []domain.ComponentHop
+	blocked int
+}
+
+func newMemoTable() *memoTable {
+	return &memoTable{
+		expansions: make(map[string]*expansion),
+		failures:   make(map[string]failure),
+	}
+}
+
+func (m *memoTable) nextHops(current domain.Component, target domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	if m == nil {
+		return current.GetNextHops(target, analyzerCtx)
+	}
+
+	key := expansionKey(current, target)
+	m.mu.Lock()
+	if cached, ok := m.expansions[key]; ok {
+		m.mu.Unlock()
+		<-cached.done
+		if !isCancellation(cached.err) {
+			return cached.hops, cached.err
+		}
+		return current.GetNextHops(target, analyzerCtx)
+	}
+	entry := &expansion{done: make(chan struct{})}
+	m.expansions[key] = entry
+	m.mu.Unlock()
+
+	entry.hops, entry.err = current.GetNextHops(target, analyzerCtx)
+	if entry.err != nil && analyzerCtx.Context().Err() != nil {
+		// The branch was abandoned mid-fetch; let the next caller retry.
+		entry.err = context.Cause(analyzerCtx.Context())
+		m.mu.Lock()
+		delete(m.expansions, key)
+		m.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.hops, entry.err
+}
+
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (m *memoTable) recordFailure(key string, result domain.PathResult, trace *domain.PathTrace, start int) {
+	if m == nil {
+		return
+	}
+
+	entry := failure{result: result, blocked: -1}
+	for i, hop := range trace.Hops[start:] {
+		entry.hops = append(entry.hops, *hop)
+		if hop == trace.BlockedAt {
+			entry.blocked = i
+		}
+	}
+
+	m.mu.Lock()
+	m.failures[key] = entry
+	m.mu.Unlock()
+}
+
+// replayFailure appends a recorded failure to trace, re-parenting its first hop
+// under lineage.
+func (m *memoTable) replayFailure(key string, trace *domain.PathTrace, lineage domain.HopLineage) (domain.PathResult, bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	entry, ok := m.failures[key]
+	m.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	for i := range entry.hops {
+		hop := entry.hops[i]
+		if i == 0 {
+			hop.SourceID = lineage.SourceID
+			hop.SourceType = lineage.SourceType
+			hop.Relationship = lineage.Relationship
+		}
+		trace.AddHop(&hop)
+		if i == entry.blocked {
+			trace.BlockedAt = &hop
+		}
+	}
+	trace.Success = false
+	return entry.result, true
+}
+
+// expansionKey identifies the next hops of a component for target. A
+// source-agnostic component expands the same way for every source, so the
+// source is left out and sources tested together share the expansion. Loop
+// detection and recorded failures keep the full stateKey, as what lies beyond
+// the component may still depend on the source.
+func expansionKey(c domain.Component, target domain.RoutingTarget) string {
+	if agnostic, ok := c.(domain.SourceAgnostic); ok && agnostic.IsSourceAgnostic() {
+		target.SourceIP = ""
+		target.SourcePort = 0
+	}
+	return stateKey(c, target)
+}
+
+// stateKey identifies a component together with the flow it is handling.
+func stateKey(c domain.Component, target domain.RoutingTarget) string {
+	id := c.GetID()
+	if provider, ok := c.(domain.StateKeyProvider); ok {
+		id = provider.GetStateKey()
+	}
+	return fmt.Sprintf("%s|%s|%s:%d|%s:%d-%d|%s|%t|%s|%s",
+		id, target.Protocol, target.SourceIP, target.SourcePort, target.IP, target.Port, target.PortTo, target.Direction, target.Reply, target.ViaPeering, target.EnteredSubnet)
+}
@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// peeringEdges names the gateways traffic that entered a VPC over a peering
+// connection cannot leave through. AWS does not support edge-to-edge routing
+// across a peering connection, so such traffic can only be delivered inside
+// the peer VPC.
+var peeringEdges = map[string]string{
+	"InternetGateway":           "an internet gateway",
+	"EgressOnlyInternetGateway": "an egress-only internet gateway",
+	"NATGateway":                "a NAT gateway",
+	"VirtualPrivateGateway":     "a virtual private gateway",
+	"TransitGatewayAttachment":  "a transit gateway",
+	"LocalGateway":              "a local gateway",
+	"CarrierGateway":            "a carrier gateway",
+}
+
+// peeringTransit returns a blocking error when target entered its VPC over a
+// peering connection and component would carry it out of that VPC again.
+func peeringTransit(component domain.Component, target domain.RoutingTarget) error {
+	if target.ViaPeering == "" {
+		return nil
+	}
+	edge, ok := peeringEdges[component.GetComponentType()]
+	if !ok {
+		return nil
+	}
+	return &domain.BlockingError{
+		ComponentID: component.GetID(),
+		Reason:      fmt.Sprintf("traffic that entered through VPC peering connection %s cannot leave through %s: edge-to-edge routing over peering is not supported", target.ViaPeering, edge),
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/eleven-am/argus/internal/components"
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// FindSources answers "who can reach destination": it lists the network
+// interfaces, instances, Lambda functions and pods that may send opts.Flow to
+// destination and keeps those whose request and reply both get through.
+func FindSources(ctx context.Context, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) (domain.SourcesResult, error) {
+	shared := newSharedState(accountCtx, resolver, opts)
+
+	// Candidates are admitted on their address in the flow's IP version, so
+	// the destination is addressed in it too.
+	target, _ := familyTarget(destination, opts.Flow.Family)
+	dest := opts.Flow.Apply(target)
+	dest.Direction = "inbound"
+	candidates, unreached, err := components.CandidateSources(newAnalyzerContext(ctx, shared.accountCtx, shared.memo, shared.limit), destination, dest)
+	if err != nil {
+		return domain.SourcesResult{}, fmt.Errorf("find candidate sources: %w", err)
+	}
+
+	found := shared.findSources(ctx, destination, candidates, opts)
+	found.Undecided = append(found.Undecided, unreached...)
+	return found, nil
+}
+
+// findSources tests each candidate against destination, concurrently and
+// sharing the memo table, and keeps them in candidate order.
+func (s *sharedState) findSources(ctx context.Context, destination domain.Component, candidates []domain.Component, opts domain.Options) domain.SourcesResult {
+	results := make([]domain.ReachabilityResult, len(candidates))
+
+	var g errgroup.Group
+	g.SetLimit(opts.EffectiveConcurrency())
+	for i, candidate := range candidates {
+		g.Go(func() error {
+			results[i] = s.testReachability(ctx, candidate, destination, opts)
+			return nil
+		})
+	}
+	g.Wait()
+
+	found := domain.SourcesResult{Candidates: len(candidates)}
+	for i, result := range results {
+		sourceTarget, _ := familyTarget(candidates[i], opts.Flow.Family)
+		source := domain.SourcePath{
+			SourceID:   candidates[i].GetID(),
+			SourceType: candidates[i].GetComponentType(),
+			SourceIP:   sourceTarget.IP,
+			Verdict:    result.Verdict,
+			Path:       result.ForwardPath,
+			ReturnPath: result.ReturnPath,
+		}
+		switch result.Verdict {
+		case domain.VerdictReachable:
+			found.Sources = append(found.Sources, source)
+		case domain.VerdictUnknown:
+			found.Undecided = append(found.Undecided, source)
+		}
+	}
+	return found
+}
@@ -2,9 +2,11 @@ package analyzer
 
 import (
 	"context"
-	"errors"
 	"net"
 
+	"golang.org/x/sync/semaphore"
+
+	"github.com/eleven-am/argus/internal/components"
 	"github.com/eleven-am/argus/internal/domain"
 	resolverpkg "github.com/eleven-am/argus/internal/resolver"
 )
@@ -14,88 +16,175 @@ func TestReachability(ctx context.Context, source, destination domain.Component,
 }
 
 func TestReachabilityWithResolver(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver) domain.ReachabilityResult {
-	if resolver == nil && accountCtx != nil {
-		resolver = resolverpkg.NewResolver(accountCtx)
-	}
+	return TestReachabilityWithFlow(ctx, source, destination, domain.FlowSpec{}, accountCtx, resolver)
+}
 
-	destTarget := destination.GetRoutingTarget()
-	destTarget.Direction = "outbound"
-	destTarget.SourceIsPrivate = isPrivateIPStr(source.GetRoutingTarget().IP)
-	sourceTarget := source.GetRoutingTarget()
-	sourceTarget.Direction = "inbound"
-	sourceTarget.SourceIsPrivate = isPrivateIPStr(destTarget.IP)
+func TestReachabilityWithFlow(ctx context.Context, source, destination domain.Component, flow domain.FlowSpec, accountCtx domain.AccountContext, resolver domain.DestinationResolver) domain.ReachabilityResult {
+	return TestReachabilityWithOptions(ctx, source, destination, accountCtx, resolver, domain.Options{Flow: flow})
+}
 
-	ctxWithResolver := &accountContextWithResolver{
-		AccountContext: accountCtx,
-		resolver:       resolver,
+func TestReachabilityWithOptions(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) domain.ReachabilityResult {
+	return newSharedState(accountCtx, resolver, opts).testReachability(ctx, source, destination, opts)
+}
+
+func (s *sharedState) testReachability(ctx context.Context, source, destination domain.Component, opts domain.Options) domain.ReachabilityResult {
+	source = components.SourceFor(source, destination)
+	destTarget, sourceTarget, unaddressed := legTargets(source, destination, opts.Flow)
+	if unaddressed != nil {
+		blocked, trace := unaddressable(unaddressed)
+		return domain.CombineResultsWithTrace(blocked, blocked, trace, domain.NewPathTrace())
 	}
+	forwardAnalyzer, returnAnalyzer := s.legs(ctx, opts)
 
-	sourceAnalyzer := NewAnalyzerContext(ctx, ctxWithResolver)
 	forwardTrace := domain.NewPathTrace()
-	sourceResult := TraversePathWithTrace(source, destTarget, destination.GetID(), sourceAnalyzer, resolver, forwardTrace, domain.HopLineage{})
+	sourceResult := TraversePathWithTrace(source, destTarget, destination.GetID(), forwardAnalyzer, s.resolver, forwardTrace, domain.HopLineage{})
 
-	destAnalyzer := NewAnalyzerContext(ctx, ctxWithResolver)
 	returnTrace := domain.NewPathTrace()
-	destResult := TraversePathWithTrace(destination, sourceTarget, source.GetID(), destAnalyzer, resolver, returnTrace, domain.HopLineage{})
+	destResult := TraversePathWithTrace(components.SourceFor(destination, source), sourceTarget, source.GetID(), returnAnalyzer, s.resolver, returnTrace, domain.HopLineage{})
 
-	return domain.CombineResultsWithTrace(sourceResult, destResult, forwardTrace, returnTrace)
+	result := domain.CombineResultsWithTrace(sourceResult, destResult, forwardTrace, returnTrace)
+	result.Truncated, result.TruncatedReason = truncation(forwardAnalyzer, returnAnalyzer)
+	return result
 }
 
-func TraversePath(current domain.Component, destination domain.RoutingTarget, destinationID string, analyzerCtx domain.AnalyzerContext, resolver domain.DestinationResolver) domain.PathResult {
-	analyzerCtx.MarkVisited(current)
+// Analyze tests reachability under the limits in opts and always reports the
+// traces it found, flagging the result as truncated when a limit or ctx cut
+// the analysis short.
+func Analyze(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) domain.AllPathsResult {
+	if opts.Strategy == domain.StrategyAllPaths {
+		return TestReachabilityAllPathsWithOptions(ctx, source, destination, accountCtx, resolver, opts)
+	}
 
-	nextHops, err := current.GetNextHops(destination, analyzerCtx)
-	if err != nil {
-		return domain.BlockedResult{
-			BlockingComponent: current,
-			Reason:            err,
-		}
+	result := TestReachabilityWithOptions(ctx, source, destination, accountCtx, resolver, opts)
+	allPaths := domain.AllPathsResult{
+		Verdict:          result.Verdict,
+		ForwardPaths:     []*domain.PathTrace{result.ForwardPath},
+		ReturnPaths:      []*domain.PathTrace{result.ReturnPath},
+		HasReachablePath: result.OverallSuccess,
+		Truncated:        result.Truncated,
+		TruncatedReason:  result.TruncatedReason,
+	}
+	if result.ForwardPath.Success {
+		allPaths.SuccessfulForwardPaths = 1
+	}
+	if result.ReturnPath.Success {
+		allPaths.SuccessfulReturnPaths = 1
 	}
+	return allPaths
+}
 
-	filteredHops := filterVisited(nextHops, analyzerCtx)
+// newLegContexts returns the analyzer contexts for the forward and return legs.
+// They share the resolver, memo table and concurrency limit, but each leg has
+// its own budget so MaxPaths applies per direction.
+func newLegContexts(ctx context.Context, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) (*analyzerContext, *analyzerContext, domain.DestinationResolver) {
+	shared := newSharedState(accountCtx, resolver, opts)
+	forward, reverse := shared.legs(ctx, opts)
+	return forward, reverse, shared.resolver
+}
 
-	if IsDestinationReached(filteredHops, destination, destinationID) {
-		return domain.SuccessResult{}
+// sharedState is what analyses run together have in common, so that a
+// component fetched or a subtree walked for one is reused by the others.
+type sharedState struct {
+	accountCtx *accountContextWithResolver
+	resolver   domain.DestinationResolver
+	memo       *memoTable
+	limit      *semaphore.Weighted
+}
+
+func newSharedState(accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) *sharedState {
+	if resolver == nil && accountCtx != nil {
+		resolver = resolverpkg.NewResolver(accountCtx)
 	}
 
-	if len(filteredHops) == 0 {
-		if isTerminalComponent(current) && isExternalDestination(destination.IP) {
-			return domain.SuccessResult{}
-		}
-		if isFilterComponent(current) {
-			return domain.SuccessResult{}
-		}
-		return domain.BlockedResult{
-			BlockingComponent: current,
-			Reason:            errors.New("no route to destination"),
-		}
+	return &sharedState{
+		accountCtx: &accountContextWithResolver{
+			AccountContext: accountCtx,
+			resolver:       resolver,
+		},
+		resolver: resolver,
+		memo:     newMemoTable(),
+		limit:    newBranchLimit(opts.EffectiveConcurrency()),
 	}
+}
 
-	var lastBlockedResult domain.PathResult
-	for _, hop := range filteredHops {
-		result := TraversePath(hop, destination, destinationID, analyzerCtx, resolver)
-		if !result.IsBlocked() {
-			return result
+func (s *sharedState) legs(ctx context.Context, opts domain.Options) (*analyzerContext, *analyzerContext) {
+	forward := newAnalyzerContext(ctx, s.accountCtx, s.memo, s.limit)
+	forward.budget = newBudget(opts)
+	reverse := newAnalyzerContext(ctx, s.accountCtx, s.memo, s.limit)
+	reverse.budget = newBudget(opts)
+	return forward, reverse
+}
+
+func truncation(legs ...*analyzerContext) (bool, string) {
+	for _, leg := range legs {
+		if truncated, reason := leg.budget.truncation(); truncated {
+			return true, reason
 		}
-		lastBlockedResult = result
 	}
+	return false, ""
+}
 
-	if lastBlockedResult != nil {
-		return lastBlockedResult
+// legTargets returns the routing targets of the forward and return legs,
+// addressed over the flow's IP version. The component is the source or
+// destination when it has no address of that version, and nil otherwise.
+func legTargets(source, destination domain.Component, flow domain.FlowSpec) (domain.RoutingTarget, domain.RoutingTarget, domain.Component) {
+	family := flowFamily(flow, source, destination)
+	sourceTarget, ok := familyTarget(source, family)
+	if !ok {
+		return domain.RoutingTarget{}, domain.RoutingTarget{}, source
 	}
-
-	return domain.BlockedResult{
-		BlockingComponent: current,
-		Reason:            errors.New("all paths blocked"),
+	destTarget, ok := familyTarget(destination, family)
+	if !ok {
+		return domain.RoutingTarget{}, domain.RoutingTarget{}, destination
 	}
+	sourceIP := sourceTarget.IP
+
+	destTarget = flow.Apply(destTarget)
+	destTarget.SourceIP = sourceIP
+	destTarget.Direction = "outbound"
+	destTarget.SourceIsPrivate = isPrivateIPStr(sourceIP)
+
+	sourceTarget.Port, sourceTarget.PortTo = flow.ReplyPorts()
+	sourceTarget.Protocol = destTarget.Protocol
+	sourceTarget.SourceIP = destTarget.IP
+	sourceTarget.SourcePort = destTarget.Port
+	sourceTarget.Direction = "outbound"
+	sourceTarget.SourceIsPrivate = isPrivateIPStr(destTarget.IP)
+	sourceTarget.Reply = true
+
+	return destTarget, sourceTarget, nil
+}
+
+func TraversePath(current domain.Component, destination domain.RoutingTarget, destinationID string, analyzerCtx domain.AnalyzerContext, resolver domain.DestinationResolver) domain.PathResult {
+	return TraversePathWithTrace(current, destination, destinationID, analyzerCtx, resolver, domain.NewPathTrace(), domain.HopLineage{})
 }
 
 func TraversePathWithTrace(current domain.Component, destination domain.RoutingTarget, destinationID string, analyzerCtx domain.AnalyzerContext, resolver domain.DestinationResolver, trace *domain.PathTrace, lineage domain.HopLineage) domain.PathResult {
-	analyzerCtx.MarkVisited(current)
+	result, _ := traversePath(current, destination, destinationID, newPathContext(analyzerCtx, destination), trace, lineage)
+	return result
+}
+
+// traversePath walks the first path from current that reaches the destination.
+// The boolean reports whether loop detection, a limit or cancellation cut any
+// branch below current, in which case a failure depends on how current was
+// reached and must not be memoized.
+func traversePath(current domain.Component, destination domain.RoutingTarget, destinationID string, path *pathContext, trace *domain.PathTrace, lineage domain.HopLineage) (domain.PathResult, bool) {
+	failureKey := stateKey(current, destination) + "|" + destinationID
+	if result, ok := path.memo.replayFailure(failureKey, trace, lineage); ok {
+		return result, false
+	}
+
+	path = path.enter(current, destination)
+	start := len(trace.Hops)
 
 	action := inferHopAction(current)
 	hop := domain.HopFromComponent(current, lineage, action, "")
 
+	if err := path.stopped(); err != nil {
+		trace.AddHop(hop)
+		return blockAt(trace, hop, current, err), true
+	}
+
 	if evaluator, ok := current.(domain.RuleEvaluator); ok {
 		result := evaluator.EvaluateWithDetails(destination, destination.Direction)
 		hop.RuleEvaluations = result.Evaluations
@@ -103,77 +192,146 @@ func TraversePathWithTrace(current domain.Component, destination domain.RoutingT
 
 	trace.AddHop(hop)
 
-	nextHops, err := current.GetNextHops(destination, analyzerCtx)
+	nextHops, err := path.nextHops(current, destination)
 	if err != nil {
-		hop.Action = domain.HopActionBlocked
-		hop.Details = err.Error()
-		trace.BlockedAt = hop
-		trace.Success = false
-		return domain.BlockedResult{
-			BlockingComponent: current,
-			Reason:            err,
+		result := blockAt(trace, hop, current, err)
+		if isCancellation(err) {
+			return result, true
 		}
+		path.memo.recordFailure(failureKey, result, trace, start)
+		return result, false
 	}
+	advise(current, destination, path, hop)
+	destination = translate(current, destination, hop)
+	path.translated(destination)
 
-	filteredHops := filterVisited(nextHops, analyzerCtx)
-
-	if IsDestinationReached(filteredHops, destination, destinationID) {
-		hop.Action = domain.HopActionTerminal
-		hop.Details = "destination reached"
-		trace.MarkSuccess()
-		return domain.SuccessResult{}
+	if reached := reachedDestination(nextHops, destination, destinationID); reached != nil {
+		result := enterDestination(current, reached, destination, path, trace, hop)
+		if blocked, ok := result.(domain.BlockedResult); ok {
+			if isCancellation(blocked.Reason) {
+				return result, true
+			}
+			path.memo.recordFailure(failureKey, result, trace, start)
+		}
+		return result, false
 	}
 
+	filteredHops := filterVisited(nextHops, path)
+	cut := len(filteredHops) < len(nextHops)
+
 	if len(filteredHops) == 0 {
 		if isTerminalComponent(current) && isExternalDestination(destination.IP) {
 			hop.Action = domain.HopActionTerminal
 			hop.Details = "external destination via terminal"
 			trace.MarkSuccess()
-			return domain.SuccessResult{}
+			return domain.SuccessResult{}, false
 		}
 		if isFilterComponent(current) {
 			hop.Details = "filter passed"
 			trace.MarkSuccess()
-			return domain.SuccessResult{}
+			return domain.SuccessResult{}, false
 		}
-		hop.Action = domain.HopActionBlocked
-		hop.Details = "no route to destination"
-		trace.BlockedAt = hop
-		trace.Success = false
-		return domain.BlockedResult{
-			BlockingComponent: current,
-			Reason:            errors.New("no route to destination"),
+		result := blockAt(trace, hop, current, noRoute(current))
+		if !cut {
+			path.memo.recordFailure(failureKey, result, trace, start)
 		}
+		return result, cut
 	}
 
-	var lastBlockedResult domain.PathResult
-	for _, nextHop := range filteredHops {
-		nextLineage := inferLineage(current, nextHop)
+	type branchOutcome struct {
+		result domain.PathResult
+		trace  *domain.PathTrace
+		cut    bool
+	}
+	outcomes := make([]*branchOutcome, len(filteredHops))
+	forEachBranch(path, len(filteredHops), func(i int, branch *pathContext) bool {
+		nextHop := filteredHops[i]
 		branchTrace := trace.Clone()
-		result := TraversePathWithTrace(nextHop, destination, destinationID, analyzerCtx, resolver, branchTrace, nextLineage)
-		if !result.IsBlocked() {
-			trace.Hops = branchTrace.Hops
-			trace.Success = branchTrace.Success
-			trace.BlockedAt = branchTrace.BlockedAt
-			return result
+		result, branchCut := traversePath(nextHop, destination, destinationID, branch, branchTrace, inferLineage(current, nextHop))
+		outcomes[i] = &branchOutcome{result: result, trace: branchTrace, cut: branchCut}
+		return !result.IsBlocked()
+	})
+
+	// Report the last failed branch, unless one failed because the analysis
+	// itself did: the path is then undecided rather than blocked.
+	var reported *branchOutcome
+	for _, outcome := range outcomes {
+		if outcome == nil {
+			cut = true
+			continue
+		}
+		if !outcome.result.IsBlocked() {
+			trace.Hops = outcome.trace.Hops
+			trace.Success = outcome.trace.Success
+			trace.BlockedAt = outcome.trace.BlockedAt
+			return outcome.result, false
+		}
+		cut = cut || outcome.cut
+		if reported == nil || domain.VerdictOf(reported.result) == domain.VerdictBlocked || domain.VerdictOf(outcome.result) == domain.VerdictUnknown {
+			reported = outcome
 		}
-		lastBlockedResult = result
 	}
 
-	if lastBlockedResult != nil {
-		return lastBlockedResult
+	if reported == nil {
+		err := path.Context().Err()
+		if err == nil {
+			err = &domain.BlockingError{ComponentID: current.GetID(), Reason: "all paths blocked"}
+		}
+		return blockAt(trace, hop, current, err), true
 	}
 
+	trace.Hops = reported.trace.Hops
+	trace.Success = false
+	trace.BlockedAt = reported.trace.BlockedAt
+	if !cut {
+		path.memo.recordFailure(failureKey, reported.result, trace, start)
+	}
+	return reported.result, cut
+}
+
+// translate returns the flow as it leaves current, recording the rewrite on
+// hop when current translates addresses.
+func translate(current domain.Component, destination domain.RoutingTarget, hop *domain.ComponentHop) domain.RoutingTarget {
+	translator, ok := current.(domain.Translator)
+	if !ok {
+		return destination
+	}
+	translated := translator.Translate(destination)
+	if translated.Flow() != destination.Flow() {
+		hop.Translation = &domain.Translation{Before: destination.Flow(), After: translated.Flow()}
+	}
+	return translated
+}
+
+// advise records on hop any risk current sees in the flow it lets through.
+func advise(current domain.Component, destination domain.RoutingTarget, analyzerCtx domain.AnalyzerContext, hop *domain.ComponentHop) {
+	if advisor, ok := current.(domain.Advisor); ok {
+		hop.Advisory = advisor.Advise(destination, analyzerCtx)
+	}
+}
+
+// blockAt marks hop as the point where the path stopped because of err. Errors
+// other than configuration blocks are classified and attached to the hop so
+// callers can tell "blocked" from "could not analyze".
+func blockAt(trace *domain.PathTrace, hop *domain.ComponentHop, component domain.Component, err error) domain.BlockedResult {
+	err = domain.ClassifyError(err)
 	hop.Action = domain.HopActionBlocked
-	hop.Details = "all paths blocked"
+	hop.Details = err.Error()
+	if !domain.IsBlocking(err) {
+		hop.Error = err
+	}
 	trace.BlockedAt = hop
 	trace.Success = false
 	return domain.BlockedResult{
-		BlockingComponent: current,
-		Reason:            errors.New("all paths blocked"),
+		BlockingComponent: component,
+		Reason:            err,
 	}
 }
 
+func noRoute(component domain.Component) error {
+	return &domain.BlockingError{ComponentID: component.GetID(), Reason: "no route to destination"}
+}
+
 func inferHopAction(c domain.Component) domain.HopAction {
 	switch c.GetComponentType() {
 	case "SecurityGroup", "NACL":
@@ -182,7 +340,7 @@ func inferHopAction(c domain.Component) domain.HopAction {
 		return domain.HopActionRouted
 	case "ALB", "NLB", "CLB", "GWLB", "TargetGroup", "VPCLink":
 		return domain.HopActionForwarded
-	case "InternetGateway", "NATGateway", "VPNConnection", "DirectConnectGateway", "IPTarget", "LocalGateway", "CarrierGateway":
+	case "InternetGateway", "NATGateway", "VPNConnection", "TGWConnectAttachment", "DirectConnectGateway", "IPTarget", "LocalGateway", "CarrierGateway":
 		return domain.HopActionTerminal
 	default:
 		return domain.HopActionEntered
@@ -194,7 +352,11 @@ func inferLineage(source, target domain.Component) domain.HopLineage {
 	targetType := target.GetComponentType()
 
 	relationship := inferRelationship(sourceType, targetType)
-	return domain.NewHopLineage(source.GetID(), sourceType, relationship)
+	lineage := domain.NewHopLineage(source.GetID(), sourceType, relationship)
+	if mp, ok := source.(domain.MetadataProvider); ok {
+		lineage.SourceRegion = mp.GetRegion()
+	}
+	return lineage
 }
 
 func inferRelationship(sourceType, targetType string) string {
@@ -215,7 +377,7 @@ func inferRelationship(sourceType, targetType string) string {
 		}
 	case "RouteTable":
 		switch targetType {
-		case "InternetGateway", "NATGateway", "TransitGatewayAttachment", "VPCEndpoint", "VPCPeering", "VirtualPrivateGateway", "LocalGateway", "CarrierGateway":
+		case "InternetGateway", "EgressOnlyInternetGateway", "NATGateway", "TransitGatewayAttachment", "VPCEndpoint", "VPCPeering", "VirtualPrivateGateway", "LocalGateway", "CarrierGateway":
 			return "routes-via"
 		case "EC2Instance", "RDSInstance", "IPTarget", "NetworkInterface":
 			return "resolved-to"
@@ -236,7 +398,7 @@ func inferRelationship(sourceType, targetType string) string {
 	case "TransitGateway":
 		return "routes-via"
 	case "VPCPeering":
-		if targetType == "RouteTable" {
+		if targetType == "RouteTable" || targetType == "Subnet" {
 			return "peers-to"
 		}
 	case "VPCEndpoint", "GWLBEndpoint":
@@ -262,9 +424,34 @@ func inferRelationship(sourceType, targetType string) string {
 			return "located-in"
 		}
 	case "VirtualPrivateGateway":
-		if targetType == "VPNConnection" {
+		switch targetType {
+		case "VPNConnection":
+			return "connects-via"
+		case "RouteTable":
+			return "associated-with"
+		case "Subnet":
+			return "delivers-to"
+		}
+	case "VPNOnPrem":
+		if targetType == "VirtualPrivateGateway" {
 			return "connects-via"
 		}
+	case "Internet":
+		switch targetType {
+		case "InternetGateway", "EgressOnlyInternetGateway", "NATGateway":
+			return "enters-via"
+		}
+	case "EgressOnlyInternetGateway":
+		if targetType == "NetworkInterface" {
+			return "delivers-to"
+		}
+	case "InternetGateway":
+		switch targetType {
+		case "NetworkInterface":
+			return "delivers-to"
+		case "RouteTable":
+			return "associated-with"
+		}
 	case "DirectConnectOnPrem":
 		if targetType == "DirectConnectGateway" {
 			return "connects-via"
@@ -272,8 +459,15 @@ func inferRelationship(sourceType, targetType string) string {
 	case "SecurityGroup":
 		return "chains-to"
 	case "NACL":
-		if targetType == "RouteTable" {
+		switch targetType {
+		case "RouteTable":
 			return "precedes"
+		case "SecurityGroup":
+			return "precedes"
+		}
+	case "NetworkInterface":
+		if targetType == "NACL" {
+			return "filtered-by"
 		}
 	}
 	return "leads-to"
@@ -356,16 +550,134 @@ func filterVisited(hops []domain.Component, analyzerCtx domain.AnalyzerContext)
 }
 
 func IsDestinationReached(hops []domain.Component, destination domain.RoutingTarget, destinationID string) bool {
+	return reachedDestination(hops, destination, destinationID) != nil
+}
+
+func reachedDestination(hops []domain.Component, destination domain.RoutingTarget, destinationID string) domain.Component {
 	for _, hop := range hops {
 		if hop.GetID() == destinationID {
-			return true
+			return hop
 		}
 		hopTarget := hop.GetRoutingTarget()
 		if targetMatches(hopTarget, destination) {
-			return true
+			return hop
 		}
 	}
-	return false
+	return nil
+}
+
+// enterDestination walks the filters reached keeps on its own ingress, such as
+// an interface's NACL and security groups, checking the flow inbound. Each
+// interface is a separate way in, so the flow is admitted when any one of them
+// lets it through.
+func enterDestination(current, reached domain.Component, destination domain.RoutingTarget, path *pathContext, trace *domain.PathTrace, hop *domain.ComponentHop) domain.PathResult {
+	var ingressHops []domain.Component
+	if provider, ok := reached.(domain.IngressProvider); ok {
+		var err error
+		ingressHops, err = provider.GetIngressHops(path)
+		if err != nil {
+			return blockAt(trace, hop, reached, err)
+		}
+	}
+
+	if len(ingressHops) == 0 {
+		hop.Action = domain.HopActionTerminal
+		hop.Details = "destination reached"
+		trace.MarkSuccess()
+		return domain.SuccessResult{}
+	}
+
+	ingressTarget := destination
+	ingressTarget.Direction = "inbound"
+
+	return enterIngress(current, ingressHops, ingressTarget, path, trace, hop, "delivered to "+reached.GetID())
+}
+
+// enterIngress explores each of hops after previous, returning the first that
+// admits the flow or, failing that, the failure to report.
+func enterIngress(previous domain.Component, hops []domain.Component, target domain.RoutingTarget, path *pathContext, trace *domain.PathTrace, hop *domain.ComponentHop, details string) domain.PathResult {
+	type branchOutcome struct {
+		result domain.PathResult
+		trace  *domain.PathTrace
+	}
+	outcomes := make([]*branchOutcome, len(hops))
+	forEachBranch(path, len(hops), func(i int, branch *pathContext) bool {
+		branchTrace := trace.Clone()
+		result := traverseIngress(previous, hops[i], target, branch, branchTrace, details)
+		outcomes[i] = &branchOutcome{result: result, trace: branchTrace}
+		return !result.IsBlocked()
+	})
+
+	var reported *branchOutcome
+	for _, outcome := range outcomes {
+		if outcome == nil {
+			continue
+		}
+		if !outcome.result.IsBlocked() {
+			trace.Hops = outcome.trace.Hops
+			trace.Success = outcome.trace.Success
+			trace.BlockedAt = outcome.trace.BlockedAt
+			return outcome.result
+		}
+		if reported == nil || domain.VerdictOf(reported.result) == domain.VerdictBlocked || domain.VerdictOf(outcome.result) == domain.VerdictUnknown {
+			reported = outcome
+		}
+	}
+
+	if reported == nil {
+		err := path.Context().Err()
+		if err == nil {
+			err = &domain.BlockingError{ComponentID: previous.GetID(), Reason: "all paths blocked"}
+		}
+		return blockAt(trace, hop, previous, err)
+	}
+
+	trace.Hops = reported.trace.Hops
+	trace.Success = false
+	trace.BlockedAt = reported.trace.BlockedAt
+	return reported.result
+}
+
+// traverseIngress checks the flow at one ingress filter and follows it to the
+// next, under the same limits and loop detection as the rest of the path. The
+// flow is admitted once a filter has nothing further to check.
+func traverseIngress(previous, current domain.Component, target domain.RoutingTarget, path *pathContext, trace *domain.PathTrace, details string) domain.PathResult {
+	path = path.enter(current, target)
+
+	hop := domain.HopFromComponent(current, inferLineage(previous, current), inferHopAction(current), details)
+
+	if err := path.stopped(); err != nil {
+		trace.AddHop(hop)
+		return blockAt(trace, hop, current, err)
+	}
+
+	if evaluator, ok := current.(domain.RuleEvaluator); ok {
+		result := evaluator.EvaluateWithDetails(target, target.Direction)
+		hop.RuleEvaluations = result.Evaluations
+	}
+	trace.AddHop(hop)
+
+	nextHops, err := path.nextHops(current, target)
+	if err != nil {
+		return blockAt(trace, hop, current, err)
+	}
+	advise(current, target, path, hop)
+	target = translate(current, target, hop)
+	path.translated(target)
+
+	if len(nextHops) == 0 {
+		hop.Action = domain.HopActionTerminal
+		hop.Details = "destination reached"
+		trace.MarkSuccess()
+		return domain.SuccessResult{}
+	}
+
+	unvisited := filterVisited(nextHops, path)
+	if len(unvisited) == 0 {
+		return blockAt(trace, hop, current, &domain.BlockingError{ComponentID: current.GetID(), Reason: "ingress loops back on itself"})
+	}
+
+	return enterIngress(current, unvisited, target, path, trace, hop, "")
 }
 
 func targetMatches(hopTarget, destination domain.RoutingTarget) bool {
@@ -374,12 +686,13 @@ func targetMatches(hopTarget, destination domain.RoutingTarget) bool {
 			return false
 		}
 	}
-	if destination.Port != 0 {
-		if hopTarget.Port != destination.Port {
+	if destination.Port != 0 && hopTarget.Port != 0 {
+		low, high := destination.PortRange()
+		if hopTarget.Port < low || hopTarget.Port > high {
 			return false
 		}
 	}
-	if destination.Protocol != "" {
+	if destination.Protocol != "" && hopTarget.Protocol != "" {
 		if hopTarget.Protocol != destination.Protocol {
 			return false
 		}
@@ -404,27 +717,24 @@ func TestReachabilityAllPaths(ctx context.Context, source, destination domain.Co
 }
 
 func TestReachabilityAllPathsWithResolver(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver) domain.AllPathsResult {
-	if resolver == nil && accountCtx != nil {
-		resolver = resolverpkg.NewResolver(accountCtx)
-	}
+	return TestReachabilityAllPathsWithFlow(ctx, source, destination, domain.FlowSpec{}, accountCtx, resolver)
+}
 
-	destTarget := destination.GetRoutingTarget()
-	destTarget.Direction = "outbound"
-	destTarget.SourceIsPrivate = isPrivateIPStr(source.GetRoutingTarget().IP)
-	sourceTarget := source.GetRoutingTarget()
-	sourceTarget.Direction = "inbound"
-	sourceTarget.SourceIsPrivate = isPrivateIPStr(destTarget.IP)
+func TestReachabilityAllPathsWithFlow(ctx context.Context, source, destination domain.Component, flow domain.FlowSpec, accountCtx domain.AccountContext, resolver domain.DestinationResolver) domain.AllPathsResult {
+	return TestReachabilityAllPathsWithOptions(ctx, source, destination, accountCtx, resolver, domain.Options{Flow: flow, Strategy: domain.StrategyAllPaths})
+}
 
-	ctxWithResolver := &accountContextWithResolver{
-		AccountContext: accountCtx,
-		resolver:       resolver,
+func TestReachabilityAllPathsWithOptions(ctx context.Context, source, destination domain.Component, accountCtx domain.AccountContext, resolver domain.DestinationResolver, opts domain.Options) domain.AllPathsResult {
+	source = components.SourceFor(source, destination)
+	destTarget, sourceTarget, unaddressed := legTargets(source, destination, opts.Flow)
+	if unaddressed != nil {
+		blocked, trace := unaddressable(unaddressed)
+		return domain.AllPathsResult{Verdict: domain.VerdictOf(blocked), ForwardPaths: []*domain.PathTrace{trace}}
 	}
+	forwardAnalyzer, returnAnalyzer, resolver := newLegContexts(ctx, accountCtx, resolver, opts)
 
-	sourceAnalyzer := NewAnalyzerContext(ctx, ctxWithResolver)
-	forwardPaths := TraverseAllPaths(source, destTarget, destination.GetID(), sourceAnalyzer, resolver, domain.HopLineage{})
-
-	destAnalyzer := NewAnalyzerContext(ctx, ctxWithResolver)
-	returnPaths := TraverseAllPaths(destination, sourceTarget, source.GetID(), destAnalyzer, resolver, domain.HopLineage{})
+	forwardPaths := TraverseAllPaths(source, destTarget, destination.GetID(), forwardAnalyzer, resolver, domain.HopLineage{})
+	returnPaths := TraverseAllPaths(components.SourceFor(destination, source), sourceTarget, source.GetID(), returnAnalyzer, resolver, domain.HopLineage{})
 
 	successfulForward := 0
 	for _, p := range forwardPaths {
@@ -440,30 +750,42 @@ func TestReachabilityAllPathsWithResolver(ctx context.Context, source, destinati
 		}
 	}
 
+	truncated, reason := truncation(forwardAnalyzer, returnAnalyzer)
 	return domain.AllPathsResult{
+		Verdict: domain.CombineVerdicts(
+			domain.PathsVerdict(forwardPaths, truncated),
+			domain.PathsVerdict(returnPaths, truncated),
+		),
 		ForwardPaths:           forwardPaths,
 		ReturnPaths:            returnPaths,
 		SuccessfulForwardPaths: successfulForward,
 		SuccessfulReturnPaths:  successfulReturn,
 		HasReachablePath:       successfulForward > 0 && successfulReturn > 0,
+		Truncated:              truncated,
+		TruncatedReason:        reason,
 	}
 }
 
 func TraverseAllPaths(current domain.Component, destination domain.RoutingTarget, destinationID string, analyzerCtx domain.AnalyzerContext, resolver domain.DestinationResolver, lineage domain.HopLineage) []*domain.PathTrace {
 	trace := domain.NewPathTrace()
-	return traverseAllPathsRecursive(current, destination, destinationID, analyzerCtx, resolver, trace, lineage, make(map[string]bool))
+	return traverseAllPathsRecursive(current, destination, destinationID, newPathContext(analyzerCtx, destination), trace, lineage)
 }
 
-func traverseAllPathsRecursive(current domain.Component, destination domain.RoutingTarget, destinationID string, analyzerCtx domain.AnalyzerContext, resolver domain.DestinationResolver, trace *domain.PathTrace, lineage domain.HopLineage, visited map[string]bool) []*domain.PathTrace {
-	if visited[current.GetID()] {
+func traverseAllPathsRecursive(current domain.Component, destination domain.RoutingTarget, destinationID string, path *pathContext, trace *domain.PathTrace, lineage domain.HopLineage) []*domain.PathTrace {
+	if path.IsVisited(current) || !path.budget.hasRoom() {
 		return nil
 	}
-	visited[current.GetID()] = true
-	defer func() { visited[current.GetID()] = false }()
+	path = path.enter(current, destination)
 
 	action := inferHopAction(current)
 	hop := domain.HopFromComponent(current, lineage, action, "")
 
+	if err := path.stopped(); err != nil {
+		trace.AddHop(hop)
+		blockAt(trace, hop, current, err)
+		return path.collect(trace)
+	}
+
 	if evaluator, ok := current.(domain.RuleEvaluator); ok {
 		result := evaluator.EvaluateWithDetails(destination, destination.Direction)
 		hop.RuleEvaluations = result.Evaluations
@@ -471,27 +793,22 @@ func traverseAllPathsRecursive(current domain.Component, destination domain.Rout
 
 	trace.AddHop(hop)
 
-	nextHops, err := current.GetNextHops(destination, analyzerCtx)
+	nextHops, err := path.nextHops(current, destination)
 	if err != nil {
-		blockedTrace := trace.Clone()
-		blockedTrace.MarkBlocked(err.Error())
-		return []*domain.PathTrace{blockedTrace}
+		blockAt(trace, hop, current, err)
+		return path.collect(trace)
 	}
+	advise(current, destination, path, hop)
+	destination = translate(current, destination, hop)
+	path.translated(destination)
 
-	if IsDestinationReached(nextHops, destination, destinationID) {
-		successTrace := trace.Clone()
-		successTrace.LastHop().Action = domain.HopActionTerminal
-		successTrace.LastHop().Details = "destination reached"
-		successTrace.MarkSuccess()
-		return []*domain.PathTrace{successTrace}
+	if reached := reachedDestination(nextHops, destination, destinationID); reached != nil {
+		destinationTrace := trace.Clone()
+		enterDestination(current, reached, destination, path, destinationTrace, destinationTrace.LastHop())
+		return path.collect(destinationTrace)
 	}
 
-	var unvisitedHops []domain.Component
-	for _, hop := range nextHops {
-		if !visited[hop.GetID()] {
-			unvisitedHops = append(unvisitedHops, hop)
-		}
-	}
+	unvisitedHops := filterVisited(nextHops, path)
 
 	if len(unvisitedHops) == 0 {
 		if isTerminalComponent(current) && isExternalDestination(destination.IP) {
@@ -499,31 +816,35 @@ func traverseAllPathsRecursive(current domain.Component, destination domain.Rout
 			successTrace.LastHop().Action = domain.HopActionTerminal
 			successTrace.LastHop().Details = "external destination via terminal"
 			successTrace.MarkSuccess()
-			return []*domain.PathTrace{successTrace}
+			return path.collect(successTrace)
 		}
 		if isFilterComponent(current) {
 			successTrace := trace.Clone()
 			successTrace.LastHop().Details = "filter passed"
 			successTrace.MarkSuccess()
-			return []*domain.PathTrace{successTrace}
+			return path.collect(successTrace)
 		}
 		blockedTrace := trace.Clone()
 		blockedTrace.MarkBlocked("no route to destination")
-		return []*domain.PathTrace{blockedTrace}
+		return path.collect(blockedTrace)
 	}
 
+	branchPaths := make([][]*domain.PathTrace, len(unvisitedHops))
+	forEachBranch(path, len(unvisitedHops), func(i int, branch *pathContext) bool {
+		nextHop := unvisitedHops[i]
+		branchPaths[i] = traverseAllPathsRecursive(nextHop, destination, destinationID, branch, trace.Clone(), inferLineage(current, nextHop))
+		return false
+	})
+
 	var allPaths []*domain.PathTrace
-	for _, nextHop := range unvisitedHops {
-		nextLineage := inferLineage(current, nextHop)
-		branchTrace := trace.Clone()
-		branchPaths := traverseAllPathsRecursive(nextHop, destination, destinationID, analyzerCtx, resolver, branchTrace, nextLineage, visited)
-		allPaths = append(allPaths, branchPaths...)
+	for _, paths := range branchPaths {
+		allPaths = append(allPaths, paths...)
 	}
 
-	if len(allPaths) == 0 {
+	if len(allPaths) == 0 && path.budget.hasRoom() {
 		blockedTrace := trace.Clone()
 		blockedTrace.MarkBlocked("all paths blocked")
-		return []*domain.PathTrace{blockedTrace}
+		return path.collect(blockedTrace)
 	}
 
 	return allPaths
@@ -3,7 +3,10 @@ package analyzer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -42,7 +45,7 @@ func (t *testAccountContext) AssumeRole(accountID string) (domain.AWSCredentials
 	return domain.AWSCredentials{}, nil
 }
 
-func (t *testAccountContext) GetClient(accountID string) (domain.AWSClient, error) {
+func (t *testAccountContext) GetClient(accountID, region string) (domain.AWSClient, error) {
 	return nil, nil
 }
 
@@ -645,3 +648,1002 @@ func TestAllPathsResult_GetBlockedPaths(t *testing.T) {
 		t.Errorf("expected 2 blocked paths, got %d", len(blocked))
 	}
 }
+
+func TestLegTargets_AppliesFlow(t *testing.T) {
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10", Protocol: "tcp"}}
+	dest := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20", Protocol: "tcp"}}
+
+	flow := domain.FlowSpec{Protocol: "tcp", FromPort: 5432, SourcePort: 40000}
+	destTarget, sourceTarget, _ := legTargets(source, dest, flow)
+
+	if destTarget.Port != 5432 || destTarget.SourcePort != 40000 {
+		t.Errorf("expected forward leg 40000->5432, got %d->%d", destTarget.SourcePort, destTarget.Port)
+	}
+	if destTarget.Direction != "outbound" {
+		t.Errorf("expected outbound forward leg, got %s", destTarget.Direction)
+	}
+	if sourceTarget.Port != 40000 || sourceTarget.SourcePort != 5432 {
+		t.Errorf("expected return leg 5432->40000, got %d->%d", sourceTarget.SourcePort, sourceTarget.Port)
+	}
+}
+
+func TestLegTargets_CarriesSourceIP(t *testing.T) {
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}
+	dest := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20", Port: 443, Protocol: "tcp"}}
+
+	destTarget, sourceTarget, _ := legTargets(source, dest, domain.FlowSpec{})
+
+	if destTarget.SourceIP != "10.0.1.10" || destTarget.IP != "10.0.2.20" {
+		t.Errorf("expected forward leg 10.0.1.10 -> 10.0.2.20, got %s -> %s", destTarget.SourceIP, destTarget.IP)
+	}
+	if sourceTarget.SourceIP != "10.0.2.20" || sourceTarget.IP != "10.0.1.10" {
+		t.Errorf("expected return leg 10.0.2.20 -> 10.0.1.10, got %s -> %s", sourceTarget.SourceIP, sourceTarget.IP)
+	}
+}
+
+func TestLegTargets_ZeroFlowKeepsRoutingTarget(t *testing.T) {
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}
+	dest := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20", Port: 6379, Protocol: "tcp"}}
+
+	destTarget, _, _ := legTargets(source, dest, domain.FlowSpec{})
+
+	if destTarget.Port != 6379 || destTarget.Protocol != "tcp" {
+		t.Errorf("expected destination routing target to be kept, got %d/%s", destTarget.Port, destTarget.Protocol)
+	}
+}
+
+func TestLegTargets_ReturnLegIsReplyOnEphemeralPorts(t *testing.T) {
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}
+	dest := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20", Port: 5432, Protocol: "tcp"}}
+
+	_, sourceTarget, _ := legTargets(source, dest, domain.FlowSpec{})
+
+	if !sourceTarget.Reply {
+		t.Error("expected return leg to be a reply")
+	}
+	if sourceTarget.Port != 1024 || sourceTarget.PortTo != 65535 {
+		t.Errorf("expected default ephemeral range 1024-65535, got %d-%d", sourceTarget.Port, sourceTarget.PortTo)
+	}
+	if sourceTarget.SourcePort != 5432 || sourceTarget.Protocol != "tcp" {
+		t.Errorf("expected reply from 5432/tcp, got %d/%s", sourceTarget.SourcePort, sourceTarget.Protocol)
+	}
+
+	_, sourceTarget, _ = legTargets(source, dest, domain.FlowSpec{Ephemeral: domain.EphemeralLinux})
+	if sourceTarget.Port != 32768 || sourceTarget.PortTo != 60999 {
+		t.Errorf("expected Linux ephemeral range, got %d-%d", sourceTarget.Port, sourceTarget.PortTo)
+	}
+}
+
+type dualStackComponent struct {
+	testComponent
+	ipv6 []string
+}
+
+func (c *dualStackComponent) GetIPv6Addresses() []string {
+	return c.ipv6
+}
+
+func TestLegTargets_IPv6UsesIPv6Addresses(t *testing.T) {
+	source := &dualStackComponent{testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}, []string{"2600:1f18::10"}}
+	dest := &dualStackComponent{testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20", Port: 443, Protocol: "tcp"}}, []string{"2600:1f18::20"}}
+
+	destTarget, sourceTarget, unaddressed := legTargets(source, dest, domain.FlowSpec{Family: domain.AddressFamilyIPv6})
+	if unaddressed != nil {
+		t.Fatalf("expected both endpoints to be addressable, got %s", unaddressed.GetID())
+	}
+	if destTarget.SourceIP != "2600:1f18::10" || destTarget.IP != "2600:1f18::20" {
+		t.Errorf("expected forward leg 2600:1f18::10 -> 2600:1f18::20, got %s -> %s", destTarget.SourceIP, destTarget.IP)
+	}
+	if sourceTarget.IP != "2600:1f18::10" {
+		t.Errorf("expected return leg to 2600:1f18::10, got %s", sourceTarget.IP)
+	}
+
+	external := &testComponent{id: "external", target: domain.RoutingTarget{IP: "2600:1f18:ffff::1"}}
+	destTarget, _, _ = legTargets(source, external, domain.FlowSpec{})
+	if destTarget.SourceIP != "2600:1f18::10" {
+		t.Errorf("expected an IPv6 destination to make the flow IPv6, got source %s", destTarget.SourceIP)
+	}
+}
+
+func TestReachability_BlocksEndpointWithoutAddressInFamily(t *testing.T) {
+	source := &dualStackComponent{testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}, nil}
+	dest := &dualStackComponent{testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20"}}, []string{"2600:1f18::20"}}
+	source.nextHops = []domain.Component{dest}
+	dest.nextHops = []domain.Component{source}
+
+	result := TestReachabilityWithOptions(context.Background(), source, dest, &testAccountContext{}, nil, domain.Options{Flow: domain.FlowSpec{Family: domain.AddressFamilyIPv6}})
+	if result.Verdict != domain.VerdictBlocked {
+		t.Fatalf("expected blocked, got %s", result.Verdict)
+	}
+	if result.ForwardPath.BlockedAt == nil || result.ForwardPath.BlockedAt.ComponentID != "source" {
+		t.Errorf("expected the flow to stop at the source, got %+v", result.ForwardPath.BlockedAt)
+	}
+
+	dual := TestDualStack(context.Background(), source, dest, &testAccountContext{}, nil, domain.Options{})
+	if dual.IPv4.Verdict != domain.VerdictReachable || dual.IPv6.Verdict != domain.VerdictBlocked {
+		t.Errorf("expected reachable over IPv4 and blocked over IPv6, got %s and %s", dual.IPv4.Verdict, dual.IPv6.Verdict)
+	}
+}
+
+func TestReachability_UnknownIPv6AddressesAreUndecided(t *testing.T) {
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.10"}}
+	dest := &dualStackComponent{testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.2.20"}}, []string{"2600:1f18::20"}}
+
+	opts := domain.Options{Flow: domain.FlowSpec{Family: domain.AddressFamilyIPv6}}
+	result := TestReachabilityWithOptions(context.Background(), source, dest, &testAccountContext{}, nil, opts)
+	if result.Verdict != domain.VerdictUnknown {
+		t.Errorf("expected unknown for a source that cannot report IPv6 addresses, got %s", result.Verdict)
+	}
+
+	opts.Strategy = domain.StrategyAllPaths
+	allPaths := TestReachabilityAllPathsWithOptions(context.Background(), source, dest, &testAccountContext{}, nil, opts)
+	if allPaths.Verdict != domain.VerdictUnknown {
+		t.Errorf("expected unknown across all paths, got %s", allPaths.Verdict)
+	}
+}
+
+type testIngressComponent struct {
+	testComponent
+	ingress []domain.Component
+}
+
+func (t *testIngressComponent) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return t.ingress, nil
+}
+
+func TestTraversePathWithTrace_DestinationIngressBlocked(t *testing.T) {
+	sg := &testComponent{
+		id:      "sg-db",
+		nextErr: &domain.BlockingError{ComponentID: "sg-db", Reason: "no inbound rule allows 10.0.1.10"},
+	}
+	nacl := &testComponent{id: "nacl-db", nextHops: []domain.Component{sg}}
+	eni := &testComponent{id: "eni-db", nextHops: []domain.Component{nacl}}
+
+	dest := &testIngressComponent{
+		testComponent: testComponent{id: "db", target: domain.RoutingTarget{IP: "10.0.2.20", Port: 5432, Protocol: "tcp"}},
+		ingress:       []domain.Component{eni},
+	}
+	source := &testComponent{id: "app", nextHops: []domain.Component{dest}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	trace := domain.NewPathTrace()
+	target := domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "10.0.2.20", Port: 5432, Protocol: "tcp", Direction: "outbound"}
+
+	result := TraversePathWithTrace(source, target, "db", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if !result.IsBlocked() {
+		t.Fatal("expected closed security group to block the destination")
+	}
+	if trace.BlockedAt == nil || trace.BlockedAt.ComponentID != "sg-db" {
+		t.Fatalf("expected block at sg-db, got %+v", trace.BlockedAt)
+	}
+
+	var ids []string
+	for _, hop := range trace.Hops {
+		ids = append(ids, hop.ComponentID)
+	}
+	want := []string{"app", "eni-db", "nacl-db", "sg-db"}
+	if len(ids) != len(want) {
+		t.Fatalf("expected hops %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("hop %d: expected %s, got %s", i, want[i], ids[i])
+		}
+	}
+}
+
+func TestTraversePathWithTrace_DestinationIngressAllowed(t *testing.T) {
+	sg := &testComponent{id: "sg-db"}
+	eni := &testComponent{id: "eni-db", nextHops: []domain.Component{sg}}
+	dest := &testIngressComponent{
+		testComponent: testComponent{id: "db"},
+		ingress:       []domain.Component{eni},
+	}
+	source := &testComponent{id: "app", nextHops: []domain.Component{dest}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	trace := domain.NewPathTrace()
+
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "10.0.2.20"}, "db", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected success, got %s", result.GetBlockingReason())
+	}
+	last := trace.LastHop()
+	if last.ComponentID != "sg-db" || last.Action != domain.HopActionTerminal {
+		t.Errorf("expected terminal hop at sg-db, got %s (%s)", last.ComponentID, last.Action)
+	}
+}
+
+func TestTraversePathWithTrace_DestinationIngressAnyInterfaceAdmits(t *testing.T) {
+	closed := &testComponent{
+		id:      "sg-closed",
+		nextErr: &domain.BlockingError{ComponentID: "sg-closed", Reason: "no inbound rule allows 10.0.1.10"},
+	}
+	first := &testComponent{id: "eni-first", nextHops: []domain.Component{closed}}
+	second := &testComponent{id: "eni-second", nextHops: []domain.Component{&testComponent{id: "sg-open"}}}
+	dest := &testIngressComponent{
+		testComponent: testComponent{id: "db"},
+		ingress:       []domain.Component{first, second},
+	}
+	source := &testComponent{id: "app", nextHops: []domain.Component{dest}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	trace := domain.NewPathTrace()
+
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "10.0.2.20"}, "db", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected the second interface to admit the flow, got %s", result.GetBlockingReason())
+	}
+	if last := trace.LastHop(); last.ComponentID != "sg-open" {
+		t.Errorf("expected the path to end at sg-open, got %s", last.ComponentID)
+	}
+}
+
+func TestTraversePathWithTrace_DestinationIngressLoopTerminates(t *testing.T) {
+	a := &testComponent{id: "ingress-a"}
+	b := &testComponent{id: "ingress-b", nextHops: []domain.Component{a}}
+	a.nextHops = []domain.Component{b}
+	dest := &testIngressComponent{
+		testComponent: testComponent{id: "db"},
+		ingress:       []domain.Component{a},
+	}
+	source := &testComponent{id: "app", nextHops: []domain.Component{dest}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	analyzerCtx := NewAnalyzerContext(ctx, &testAccountContext{})
+	trace := domain.NewPathTrace()
+
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "10.0.2.20"}, "db", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if !result.IsBlocked() {
+		t.Fatal("expected a looping ingress chain to block")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected loop detection to stop the walk before the deadline")
+	}
+	if trace.BlockedAt == nil || trace.BlockedAt.ComponentID != "ingress-b" {
+		t.Errorf("expected block at ingress-b, got %+v", trace.BlockedAt)
+	}
+}
+
+type countingComponent struct {
+	testComponent
+	calls int
+}
+
+func (c *countingComponent) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	c.calls++
+	return c.testComponent.GetNextHops(dest, analyzerCtx)
+}
+
+func TestTraversePathWithTrace_SharedHubReportsItsOwnBlock(t *testing.T) {
+	hub := &countingComponent{testComponent: testComponent{
+		id:      "tgw-hub",
+		nextErr: &domain.BlockingError{ComponentID: "tgw-hub", Reason: "blackhole route"},
+	}}
+	spokeA := &testComponent{id: "spoke-a", nextHops: []domain.Component{hub}}
+	spokeB := &testComponent{id: "spoke-b", nextHops: []domain.Component{hub}}
+	source := &testComponent{id: "source", nextHops: []domain.Component{spokeA, spokeB}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	trace := domain.NewPathTrace()
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	result := TraversePathWithTrace(source, dest, "dest", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if !result.IsBlocked() {
+		t.Fatal("expected blocked result")
+	}
+	if result.GetBlockingReason() != "Blocked at tgw-hub: blackhole route" {
+		t.Errorf("expected the hub's reason from the second spoke, got %q", result.GetBlockingReason())
+	}
+	if hub.calls != 1 {
+		t.Errorf("expected hub to be expanded once, got %d", hub.calls)
+	}
+
+	var ids []string
+	for _, hop := range trace.Hops {
+		ids = append(ids, hop.ComponentID)
+	}
+	want := []string{"source", "spoke-b", "tgw-hub"}
+	if len(ids) != len(want) {
+		t.Fatalf("expected hops %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected hops %v, got %v", want, ids)
+		}
+	}
+	if trace.BlockedAt == nil || trace.BlockedAt.ComponentID != "tgw-hub" {
+		t.Fatalf("expected block at tgw-hub, got %+v", trace.BlockedAt)
+	}
+	if trace.BlockedAt.SourceID != "spoke-b" {
+		t.Errorf("expected replayed hop to be parented under spoke-b, got %q", trace.BlockedAt.SourceID)
+	}
+}
+
+func TestTraverseAllPaths_SharedHubOnEveryBranch(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	hub := &countingComponent{testComponent: testComponent{id: "tgw-hub", nextHops: []domain.Component{destComponent}}}
+	spokeA := &testComponent{id: "spoke-a", nextHops: []domain.Component{hub}}
+	spokeB := &testComponent{id: "spoke-b", nextHops: []domain.Component{hub}}
+	source := &testComponent{id: "source", nextHops: []domain.Component{spokeA, spokeB}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	paths := TraverseAllPaths(source, dest, "dest", analyzerCtx, nil, domain.HopLineage{})
+
+	if len(paths) != 2 {
+		t.Fatalf("expected a path through each spoke, got %d", len(paths))
+	}
+	for i, p := range paths {
+		if !p.Success {
+			t.Errorf("expected path %d to succeed", i)
+		}
+	}
+	if hub.calls != 1 {
+		t.Errorf("expected hub to be expanded once, got %d", hub.calls)
+	}
+}
+
+type slowComponent struct {
+	testComponent
+	delay time.Duration
+}
+
+func (s *slowComponent) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-analyzerCtx.Context().Done():
+		return nil, analyzerCtx.Context().Err()
+	}
+	return s.testComponent.GetNextHops(dest, analyzerCtx)
+}
+
+func TestTraversePathWithTrace_ParallelKeepsBranchOrder(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	slow := &slowComponent{
+		testComponent: testComponent{id: "slow", nextHops: []domain.Component{destComponent}},
+		delay:         20 * time.Millisecond,
+	}
+	fast := &testComponent{id: "fast", nextHops: []domain.Component{destComponent}}
+	source := &testComponent{id: "source", nextHops: []domain.Component{slow, fast}}
+
+	analyzerCtx := newAnalyzerContext(context.Background(), &testAccountContext{}, newMemoTable(), newBranchLimit(4))
+	trace := domain.NewPathTrace()
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	result := TraversePathWithTrace(source, dest, "dest", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected success, got: %s", result.GetBlockingReason())
+	}
+	if len(trace.Hops) < 2 || trace.Hops[1].ComponentID != "slow" {
+		t.Errorf("expected the first branch to be reported regardless of finish order, got %+v", trace.Hops)
+	}
+}
+
+func TestTraverseAllPaths_ParallelKeepsBranchOrder(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	var branches []domain.Component
+	for i := 0; i < 6; i++ {
+		branches = append(branches, &slowComponent{
+			testComponent: testComponent{id: fmt.Sprintf("branch-%d", i), nextHops: []domain.Component{destComponent}},
+			delay:         time.Duration(6-i) * time.Millisecond,
+		})
+	}
+	source := &testComponent{id: "source", nextHops: branches}
+
+	analyzerCtx := newAnalyzerContext(context.Background(), &testAccountContext{}, newMemoTable(), newBranchLimit(3))
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	paths := TraverseAllPaths(source, dest, "dest", analyzerCtx, nil, domain.HopLineage{})
+
+	if len(paths) != len(branches) {
+		t.Fatalf("expected %d paths, got %d", len(branches), len(paths))
+	}
+	for i, p := range paths {
+		if want := fmt.Sprintf("branch-%d", i); p.Hops[1].ComponentID != want {
+			t.Errorf("path %d: expected %s, got %s", i, want, p.Hops[1].ComponentID)
+		}
+	}
+}
+
+func TestTraversePath_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	source := &testComponent{id: "source", nextHops: []domain.Component{destComponent}}
+
+	analyzerCtx := newAnalyzerContext(ctx, &testAccountContext{}, newMemoTable(), newBranchLimit(4))
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	result := TraversePath(source, dest, "dest", analyzerCtx, nil)
+
+	if !result.IsBlocked() {
+		t.Fatal("expected a cancelled analysis not to report success")
+	}
+	if blocked, ok := result.(domain.BlockedResult); !ok || !errors.Is(blocked.Reason, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", result.GetBlockingReason())
+	}
+}
+
+func chainTo(dest domain.Component, length int) domain.Component {
+	next := dest
+	for i := length; i > 0; i-- {
+		next = &testComponent{id: fmt.Sprintf("hop-%d", i), nextHops: []domain.Component{next}}
+	}
+	return next
+}
+
+func TestAnalyze_MaxDepthTruncates(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	source := &testComponent{
+		id:       "source",
+		target:   domain.RoutingTarget{IP: "10.0.1.50"},
+		nextHops: []domain.Component{chainTo(destComponent, 5)},
+	}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := Analyze(context.Background(), source, destComponent, &testAccountContext{}, nil, domain.Options{MaxDepth: 3})
+
+	if result.HasReachablePath {
+		t.Error("expected the depth limit to stop the forward path")
+	}
+	if !result.Truncated {
+		t.Fatal("expected result to be flagged as truncated")
+	}
+	if result.TruncatedReason != "maximum depth of 3 hops reached" {
+		t.Errorf("unexpected reason %q", result.TruncatedReason)
+	}
+	if len(result.ForwardPaths) != 1 || result.ForwardPaths[0].Depth() != 4 {
+		t.Errorf("expected a partial forward trace of 4 hops, got %+v", result.ForwardPaths)
+	}
+}
+
+func TestAnalyze_MaxPathsTruncates(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	var branches []domain.Component
+	for i := 0; i < 5; i++ {
+		branches = append(branches, &testComponent{id: fmt.Sprintf("branch-%d", i), nextHops: []domain.Component{destComponent}})
+	}
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.50"}, nextHops: branches}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := Analyze(context.Background(), source, destComponent, &testAccountContext{}, nil, domain.Options{
+		Strategy:    domain.StrategyAllPaths,
+		MaxPaths:    2,
+		Concurrency: 1,
+	})
+
+	if len(result.ForwardPaths) != 2 {
+		t.Fatalf("expected 2 forward paths, got %d", len(result.ForwardPaths))
+	}
+	if !result.Truncated || result.TruncatedReason != "maximum of 2 paths reached" {
+		t.Errorf("expected truncation by MaxPaths, got %v %q", result.Truncated, result.TruncatedReason)
+	}
+	if !result.HasReachablePath {
+		t.Error("paths found before the limit should still count")
+	}
+}
+
+func TestAnalyze_PerCallTimeout(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	slow := &slowComponent{
+		testComponent: testComponent{id: "slow", nextHops: []domain.Component{destComponent}},
+		delay:         time.Second,
+	}
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.50"}, nextHops: []domain.Component{slow}}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := Analyze(context.Background(), source, destComponent, &testAccountContext{}, nil, domain.Options{PerCallTimeout: 10 * time.Millisecond})
+
+	if !result.Truncated {
+		t.Fatal("expected a timed out lookup to truncate the result")
+	}
+	if result.TruncatedReason != "slow did not respond within 10ms" {
+		t.Errorf("unexpected reason %q", result.TruncatedReason)
+	}
+	blockedAt := result.ForwardPaths[0].BlockedAt
+	if blockedAt == nil || blockedAt.ComponentID != "slow" {
+		t.Errorf("expected the forward path to stop at slow, got %+v", blockedAt)
+	}
+}
+
+func TestAnalyze_FirstPathWithoutLimits(t *testing.T) {
+	destComponent := &testComponent{
+		id:     "dest",
+		target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"},
+	}
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.50"}, nextHops: []domain.Component{destComponent}}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := Analyze(context.Background(), source, destComponent, &testAccountContext{}, nil, domain.Options{})
+
+	if !result.HasReachablePath || result.Truncated {
+		t.Errorf("expected an untruncated reachable result, got %+v", result)
+	}
+	if result.SuccessfulForwardPaths != 1 || result.SuccessfulReturnPaths != 1 {
+		t.Errorf("expected one successful path per direction, got %d/%d", result.SuccessfulForwardPaths, result.SuccessfulReturnPaths)
+	}
+}
+
+type testAPIError struct {
+	code string
+}
+
+func (e *testAPIError) Error() string     { return "api error " + e.code }
+func (e *testAPIError) ErrorCode() string { return e.code }
+
+func TestTestReachability_VerdictBlockedByConfiguration(t *testing.T) {
+	destComponent := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}}
+	sg := &testComponent{id: "sg-1", nextErr: &domain.BlockingError{ComponentID: "sg-1", Reason: "no outbound rule allows 10.0.1.100"}}
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.50"}, nextHops: []domain.Component{sg}}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := TestReachability(context.Background(), source, destComponent, &testAccountContext{})
+
+	if result.Verdict != domain.VerdictBlocked {
+		t.Errorf("expected blocked verdict, got %s", result.Verdict)
+	}
+	if result.ForwardPath.BlockedAt.Error != nil {
+		t.Errorf("configuration blocks should not attach an error, got %v", result.ForwardPath.BlockedAt.Error)
+	}
+}
+
+func TestTestReachability_VerdictUnknownOnAccessDenied(t *testing.T) {
+	destComponent := &testComponent{id: "dest", target: domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}}
+	sg := &testComponent{id: "sg-1", nextErr: fmt.Errorf("describe security group sg-1: %w", &testAPIError{code: "UnauthorizedOperation"})}
+	source := &testComponent{id: "source", target: domain.RoutingTarget{IP: "10.0.1.50"}, nextHops: []domain.Component{sg}}
+	destComponent.nextHops = []domain.Component{source}
+
+	result := TestReachability(context.Background(), source, destComponent, &testAccountContext{})
+
+	if result.Verdict != domain.VerdictUnknown {
+		t.Errorf("expected unknown verdict, got %s", result.Verdict)
+	}
+	var denied *domain.AccessDeniedError
+	if !errors.As(result.ForwardPath.BlockedAt.Error, &denied) || denied.Code != "UnauthorizedOperation" {
+		t.Errorf("expected access denied error on the hop, got %v", result.ForwardPath.BlockedAt.Error)
+	}
+}
+
+func TestTraversePathWithTrace_UndecidedBranchWinsOverBlock(t *testing.T) {
+	blocked := &testComponent{id: "attachment-a", nextErr: &domain.BlockingError{ComponentID: "attachment-a", Reason: "blackhole"}}
+	throttled := &testComponent{id: "attachment-b", nextErr: &testAPIError{code: "RequestLimitExceeded"}}
+	later := &testComponent{id: "attachment-c", nextErr: &domain.BlockingError{ComponentID: "attachment-c", Reason: "blackhole"}}
+	source := &testComponent{id: "tgw", nextHops: []domain.Component{blocked, throttled, later}}
+
+	analyzerCtx := NewAnalyzerContext(context.Background(), &testAccountContext{})
+	trace := domain.NewPathTrace()
+	dest := domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}
+
+	result := TraversePathWithTrace(source, dest, "dest", analyzerCtx, nil, trace, domain.HopLineage{})
+
+	if domain.VerdictOf(result) != domain.VerdictUnknown {
+		t.Errorf("expected an unknown verdict while a branch could not be analyzed, got %s", domain.VerdictOf(result))
+	}
+	var transient *domain.TransientError
+	if trace.BlockedAt == nil || !errors.As(trace.BlockedAt.Error, &transient) {
+		t.Errorf("expected the throttled branch to be reported, got %+v", trace.BlockedAt)
+	}
+}
+
+func TestTestReachabilityMatrix_CellsAndSummary(t *testing.T) {
+	dest := &testComponent{id: "db", target: domain.RoutingTarget{IP: "10.0.2.10", Port: 5432, Protocol: "tcp"}}
+	open := &testComponent{id: "app-a", target: domain.RoutingTarget{IP: "10.0.1.10"}, nextHops: []domain.Component{dest}}
+	closed := &testComponent{id: "app-b", target: domain.RoutingTarget{IP: "10.0.1.20"}, nextErr: &domain.BlockingError{ComponentID: "app-b", Reason: "no outbound rule"}}
+	dest.nextHops = []domain.Component{open, closed}
+
+	flows := []domain.FlowSpec{{Protocol: "tcp", FromPort: 5432}, {Protocol: "tcp", FromPort: 6432}}
+	result := TestReachabilityMatrix(context.Background(), []domain.Component{open, closed, nil}, []domain.Component{dest}, flows, &testAccountContext{}, nil, domain.Options{Concurrency: 4})
+
+	if len(result.Cells) != 6 {
+		t.Fatalf("expected 6 cells, got %d", len(result.Cells))
+	}
+	for i, cell := range result.Cells {
+		if got := result.Cell(cell.SourceIndex, cell.DestinationIndex, cell.FlowIndex); got != &result.Cells[i] {
+			t.Errorf("cell %d is not addressed by its indexes", i)
+		}
+	}
+
+	if cell := result.Cell(0, 0, 1); cell.Result.Verdict != domain.VerdictReachable || cell.Flow.FromPort != 6432 {
+		t.Errorf("expected app-a to reach db on 6432, got %s on %d", cell.Result.Verdict, cell.Flow.FromPort)
+	}
+	if cell := result.Cell(1, 0, 0); cell.Result.Verdict != domain.VerdictBlocked {
+		t.Errorf("expected app-b to be blocked, got %s", cell.Result.Verdict)
+	}
+	if cell := result.Cell(2, 0, 0); cell.Result.ForwardPath != nil {
+		t.Error("expected no analysis for an unresolved source")
+	}
+	if result.Cell(3, 0, 0) != nil {
+		t.Error("expected nil for an out of range cell")
+	}
+
+	want := domain.MatrixSummary{Cells: 6, Reachable: 2, Blocked: 2, Unknown: 2}
+	if result.Summary != want {
+		t.Errorf("expected summary %+v, got %+v", want, result.Summary)
+	}
+}
+
+func TestTestReachabilityMatrix_SharesSubpathsAcrossCells(t *testing.T) {
+	dest := &testComponent{id: "db", target: domain.RoutingTarget{IP: "10.0.2.10", Port: 5432, Protocol: "tcp"}}
+	subnet := &countingComponent{testComponent: testComponent{id: "subnet-shared", nextHops: []domain.Component{dest}}}
+	source := &testComponent{id: "app", target: domain.RoutingTarget{IP: "10.0.1.10"}, nextHops: []domain.Component{subnet}}
+	dest.nextHops = []domain.Component{source}
+
+	result := TestReachabilityMatrix(context.Background(), []domain.Component{source, source}, []domain.Component{dest}, nil, &testAccountContext{}, nil, domain.Options{})
+
+	if result.Summary.Reachable != 2 {
+		t.Fatalf("expected both cells reachable, got %+v", result.Summary)
+	}
+	if calls := subnet.calls; calls != 1 {
+		t.Errorf("expected the shared subnet to be expanded once, got %d", calls)
+	}
+}
+
+type sourceAgnosticComponent struct {
+	countingComponent
+}
+
+func (c *sourceAgnosticComponent) IsSourceAgnostic() bool {
+	return true
+}
+
+func TestTestReachabilityMatrix_SharesSourceAgnosticHopsAcrossSources(t *testing.T) {
+	dest := &testComponent{id: "db", target: domain.RoutingTarget{IP: "10.0.2.10", Port: 5432, Protocol: "tcp"}}
+	routeTable := &sourceAgnosticComponent{countingComponent{testComponent: testComponent{id: "rtb-shared", nextHops: []domain.Component{dest}}}}
+	filter := &countingComponent{testComponent: testComponent{id: "sg-shared", nextHops: []domain.Component{routeTable}}}
+	first := &testComponent{id: "app-1", target: domain.RoutingTarget{IP: "10.0.1.10"}, nextHops: []domain.Component{filter}}
+	second := &testComponent{id: "app-2", target: domain.RoutingTarget{IP: "10.0.1.11"}, nextHops: []domain.Component{filter}}
+	dest.nextHops = []domain.Component{first, second}
+
+	result := TestReachabilityMatrix(context.Background(), []domain.Component{first, second}, []domain.Component{dest}, nil, &testAccountContext{}, nil, domain.Options{Flow: domain.FlowSpec{Protocol: "tcp", FromPort: 5432}, Concurrency: 1})
+
+	if result.Summary.Reachable != 2 {
+		t.Fatalf("expected both cells reachable, got %+v", result.Summary)
+	}
+	if calls := routeTable.calls; calls != 1 {
+		t.Errorf("expected the source-agnostic route table to be expanded once, got %d", calls)
+	}
+	if calls := filter.calls; calls != 2 {
+		t.Errorf("expected the filter to be expanded once per source, got %d", calls)
+	}
+}
+
+func TestFindSources_KeepsReachableAndUndecided(t *testing.T) {
+	dest := &testComponent{id: "db", target: domain.RoutingTarget{IP: "10.0.2.10", Port: 5432, Protocol: "tcp"}}
+	open := &testComponent{id: "app", target: domain.RoutingTarget{IP: "10.0.1.10"}, nextHops: []domain.Component{dest}}
+	closed := &testComponent{id: "web", target: domain.RoutingTarget{IP: "10.0.1.20"}, nextErr: &domain.BlockingError{ComponentID: "web", Reason: "no outbound rule"}}
+	denied := &testComponent{id: "batch", target: domain.RoutingTarget{IP: "10.0.1.30"}, nextErr: &testAPIError{code: "UnauthorizedOperation"}}
+	dest.nextHops = []domain.Component{open, closed, denied}
+
+	opts := domain.Options{Concurrency: 4}
+	result := newSharedState(&testAccountContext{}, nil, opts).findSources(context.Background(), dest, []domain.Component{open, closed, denied}, opts)
+
+	if result.Candidates != 3 {
+		t.Errorf("expected 3 candidates, got %d", result.Candidates)
+	}
+	if len(result.Sources) != 1 || result.Sources[0].SourceID != "app" || result.Sources[0].SourceIP != "10.0.1.10" {
+		t.Fatalf("expected app as the only source, got %+v", result.Sources)
+	}
+	if path := result.Sources[0].Path; path == nil || !path.Success || path.Hops[0].ComponentID != "app" {
+		t.Errorf("expected the source to carry its path, got %+v", path)
+	}
+	if len(result.Undecided) != 1 || result.Undecided[0].SourceID != "batch" {
+		t.Errorf("expected batch to be undecided, got %+v", result.Undecided)
+	}
+}
+
+func TestMergeExposedPorts_JoinsAdjacentRanges(t *testing.T) {
+	v4 := internetFamilies[0]
+	v6 := internetFamilies[1]
+	cells := []*exposureCell{
+		{family: v4, flow: domain.FlowSpec{Protocol: "tcp", FromPort: 80, ToPort: 99}},
+		{family: v4, flow: domain.FlowSpec{Protocol: "tcp", FromPort: 100, ToPort: 443}},
+		{family: v4, flow: domain.FlowSpec{Protocol: "tcp", FromPort: 8080}},
+		{family: v4, flow: domain.FlowSpec{Protocol: "udp", FromPort: 8081}},
+		{family: v6, flow: domain.FlowSpec{Protocol: "udp", FromPort: 8082}},
+	}
+
+	var ports []domain.ExposedPorts
+	for _, cell := range cells {
+		ports = mergeExposedPorts(ports, cell)
+	}
+
+	want := []domain.ExposedPorts{
+		{Protocol: "tcp", FromPort: 80, ToPort: 443, Source: "0.0.0.0/0"},
+		{Protocol: "tcp", FromPort: 8080, ToPort: 8080, Source: "0.0.0.0/0"},
+		{Protocol: "udp", FromPort: 8081, ToPort: 8081, Source: "0.0.0.0/0"},
+		{Protocol: "udp", FromPort: 8082, ToPort: 8082, Source: "::/0"},
+	}
+	if len(ports) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, ports)
+	}
+	for i := range want {
+		if ports[i] != want[i] {
+			t.Errorf("range %d: expected %+v, got %+v", i, want[i], ports[i])
+		}
+	}
+}
+
+// exposureClient serves the lookups FindInternetExposure makes before it
+// traverses anything. Other methods are not implemented.
+type exposureClient struct {
+	domain.AWSClient
+	enis   []domain.ENIData
+	apiErr error
+}
+
+func (c *exposureClient) GetENIsByVPC(ctx context.Context, vpcID string) ([]domain.ENIData, error) {
+	return c.enis, nil
+}
+
+func (c *exposureClient) GetEC2Instance(ctx context.Context, instanceID string) (*domain.EC2InstanceData, error) {
+	return nil, fmt.Errorf("describe instance %s: throttled", instanceID)
+}
+
+func (c *exposureClient) GetAPIGatewaysByVPC(ctx context.Context, vpcID string) ([]domain.APIGatewayData, error) {
+	return nil, c.apiErr
+}
+
+type exposureAccountContext struct {
+	testAccountContext
+	client *exposureClient
+}
+
+func (a *exposureAccountContext) GetClient(accountID, region string) (domain.AWSClient, error) {
+	return a.client, nil
+}
+
+func TestFindInternetExposure_FailedLookupsAreUndecided(t *testing.T) {
+	accountCtx := &exposureAccountContext{client: &exposureClient{
+		enis: []domain.ENIData{
+			{ID: "eni-web", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.3", InstanceID: "i-web", InterfaceType: "interface"},
+		},
+		apiErr: errors.New("get vpc links: throttled"),
+	}}
+
+	result, err := FindInternetExposure(context.Background(), "111111111111", "us-east-1", "vpc-1", accountCtx, nil, domain.Options{})
+	if err != nil {
+		t.Fatalf("expected failed lookups not to discard the scan, got %v", err)
+	}
+	if len(result.Exposures) != 2 {
+		t.Fatalf("expected the interface and the API lookup, got %+v", result.Exposures)
+	}
+
+	iface := result.Exposures[0]
+	if iface.ResourceID != "111111111111:eni-web" || len(iface.Undecided) != 1 || !strings.Contains(iface.Undecided[0].Reason, "throttled") {
+		t.Errorf("expected eni-web undecided with the owner lookup error, got %+v", iface)
+	}
+	apis := result.Exposures[1]
+	if apis.ResourceType != "APIGateway" || len(apis.Undecided) != 1 || !strings.Contains(apis.Undecided[0].Reason, "get vpc links") {
+		t.Errorf("expected the API lookup undecided with its error, got %+v", apis)
+	}
+}
+
+type translatingComponent struct {
+	testComponent
+	sourceIP string
+}
+
+func (c *translatingComponent) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	target.SourceIP = c.sourceIP
+	return target
+}
+
+// allowlistComponent only lets traffic from sourceIP through, like a remote
+// security group that allow-lists an egress address.
+type allowlistComponent struct {
+	testComponent
+	sourceIP string
+}
+
+func (c *allowlistComponent) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	if dest.SourceIP != c.sourceIP {
+		return nil, &domain.BlockingError{ComponentID: c.id, Reason: dest.SourceIP + " is not allow-listed"}
+	}
+	return c.testComponent.GetNextHops(dest, analyzerCtx)
+}
+
+func TestTraversePathWithTrace_TranslatedFlowReachesLaterHops(t *testing.T) {
+	dest := &testComponent{id: "partner", target: domain.RoutingTarget{IP: "198.51.100.10"}}
+	firewall := &allowlistComponent{testComponent: testComponent{id: "partner-firewall", nextHops: []domain.Component{dest}}, sourceIP: "54.1.2.3"}
+	nat := &translatingComponent{testComponent: testComponent{id: "nat", nextHops: []domain.Component{firewall}}, sourceIP: "54.1.2.3"}
+	source := &testComponent{id: "app", nextHops: []domain.Component{nat}}
+
+	target := domain.RoutingTarget{IP: "198.51.100.10", Port: 443, Protocol: "tcp", SourceIP: "10.0.1.10"}
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, target, "partner", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected the translated source to be allow-listed, got %v", trace.GetBlockingReason())
+	}
+	translation := trace.Hops[1].Translation
+	if translation == nil {
+		t.Fatal("expected the NAT hop to record its translation")
+	}
+	if translation.Before.SourceIP != "10.0.1.10" || translation.After.SourceIP != "54.1.2.3" {
+		t.Errorf("expected 10.0.1.10 -> 54.1.2.3, got %s -> %s", translation.Before.SourceIP, translation.After.SourceIP)
+	}
+	if trace.Hops[0].Translation != nil || trace.Hops[2].Translation != nil {
+		t.Error("expected only the NAT hop to record a translation")
+	}
+}
+
+// sourceRoutedComponent forwards by source address, like a route table
+// associated with an appliance that hairpins translated traffic.
+type sourceRoutedComponent struct {
+	testComponent
+	routes map[string][]domain.Component
+}
+
+func (c *sourceRoutedComponent) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return c.routes[dest.SourceIP], nil
+}
+
+func TestTraversePathWithTrace_RevisitWithTranslatedFlowIsNotALoop(t *testing.T) {
+	dest := &testComponent{id: "partner", target: domain.RoutingTarget{IP: "198.51.100.10"}}
+	router := &sourceRoutedComponent{testComponent: testComponent{id: "router"}}
+	nat := &translatingComponent{testComponent: testComponent{id: "nat", nextHops: []domain.Component{router}}, sourceIP: "54.1.2.3"}
+	router.routes = map[string][]domain.Component{
+		"10.0.1.10": {nat},
+		"54.1.2.3":  {dest},
+	}
+	source := &testComponent{id: "app", nextHops: []domain.Component{router}}
+
+	target := domain.RoutingTarget{IP: "198.51.100.10", Port: 443, Protocol: "tcp", SourceIP: "10.0.1.10"}
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, target, "partner", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected the translated flow to pass the router again, got %v", trace.GetBlockingReason())
+	}
+	if len(trace.Hops) != 4 {
+		t.Errorf("expected app, router, nat, router; got %d hops", len(trace.Hops))
+	}
+}
+
+// regionalComponent is a testComponent located in a region.
+type regionalComponent struct {
+	testComponent
+	region string
+}
+
+func (c *regionalComponent) GetVPCID() string            { return "" }
+func (c *regionalComponent) GetRegion() string           { return c.region }
+func (c *regionalComponent) GetSubnetID() string         { return "" }
+func (c *regionalComponent) GetAvailabilityZone() string { return "" }
+
+func TestTraversePathWithTrace_MarksCrossRegionHops(t *testing.T) {
+	dest := &regionalComponent{testComponent: testComponent{id: "db-eu", target: domain.RoutingTarget{IP: "10.1.1.10"}}, region: "eu-west-1"}
+	peerTGW := &regionalComponent{testComponent: testComponent{id: "tgw-eu", nextHops: []domain.Component{dest}}, region: "eu-west-1"}
+	localTGW := &regionalComponent{testComponent: testComponent{id: "tgw-us", nextHops: []domain.Component{peerTGW}}, region: "us-east-1"}
+	source := &regionalComponent{testComponent: testComponent{id: "app-us", nextHops: []domain.Component{localTGW}}, region: "us-east-1"}
+
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "10.1.1.10"}, "db-eu", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected success, got %v", trace.GetBlockingReason())
+	}
+	want := []bool{false, false, true}
+	if len(trace.Hops) != len(want) {
+		t.Fatalf("expected %d hops, got %d", len(want), len(trace.Hops))
+	}
+	for i, want := range want {
+		if trace.Hops[i].CrossRegion != want {
+			t.Errorf("hop %d (%s): expected CrossRegion %v", i, trace.Hops[i].ComponentID, want)
+		}
+	}
+}
+
+// peeringComponent marks the flow as having crossed a peering connection.
+type peeringComponent struct {
+	testComponent
+}
+
+func (c *peeringComponent) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	target.ViaPeering = c.id
+	return target
+}
+
+// gatewayComponent is a testComponent of the given component type.
+type gatewayComponent struct {
+	testComponent
+	componentType string
+}
+
+func (c *gatewayComponent) GetComponentType() string {
+	return c.componentType
+}
+
+func TestTraversePathWithTrace_PeeredTrafficCannotLeaveThroughGateway(t *testing.T) {
+	internet := &testComponent{id: "internet", target: domain.RoutingTarget{IP: "8.8.8.8"}}
+	nat := &gatewayComponent{testComponent: testComponent{id: "nat-peer", nextHops: []domain.Component{internet}}, componentType: "NATGateway"}
+	peerRouteTable := &testComponent{id: "rtb-peer", nextHops: []domain.Component{nat}}
+	peering := &peeringComponent{testComponent: testComponent{id: "pcx-1", nextHops: []domain.Component{peerRouteTable}}}
+	source := &testComponent{id: "app", nextHops: []domain.Component{peering}}
+
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "8.8.8.8", Port: 443, Protocol: "tcp"}, "internet", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if !result.IsBlocked() {
+		t.Fatal("expected traffic from a peering connection to be blocked at the NAT gateway")
+	}
+	if trace.BlockedAt == nil || trace.BlockedAt.ComponentID != "nat-peer" {
+		t.Fatalf("expected block at nat-peer, got %+v", trace.BlockedAt)
+	}
+	if !strings.Contains(trace.BlockedAt.Details, "pcx-1") {
+		t.Errorf("expected the reason to name the peering connection, got %q", trace.BlockedAt.Details)
+	}
+}
+
+func TestTraversePathWithTrace_GatewayWithoutPeeringPasses(t *testing.T) {
+	internet := &testComponent{id: "internet", target: domain.RoutingTarget{IP: "8.8.8.8"}}
+	nat := &gatewayComponent{testComponent: testComponent{id: "nat", nextHops: []domain.Component{internet}}, componentType: "NATGateway"}
+	source := &testComponent{id: "app", nextHops: []domain.Component{nat}}
+
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "8.8.8.8", Port: 443, Protocol: "tcp"}, "internet", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("expected success, got %v", trace.GetBlockingReason())
+	}
+}
+
+// advisingComponent warns about every flow it lets through.
+type advisingComponent struct {
+	testComponent
+	advisory string
+}
+
+func (c *advisingComponent) Advise(target domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) string {
+	return c.advisory
+}
+
+func TestTraversePathWithTrace_RecordsAdvisory(t *testing.T) {
+	dest := &testComponent{id: "db", target: domain.RoutingTarget{IP: "10.1.1.10"}}
+	inspection := &advisingComponent{testComponent: testComponent{id: "inspection", nextHops: []domain.Component{dest}}, advisory: "asymmetric return path"}
+	source := &testComponent{id: "app", nextHops: []domain.Component{inspection}}
+
+	trace := domain.NewPathTrace()
+	result := TraversePathWithTrace(source, domain.RoutingTarget{IP: "10.1.1.10"}, "db", NewAnalyzerContext(context.Background(), &testAccountContext{}), nil, trace, domain.HopLineage{})
+
+	if result.IsBlocked() {
+		t.Fatalf("an advisory must not block the flow, got %v", trace.GetBlockingReason())
+	}
+	if trace.Hops[0].Advisory != "" {
+		t.Errorf("expected no advisory on the source hop, got %q", trace.Hops[0].Advisory)
+	}
+	if trace.Hops[1].Advisory != "asymmetric return path" {
+		t.Errorf("expected the advisory on the inspection hop, got %q", trace.Hops[1].Advisory)
+	}
+}
@@ -7,8 +7,8 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -24,9 +24,13 @@ type AccountContext struct {
 	stsClient       *sts.Client
 	credentialCache map[string]credentialEntry
 	clientPool      map[string]*Client
+	refresh         singleflight.Group
 	mu              sync.RWMutex
 }
 
+// credentialRefreshWindow is how long before expiry credentials are renewed.
+const credentialRefreshWindow = 5 * time.Minute
+
 func NewAccountContext(cfg aws.Config, roleARNPattern string) *AccountContext {
 	if roleARNPattern == "" {
 		roleARNPattern = "arn:aws:iam::%s:role/ReachabilityAnalyzerCrossAccountRole"
@@ -40,15 +44,38 @@ func NewAccountContext(cfg aws.Config, roleARNPattern string) *AccountContext {
 	}
 }
 
+// AssumeRole returns the account's assumed-role credentials, renewing them
+// shortly before they expire. Concurrent renewals for one account share a
+// single STS call.
 func (a *AccountContext) AssumeRole(accountID string) (domain.AWSCredentials, error) {
+	if creds, ok := a.cachedCredentials(accountID); ok {
+		return creds, nil
+	}
+
+	v, err, _ := a.refresh.Do(accountID, func() (interface{}, error) {
+		if creds, ok := a.cachedCredentials(accountID); ok {
+			return creds, nil
+		}
+		return a.assumeRole(accountID)
+	})
+	if err != nil {
+		return domain.AWSCredentials{}, err
+	}
+	return v.(domain.AWSCredentials), nil
+}
+
+func (a *AccountContext) cachedCredentials(accountID string) (domain.AWSCredentials, bool) {
 	a.mu.RLock()
 	entry, exists := a.credentialCache[accountID]
 	a.mu.RUnlock()
 
-	if exists && time.Now().Add(5*time.Minute).Before(entry.expiration) {
-		return entry.creds, nil
+	if exists && time.Now().Add(credentialRefreshWindow).Before(entry.expiration) {
+		return entry.creds, true
 	}
+	return domain.AWSCredentials{}, false
+}
 
+func (a *AccountContext) assumeRole(accountID string) (domain.AWSCredentials, error) {
 	roleARN := fmt.Sprintf(a.roleARNPattern, accountID)
 	sessionName := fmt.Sprintf("reachability-analyzer-%s", accountID)
 
@@ -78,35 +105,63 @@ func (a *AccountContext) AssumeRole(accountID string) (domain.AWSCredentials, er
 	return creds, nil
 }
 
-func (a *AccountContext) GetClient(accountID string) (domain.AWSClient, error) {
+// GetClient returns the client for accountID in region, creating it on first
+// use. Clients share the account's assumed-role credentials but are pooled per
+// region, since every AWS API call is made against a single region. A pooled
+// client is kept across credential renewals, so its response cache survives;
+// only the credentials it signs with change.
+func (a *AccountContext) GetClient(accountID, region string) (domain.AWSClient, error) {
+	if region == "" {
+		region = a.baseConfig.Region
+	}
+	key := accountID + "/" + region
+
+	if _, err := a.AssumeRole(accountID); err != nil {
+		return nil, err
+	}
+
 	a.mu.RLock()
-	client, exists := a.clientPool[accountID]
+	client, exists := a.clientPool[key]
 	a.mu.RUnlock()
-
 	if exists {
-		entry, hasEntry := a.credentialCache[accountID]
-		if hasEntry && time.Now().Add(5*time.Minute).Before(entry.expiration) {
-			return client, nil
-		}
+		return client, nil
 	}
 
-	creds, err := a.AssumeRole(accountID)
-	if err != nil {
-		return nil, err
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if client, exists := a.clientPool[key]; exists {
+		return client, nil
 	}
 
 	cfg := a.baseConfig.Copy()
-	cfg.Credentials = credentials.NewStaticCredentialsProvider(
-		creds.AccessKeyID,
-		creds.SecretAccessKey,
-		creds.SessionToken,
-	)
-
-	client = NewClient(cfg, accountID, cfg.Region)
+	cfg.Region = region
+	cfg.Credentials = aws.NewCredentialsCache(&accountCredentials{accounts: a, accountID: accountID})
 
-	a.mu.Lock()
-	a.clientPool[accountID] = client
-	a.mu.Unlock()
+	client = NewClient(cfg, accountID, region)
+	a.clientPool[key] = client
 
 	return client, nil
 }
+
+// accountCredentials serves an account's assumed-role credentials to the SDK.
+// They are reported as expiring at the start of the refresh window, so the
+// SDK asks again once AssumeRole would renew them.
+type accountCredentials struct {
+	accounts  *AccountContext
+	accountID string
+}
+
+func (c *accountCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := c.accounts.AssumeRole(c.accountID)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	return aws.Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Source:          "AccountContextAssumeRole",
+		CanExpire:       true,
+		Expires:         creds.Expiration.Add(-credentialRefreshWindow),
+	}, nil
+}
@@ -3,10 +3,12 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigwtypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
 	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -43,6 +45,7 @@ func (c *Client) GetAPIGatewayREST(ctx context.Context, apiID string) (*domain.A
 		APIType:        "REST",
 		EndpointType:   endpointType,
 		VPCEndpointIDs: vpceIDs,
+		Region:         c.region,
 	}
 
 	c.cache.set(key, data)
@@ -69,6 +72,7 @@ func (c *Client) GetAPIGatewayHTTP(ctx context.Context, apiID string) (*domain.A
 		Name:         derefString(out.Name),
 		APIType:      apiType,
 		EndpointType: "REGIONAL",
+		Region:       c.region,
 	}
 
 	integrationsOut, err := c.apigwv2Client.GetIntegrations(ctx, &apigatewayv2.GetIntegrationsInput{
@@ -129,6 +133,7 @@ func (c *Client) GetVPCLinkV1(ctx context.Context, vpcLinkID string) (*domain.VP
 		Version:    "V1",
 		TargetARNs: out.TargetArns,
 		Status:     string(out.Status),
+		Region:     c.region,
 	}
 
 	c.cache.set(key, data)
@@ -167,6 +172,7 @@ func (c *Client) GetVPCLinkV2(ctx context.Context, vpcLinkID string) (*domain.VP
 		Status:             string(out.VpcLinkStatus),
 		VPCID:              vpcID,
 		IntegrationTargets: c.getIntegrationTargets(vpcLinkID),
+		Region:             c.region,
 	}
 
 	c.cache.set(key, data)
@@ -199,6 +205,7 @@ func (c *Client) GetAPIGatewayByVPCEndpoint(ctx context.Context, vpceID string)
 							APIType:        "REST",
 							EndpointType:   endpointType,
 							VPCEndpointIDs: api.EndpointConfiguration.VpcEndpointIds,
+							Region:         c.region,
 						}
 						c.cache.set(key, data)
 						return data, nil
@@ -257,6 +264,7 @@ func (c *Client) GetAPIGatewayByVPCEndpoint(ctx context.Context, vpceID string)
 					APIType:      string(api.ProtocolType),
 					EndpointType: "REGIONAL",
 					VPCLinkIDs:   vpcLinkIDs,
+					Region:       c.region,
 				}
 				c.cache.set(key, data)
 				return data, nil
@@ -329,3 +337,110 @@ func (c *Client) GetAPIGatewayByPrivateIP(ctx context.Context, ip, vpcID string)
 
 	return nil, nil
 }
+
+// GetAPIGatewaysByVPC returns the REST and HTTP APIs with an integration that
+// reaches into vpcID through a VPC link.
+func (c *Client) GetAPIGatewaysByVPC(ctx context.Context, vpcID string) ([]domain.APIGatewayData, error) {
+	var apis []domain.APIGatewayData
+
+	restPaginator := apigateway.NewGetRestApisPaginator(c.apigwClient, &apigateway.GetRestApisInput{})
+	for restPaginator.HasMorePages() {
+		page, err := restPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get rest apis: %w", err)
+		}
+		for _, api := range page.Items {
+			apiID := derefString(api.Id)
+			linkIDs, err := c.restAPIVPCLinks(ctx, apiID, vpcID)
+			if err != nil {
+				return nil, err
+			}
+			if len(linkIDs) == 0 {
+				continue
+			}
+			data, err := c.GetAPIGatewayREST(ctx, apiID)
+			if err != nil {
+				return nil, err
+			}
+			withLinks := *data
+			withLinks.VPCLinkIDs = linkIDs
+			apis = append(apis, withLinks)
+		}
+	}
+
+	var nextToken *string
+	for {
+		out, err := c.apigwv2Client.GetApis(ctx, &apigatewayv2.GetApisInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("get apis: %w", err)
+		}
+		for _, api := range out.Items {
+			data, err := c.GetAPIGatewayHTTP(ctx, derefString(api.ApiId))
+			if err != nil {
+				return nil, err
+			}
+			for _, linkID := range data.VPCLinkIDs {
+				link, err := c.GetVPCLinkV2(ctx, linkID)
+				if err != nil {
+					return nil, err
+				}
+				if link.VPCID == vpcID {
+					apis = append(apis, *data)
+					break
+				}
+			}
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+
+	return apis, nil
+}
+
+// restAPIVPCLinks returns the VPC links a REST API integrates through whose
+// network load balancers are in vpcID.
+func (c *Client) restAPIVPCLinks(ctx context.Context, apiID, vpcID string) ([]string, error) {
+	connections := make(map[string]bool)
+	paginator := apigateway.NewGetResourcesPaginator(c.apigwClient, &apigateway.GetResourcesInput{
+		RestApiId: aws.String(apiID),
+		Embed:     []string{"methods"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get resources for rest api %s: %w", apiID, err)
+		}
+		for _, resource := range page.Items {
+			for _, method := range resource.ResourceMethods {
+				if method.MethodIntegration != nil && method.MethodIntegration.ConnectionType == apigwtypes.ConnectionTypeVpcLink {
+					connections[derefString(method.MethodIntegration.ConnectionId)] = true
+				}
+			}
+		}
+	}
+
+	var linkIDs []string
+	for linkID := range connections {
+		if linkID == "" {
+			continue
+		}
+		link, err := c.GetVPCLinkV1(ctx, linkID)
+		if err != nil {
+			return nil, err
+		}
+		for _, targetARN := range link.TargetARNs {
+			nlb, err := c.GetNLB(ctx, targetARN)
+			if err != nil {
+				return nil, err
+			}
+			if nlb.VPCID == vpcID {
+				linkIDs = append(linkIDs, linkID)
+				break
+			}
+		}
+	}
+	sort.Strings(linkIDs)
+	return linkIDs, nil
+}
@@ -3,9 +3,12 @@ package aws
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	dxtypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
@@ -23,9 +26,11 @@ func (c *Client) GetEC2Instance(ctx context.Context, instanceID string) (*domain
 		return nil, fmt.Errorf("describe instance %s: %w", instanceID, err)
 	}
 	if len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
-		return nil, fmt.Errorf("instance %s not found", instanceID)
+		return nil, &domain.NotFoundError{Kind: "instance", ID: instanceID}
 	}
-	return toEC2InstanceData(&out.Reservations[0].Instances[0]), nil
+	data := toEC2InstanceData(&out.Reservations[0].Instances[0])
+	data.Region = c.region
+	return data, nil
 }
 
 func (c *Client) GetRDSInstance(ctx context.Context, dbInstanceID string) (*domain.RDSInstanceData, error) {
@@ -36,11 +41,12 @@ func (c *Client) GetRDSInstance(ctx context.Context, dbInstanceID string) (*doma
 		return nil, fmt.Errorf("describe rds instance %s: %w", dbInstanceID, err)
 	}
 	if len(out.DBInstances) == 0 {
-		return nil, fmt.Errorf("rds instance %s not found", dbInstanceID)
+		return nil, &domain.NotFoundError{Kind: "rds instance", ID: dbInstanceID}
 	}
 
 	db := &out.DBInstances[0]
 	privateIP := ""
+	var ipv6Addresses []string
 
 	if db.DBInstanceArn != nil {
 		eniOut, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
@@ -51,10 +57,14 @@ func (c *Client) GetRDSInstance(ctx context.Context, dbInstanceID string) (*doma
 		})
 		if err == nil && len(eniOut.NetworkInterfaces) > 0 {
 			privateIP = derefString(eniOut.NetworkInterfaces[0].PrivateIpAddress)
+			ipv6Addresses = toENIData(&eniOut.NetworkInterfaces[0]).IPv6Addresses
 		}
 	}
 
-	return toRDSInstanceData(db, privateIP), nil
+	data := toRDSInstanceData(db, privateIP)
+	data.Region = c.region
+	data.IPv6Addresses = ipv6Addresses
+	return data, nil
 }
 
 func (c *Client) GetLambdaFunction(ctx context.Context, functionName string) (*domain.LambdaFunctionData, error) {
@@ -66,6 +76,7 @@ func (c *Client) GetLambdaFunction(ctx context.Context, functionName string) (*d
 	}
 
 	data := toLambdaFunctionData(out)
+	data.Region = c.region
 
 	if len(data.SubnetIDs) > 0 {
 		subnetOut, err := c.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
@@ -78,11 +89,63 @@ func (c *Client) GetLambdaFunction(ctx context.Context, functionName string) (*d
 				}
 			}
 		}
+
+		data.ENIIPs, err = c.lambdaENIIPs(ctx, data)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return data, nil
 }
 
+// lambdaENIIPs returns the addresses of the network interfaces a VPC function
+// sends from. Lambda shares an interface between the functions with the same
+// subnet and security groups, so the function's are the lambda interfaces in
+// its subnets carrying exactly its security groups.
+func (c *Client) lambdaENIIPs(ctx context.Context, fn *domain.LambdaFunctionData) ([]string, error) {
+	out, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("interface-type"), Values: []string{string(ec2types.NetworkInterfaceTypeLambda)}},
+			{Name: aws.String("subnet-id"), Values: fn.SubnetIDs},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe network interfaces for lambda %s: %w", fn.Name, err)
+	}
+	var ips []string
+	for _, eni := range out.NetworkInterfaces {
+		if !sameGroups(extractENIGroupIDs(eni.Groups), fn.SecurityGroups) {
+			continue
+		}
+		if ip := derefString(eni.PrivateIpAddress); ip != "" {
+			ips = append(ips, ip)
+		}
+		if !fn.DualStack {
+			continue
+		}
+		for _, addr := range eni.Ipv6Addresses {
+			if ip := derefString(addr.Ipv6Address); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips, nil
+}
+
+// sameGroups reports whether a and b hold the same security group IDs.
+func sameGroups(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, id := range a {
+		if !slices.Contains(b, id) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Client) GetVirtualPrivateGateway(ctx context.Context, vgwID string) (*domain.VirtualPrivateGatewayData, error) {
 	out, err := c.ec2Client.DescribeVpnGateways(ctx, &ec2.DescribeVpnGatewaysInput{
 		VpnGatewayIds: []string{vgwID},
@@ -91,7 +154,7 @@ func (c *Client) GetVirtualPrivateGateway(ctx context.Context, vgwID string) (*d
 		return nil, fmt.Errorf("describe vpn gateway %s: %w", vgwID, err)
 	}
 	if len(out.VpnGateways) == 0 {
-		return nil, fmt.Errorf("vpn gateway %s not found", vgwID)
+		return nil, &domain.NotFoundError{Kind: "vpn gateway", ID: vgwID}
 	}
 	vgw := &out.VpnGateways[0]
 	var vpcID string
@@ -102,8 +165,9 @@ func (c *Client) GetVirtualPrivateGateway(ctx context.Context, vgwID string) (*d
 		}
 	}
 	return &domain.VirtualPrivateGatewayData{
-		ID:    derefString(vgw.VpnGatewayId),
-		VPCID: vpcID,
+		ID:     derefString(vgw.VpnGatewayId),
+		VPCID:  vpcID,
+		Region: c.region,
 	}, nil
 }
 
@@ -115,22 +179,11 @@ func (c *Client) GetVPNConnection(ctx context.Context, vpnID string) (*domain.VP
 		return nil, fmt.Errorf("describe vpn connection %s: %w", vpnID, err)
 	}
 	if len(out.VpnConnections) == 0 {
-		return nil, fmt.Errorf("vpn connection %s not found", vpnID)
-	}
-	vpn := &out.VpnConnections[0]
-	hasUpTunnel := false
-	for _, tel := range vpn.VgwTelemetry {
-		if tel.Status == ec2types.TelemetryStatusUp {
-			hasUpTunnel = true
-			break
-		}
+		return nil, &domain.NotFoundError{Kind: "vpn connection", ID: vpnID}
 	}
-	return &domain.VPNConnectionData{
-		ID:          derefString(vpn.VpnConnectionId),
-		VGWID:       derefString(vpn.VpnGatewayId),
-		State:       string(vpn.State),
-		HasUpTunnel: hasUpTunnel,
-	}, nil
+	data := toVPNConnectionData(&out.VpnConnections[0])
+	data.Region = c.region
+	return data, nil
 }
 
 func (c *Client) GetVPNConnectionsByVGW(ctx context.Context, vgwID string) ([]*domain.VPNConnectionData, error) {
@@ -147,29 +200,50 @@ func (c *Client) GetVPNConnectionsByVGW(ctx context.Context, vgwID string) ([]*d
 	}
 
 	var result []*domain.VPNConnectionData
-	for _, vpn := range out.VpnConnections {
-		hasUpTunnel := false
-		for _, tel := range vpn.VgwTelemetry {
-			if tel.Status == ec2types.TelemetryStatusUp {
-				hasUpTunnel = true
-				break
-			}
-		}
-		result = append(result, &domain.VPNConnectionData{
-			ID:          derefString(vpn.VpnConnectionId),
-			VGWID:       derefString(vpn.VpnGatewayId),
-			State:       string(vpn.State),
-			HasUpTunnel: hasUpTunnel,
-		})
+	for i := range out.VpnConnections {
+		data := toVPNConnectionData(&out.VpnConnections[i])
+		data.Region = c.region
+		result = append(result, data)
 	}
 	return result, nil
 }
 
 func (c *Client) GetDirectConnectGateway(ctx context.Context, dxgwID string) (*domain.DirectConnectGatewayData, error) {
-	return &domain.DirectConnectGatewayData{
-		ID:      dxgwID,
-		OwnerID: c.accountID,
-	}, nil
+	key := c.cacheKey("dxgw", dxgwID)
+	if v, ok := c.cache.get(key); ok {
+		return v.(*domain.DirectConnectGatewayData), nil
+	}
+
+	out, err := c.directconnectClient.DescribeDirectConnectGateways(ctx, &directconnect.DescribeDirectConnectGatewaysInput{
+		DirectConnectGatewayId: aws.String(dxgwID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe direct connect gateway %s: %w", dxgwID, err)
+	}
+	if len(out.DirectConnectGateways) == 0 {
+		return nil, &domain.NotFoundError{Kind: "direct connect gateway", ID: dxgwID}
+	}
+
+	var associations []dxtypes.DirectConnectGatewayAssociation
+	var nextToken *string
+	for {
+		page, err := c.directconnectClient.DescribeDirectConnectGatewayAssociations(ctx, &directconnect.DescribeDirectConnectGatewayAssociationsInput{
+			DirectConnectGatewayId: aws.String(dxgwID),
+			NextToken:              nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe direct connect gateway associations for %s: %w", dxgwID, err)
+		}
+		associations = append(associations, page.DirectConnectGatewayAssociations...)
+		if page.NextToken == nil {
+			break
+		}
+		nextToken = page.NextToken
+	}
+
+	data := toDirectConnectGatewayData(&out.DirectConnectGateways[0], associations)
+	c.cache.set(key, data)
+	return data, nil
 }
 
 func (c *Client) GetNetworkInterface(ctx context.Context, eniID string) (*domain.ENIData, error) {
@@ -180,10 +254,12 @@ func (c *Client) GetNetworkInterface(ctx context.Context, eniID string) (*domain
 		return nil, fmt.Errorf("describe network interface %s: %w", eniID, err)
 	}
 	if len(out.NetworkInterfaces) == 0 {
-		return nil, fmt.Errorf("network interface %s not found", eniID)
+		return nil, &domain.NotFoundError{Kind: "network interface", ID: eniID}
 	}
 
-	return toENIData(&out.NetworkInterfaces[0]), nil
+	data := toENIData(&out.NetworkInterfaces[0])
+	data.Region = c.region
+	return data, nil
 }
 
 func (c *Client) GetENIsBySecurityGroup(ctx context.Context, sgID string) ([]domain.ENIData, error) {
@@ -209,17 +285,52 @@ func (c *Client) GetENIsBySecurityGroup(ctx context.Context, sgID string) ([]dom
 
 	var enis []domain.ENIData
 	for _, eni := range networkInterfaces {
-		enis = append(enis, *toENIData(&eni))
+		data := toENIData(&eni)
+		data.Region = c.region
+		enis = append(enis, *data)
 	}
 	return enis, nil
 }
 
-func (c *Client) GetNetworkInterfaceByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.ENIData, error) {
-	out, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+func (c *Client) GetENIsByVPC(ctx context.Context, vpcID string) ([]domain.ENIData, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: []ec2types.Filter{
-			{Name: aws.String("private-ip-address"), Values: []string{ip}},
 			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
 		},
+	}
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.ec2Client, input)
+	networkInterfaces, err := CollectPages(
+		ctx,
+		paginator.HasMorePages,
+		func(ctx context.Context) (*ec2.DescribeNetworkInterfacesOutput, error) {
+			return paginator.NextPage(ctx)
+		},
+		func(out *ec2.DescribeNetworkInterfacesOutput) []ec2types.NetworkInterface {
+			return out.NetworkInterfaces
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("describe network interfaces for vpc %s: %w", vpcID, err)
+	}
+
+	var enis []domain.ENIData
+	for _, eni := range networkInterfaces {
+		data := toENIData(&eni)
+		data.Region = c.region
+		enis = append(enis, *data)
+	}
+	return enis, nil
+}
+
+func (c *Client) GetNetworkInterfaceByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.ENIData, error) {
+	filters := []ec2types.Filter{
+		{Name: aws.String(addressFilter(ip, "")), Values: []string{ip}},
+	}
+	if vpcID != "" {
+		filters = append(filters, ec2types.Filter{Name: aws.String("vpc-id"), Values: []string{vpcID}})
+	}
+	out, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+		Filters: filters,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("describe network interfaces for ip %s: %w", ip, err)
@@ -227,13 +338,15 @@ func (c *Client) GetNetworkInterfaceByPrivateIP(ctx context.Context, ip, vpcID s
 	if len(out.NetworkInterfaces) == 0 {
 		return nil, nil
 	}
-	return toENIData(&out.NetworkInterfaces[0]), nil
+	data := toENIData(&out.NetworkInterfaces[0])
+	data.Region = c.region
+	return data, nil
 }
 
 func (c *Client) GetEC2InstanceByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.EC2InstanceData, error) {
 	out, err := c.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
 		Filters: []ec2types.Filter{
-			{Name: aws.String("private-ip-address"), Values: []string{ip}},
+			{Name: aws.String(addressFilter(ip, "network-interface.")), Values: []string{ip}},
 			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
 		},
 	})
@@ -242,7 +355,9 @@ func (c *Client) GetEC2InstanceByPrivateIP(ctx context.Context, ip, vpcID string
 	}
 	for _, res := range out.Reservations {
 		for _, inst := range res.Instances {
-			return toEC2InstanceData(&inst), nil
+			data := toEC2InstanceData(&inst)
+			data.Region = c.region
+			return data, nil
 		}
 	}
 	return nil, nil
@@ -266,6 +381,7 @@ func (c *Client) GetRDSInstanceByPrivateIP(ctx context.Context, ip, vpcID string
 	for _, db := range dbInstances {
 		data := toRDSInstanceData(&db, "")
 		if data != nil && data.PrivateIP == ip {
+			data.Region = c.region
 			return data, nil
 		}
 	}
@@ -275,7 +391,7 @@ func (c *Client) GetRDSInstanceByPrivateIP(ctx context.Context, ip, vpcID string
 func (c *Client) GetLambdaFunctionByENIIP(ctx context.Context, ip, vpcID string) (*domain.LambdaFunctionData, error) {
 	out, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
 		Filters: []ec2types.Filter{
-			{Name: aws.String("private-ip-address"), Values: []string{ip}},
+			{Name: aws.String(addressFilter(ip, "")), Values: []string{ip}},
 			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
 			{Name: aws.String("interface-type"), Values: []string{"lambda"}},
 		},
@@ -304,9 +420,20 @@ func (c *Client) GetLambdaFunctionByENIIP(ctx context.Context, ip, vpcID string)
 		SubnetIDs:      []string{derefString(eni.SubnetId)},
 		SecurityGroups: extractENIGroupIDs(eni.Groups),
 		ENIIPs:         []string{ip},
+		Region:         c.region,
 	}, nil
 }
 
+// addressFilter returns the describe filter that matches a private address of
+// ip's family. prefix scopes the filter to a nested resource, e.g.
+// "network-interface." for instances.
+func addressFilter(ip, prefix string) string {
+	if strings.Contains(ip, ":") {
+		return prefix + "ipv6-addresses.ipv6-address"
+	}
+	return "private-ip-address"
+}
+
 func extractENIGroupIDs(groups []ec2types.GroupIdentifier) []string {
 	var ids []string
 	for _, g := range groups {
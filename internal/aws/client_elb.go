@@ -23,7 +23,7 @@ func (c *Client) GetALB(ctx context.Context, albARN string) (*domain.ALBData, er
 		return nil, fmt.Errorf("describe alb %s: %w", albARN, err)
 	}
 	if len(out.LoadBalancers) == 0 {
-		return nil, fmt.Errorf("alb %s not found", albARN)
+		return nil, &domain.NotFoundError{Kind: "alb", ID: albARN}
 	}
 
 	lb := &out.LoadBalancers[0]
@@ -31,12 +31,20 @@ func (c *Client) GetALB(ctx context.Context, albARN string) (*domain.ALBData, er
 		return nil, fmt.Errorf("load balancer %s is not an ALB", albARN)
 	}
 
-	tgARNs, err := c.getTargetGroupARNsForLB(ctx, albARN)
+	listeners, err := c.getListeners(ctx, albARN)
 	if err != nil {
 		return nil, err
 	}
 
-	return toALBData(lb, tgARNs), nil
+	data := toALBData(lb, listenerTargetGroupARNs(listeners))
+	data.Listeners = listeners
+	data.ListenersLoaded = true
+	data.Region = c.region
+	data.FrontendIPs, _, err = c.loadBalancerIPs(ctx, albARN)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (c *Client) GetALBByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.ALBData, error) {
@@ -74,7 +82,7 @@ func (c *Client) GetNLB(ctx context.Context, nlbARN string) (*domain.NLBData, er
 		return nil, fmt.Errorf("describe nlb %s: %w", nlbARN, err)
 	}
 	if len(out.LoadBalancers) == 0 {
-		return nil, fmt.Errorf("nlb %s not found", nlbARN)
+		return nil, &domain.NotFoundError{Kind: "nlb", ID: nlbARN}
 	}
 
 	lb := &out.LoadBalancers[0]
@@ -82,12 +90,70 @@ func (c *Client) GetNLB(ctx context.Context, nlbARN string) (*domain.NLBData, er
 		return nil, fmt.Errorf("load balancer %s is not an NLB", nlbARN)
 	}
 
-	tgARNs, err := c.getTargetGroupARNsForLB(ctx, nlbARN)
+	listeners, err := c.getListeners(ctx, nlbARN)
+	if err != nil {
+		return nil, err
+	}
+
+	data := toNLBData(lb, listenerTargetGroupARNs(listeners))
+	data.Listeners = listeners
+	data.ListenersLoaded = true
+	data.Region = c.region
+	data.FrontendIPs, data.FrontendIPsByAZ, err = c.loadBalancerIPs(ctx, nlbARN)
 	if err != nil {
 		return nil, err
 	}
 
-	return toNLBData(lb, tgARNs), nil
+	attrOut, err := c.elbv2Client.DescribeLoadBalancerAttributes(ctx, &elbv2.DescribeLoadBalancerAttributesInput{
+		LoadBalancerArn: aws.String(nlbARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe load balancer attributes for %s: %w", nlbARN, err)
+	}
+	for _, attr := range attrOut.Attributes {
+		if derefString(attr.Key) == "load_balancing.cross_zone.enabled" {
+			data.CrossZone = derefString(attr.Value) == "true"
+		}
+	}
+	return data, nil
+}
+
+// loadBalancerIPs returns the private addresses of a load balancer's network
+// interfaces, in all and by Availability Zone. Their description is "ELB "
+// followed by the resource part of the ARN, e.g. "ELB net/web/50dc6c495c0c9188".
+func (c *Client) loadBalancerIPs(ctx context.Context, lbARN string) ([]string, map[string]string, error) {
+	_, resource, ok := strings.Cut(lbARN, ":loadbalancer/")
+	if !ok {
+		return nil, nil, nil
+	}
+	return c.interfaceIPsByDescription(ctx, lbARN, "ELB "+resource)
+}
+
+// interfaceIPsByDescription returns the private addresses of the network
+// interfaces whose description is description, in all and by Availability
+// Zone. lb names the owner in errors.
+func (c *Client) interfaceIPsByDescription(ctx context.Context, lb, description string) ([]string, map[string]string, error) {
+	out, err := c.ec2Client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("description"), Values: []string{description}},
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("describe network interfaces for %s: %w", lb, err)
+	}
+	var ips []string
+	byAZ := make(map[string]string)
+	for _, eni := range out.NetworkInterfaces {
+		ip := derefString(eni.PrivateIpAddress)
+		if ip == "" {
+			continue
+		}
+		ips = append(ips, ip)
+		if az := derefString(eni.AvailabilityZone); az != "" {
+			byAZ[az] = ip
+		}
+	}
+	return ips, byAZ, nil
 }
 
 func (c *Client) GetNLBByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.NLBData, error) {
@@ -120,7 +186,9 @@ func (c *Client) GetNLBByPrivateIP(ctx context.Context, ip, vpcID string) (*doma
 		if lb.VpcId != nil && *lb.VpcId != vpcID {
 			continue
 		}
-		return toNLBData(&lb, nil), nil
+		data := toNLBData(&lb, nil)
+		data.Region = c.region
+		return data, nil
 	}
 	return nil, nil
 }
@@ -133,7 +201,7 @@ func (c *Client) GetGWLB(ctx context.Context, gwlbARN string) (*domain.GWLBData,
 		return nil, fmt.Errorf("describe gwlb %s: %w", gwlbARN, err)
 	}
 	if len(out.LoadBalancers) == 0 {
-		return nil, fmt.Errorf("gwlb %s not found", gwlbARN)
+		return nil, &domain.NotFoundError{Kind: "gwlb", ID: gwlbARN}
 	}
 
 	lb := &out.LoadBalancers[0]
@@ -146,38 +214,71 @@ func (c *Client) GetGWLB(ctx context.Context, gwlbARN string) (*domain.GWLBData,
 		return nil, err
 	}
 
-	return toGWLBData(lb, tgARNs), nil
+	data := toGWLBData(lb, tgARNs)
+	data.Region = c.region
+	return data, nil
 }
 
 func (c *Client) getTargetGroupARNsForLB(ctx context.Context, lbARN string) ([]string, error) {
-	out, err := c.elbv2Client.DescribeListeners(ctx, &elbv2.DescribeListenersInput{
+	listeners, err := c.getListeners(ctx, lbARN)
+	if err != nil {
+		return nil, err
+	}
+	return listenerTargetGroupARNs(listeners), nil
+}
+
+// getListeners returns the listeners of a load balancer. The target groups of
+// an HTTP or HTTPS listener include those its rules forward to.
+func (c *Client) getListeners(ctx context.Context, lbARN string) ([]domain.ListenerData, error) {
+	key := c.cacheKey("lb-listeners", lbARN)
+	if v, ok := c.cache.get(key); ok {
+		return v.([]domain.ListenerData), nil
+	}
+
+	paginator := elbv2.NewDescribeListenersPaginator(c.elbv2Client, &elbv2.DescribeListenersInput{
 		LoadBalancerArn: aws.String(lbARN),
 	})
+	described, err := CollectPages(
+		ctx,
+		paginator.HasMorePages,
+		func(ctx context.Context) (*elbv2.DescribeListenersOutput, error) {
+			return paginator.NextPage(ctx)
+		},
+		func(out *elbv2.DescribeListenersOutput) []elbv2types.Listener {
+			return out.Listeners
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("describe listeners for %s: %w", lbARN, err)
 	}
 
-	tgMap := make(map[string]bool)
-	for _, listener := range out.Listeners {
-		for _, action := range listener.DefaultActions {
-			if action.TargetGroupArn != nil {
-				tgMap[*action.TargetGroupArn] = true
-			}
-			if action.ForwardConfig != nil {
-				for _, tgTuple := range action.ForwardConfig.TargetGroups {
-					if tgTuple.TargetGroupArn != nil {
-						tgMap[*tgTuple.TargetGroupArn] = true
-					}
-				}
+	var listeners []domain.ListenerData
+	for i := range described {
+		listener := &described[i]
+		var rules []elbv2types.Rule
+		if listener.Protocol == elbv2types.ProtocolEnumHttp || listener.Protocol == elbv2types.ProtocolEnumHttps {
+			rulesPaginator := elbv2.NewDescribeRulesPaginator(c.elbv2Client, &elbv2.DescribeRulesInput{
+				ListenerArn: listener.ListenerArn,
+			})
+			rules, err = CollectPages(
+				ctx,
+				rulesPaginator.HasMorePages,
+				func(ctx context.Context) (*elbv2.DescribeRulesOutput, error) {
+					return rulesPaginator.NextPage(ctx)
+				},
+				func(out *elbv2.DescribeRulesOutput) []elbv2types.Rule {
+					return out.Rules
+				},
+			)
+			if err != nil {
+				return nil, fmt.Errorf("describe rules for listener %s: %w", derefString(listener.ListenerArn), err)
 			}
 		}
+		listeners = append(listeners, toListenerData(listener, rules))
 	}
 
-	var tgARNs []string
-	for arn := range tgMap {
-		tgARNs = append(tgARNs, arn)
-	}
-	return tgARNs, nil
+	c.cache.set(key, listeners)
+	return listeners, nil
 }
 
 func (c *Client) GetCLB(ctx context.Context, clbName string) (*domain.CLBData, error) {
@@ -188,10 +289,18 @@ func (c *Client) GetCLB(ctx context.Context, clbName string) (*domain.CLBData, e
 		return nil, fmt.Errorf("describe clb %s: %w", clbName, err)
 	}
 	if len(out.LoadBalancerDescriptions) == 0 {
-		return nil, fmt.Errorf("clb %s not found", clbName)
+		return nil, &domain.NotFoundError{Kind: "clb", ID: clbName}
 	}
 
-	return toCLBData(&out.LoadBalancerDescriptions[0]), nil
+	data := toCLBData(&out.LoadBalancerDescriptions[0])
+	data.Region = c.region
+	// A Classic Load Balancer's interfaces are described as "ELB " followed
+	// by its name.
+	data.FrontendIPs, _, err = c.interfaceIPsByDescription(ctx, clbName, "ELB "+clbName)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (c *Client) GetCLBByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.CLBData, error) {
@@ -220,7 +329,9 @@ func (c *Client) GetCLBByPrivateIP(ctx context.Context, ip, vpcID string) (*doma
 		if lb.VPCId != nil && *lb.VPCId != vpcID {
 			continue
 		}
-		return toCLBData(&lb), nil
+		data := toCLBData(&lb)
+		data.Region = c.region
+		return data, nil
 	}
 	return nil, nil
 }
@@ -233,7 +344,7 @@ func (c *Client) GetTargetGroup(ctx context.Context, tgARN string) (*domain.Targ
 		return nil, fmt.Errorf("describe target group %s: %w", tgARN, err)
 	}
 	if len(out.TargetGroups) == 0 {
-		return nil, fmt.Errorf("target group %s not found", tgARN)
+		return nil, &domain.NotFoundError{Kind: "target group", ID: tgARN}
 	}
 
 	tg := &out.TargetGroups[0]
@@ -245,7 +356,16 @@ func (c *Client) GetTargetGroup(ctx context.Context, tgARN string) (*domain.Targ
 		return nil, fmt.Errorf("describe target health for %s: %w", tgARN, err)
 	}
 
-	return toTargetGroupData(tg, healthOut.TargetHealthDescriptions), nil
+	attrOut, err := c.elbv2Client.DescribeTargetGroupAttributes(ctx, &elbv2.DescribeTargetGroupAttributesInput{
+		TargetGroupArn: aws.String(tgARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe target group attributes for %s: %w", tgARN, err)
+	}
+
+	data := toTargetGroupData(tg, healthOut.TargetHealthDescriptions, attrOut.Attributes)
+	data.Region = c.region
+	return data, nil
 }
 
 func parseNLBNameFromDescription(desc string) string {
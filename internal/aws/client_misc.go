@@ -45,6 +45,8 @@ func (c *Client) GetEKSPodByIP(ctx context.Context, ip, vpcID string) (*domain.E
 					ENIID:          derefString(eni.NetworkInterfaceId),
 					SecurityGroups: sgs,
 					SubnetID:       derefString(eni.SubnetId),
+					VPCID:          derefString(eni.VpcId),
+					Region:         c.region,
 				}, nil
 			}
 		}
@@ -67,11 +69,12 @@ func (c *Client) GetElastiCacheCluster(ctx context.Context, clusterID string) (*
 		return nil, fmt.Errorf("describe elasticache cluster %s: %w", clusterID, err)
 	}
 	if len(out.CacheClusters) == 0 {
-		return nil, fmt.Errorf("elasticache cluster %s not found", clusterID)
+		return nil, &domain.NotFoundError{Kind: "elasticache cluster", ID: clusterID}
 	}
 
 	cluster := &out.CacheClusters[0]
 	data := toElastiCacheClusterData(cluster)
+	data.Region = c.region
 
 	if cluster.CacheSubnetGroupName != nil {
 		subnetOut, err := c.elasticacheClient.DescribeCacheSubnetGroups(ctx, &elasticache.DescribeCacheSubnetGroupsInput{
@@ -145,6 +148,7 @@ func (c *Client) GetDirectConnectGatewayAttachments(ctx context.Context, dxgwID
 			TransitGatewayID: "",
 			TGWAccountID:     derefString(att.VirtualInterfaceOwnerAccount),
 			State:            string(att.AttachmentState),
+			Region:           c.region,
 		})
 	}
 
@@ -171,7 +175,7 @@ func (c *Client) GetNetworkFirewall(ctx context.Context, firewallID string) (*do
 	}
 
 	if out.Firewall == nil {
-		return nil, fmt.Errorf("network firewall %s not found", firewallID)
+		return nil, &domain.NotFoundError{Kind: "network firewall", ID: firewallID}
 	}
 
 	fw := out.Firewall
@@ -180,6 +184,7 @@ func (c *Client) GetNetworkFirewall(ctx context.Context, firewallID string) (*do
 	var statelessGroups []domain.StatelessRuleGroup
 	var statefulGroups []domain.StatefulRuleGroup
 	var defaultActions domain.FirewallDefaultActions
+	variables := make(map[string][]string)
 
 	if policyARN != "" {
 		policyOut, err := c.networkFirewallClient.DescribeFirewallPolicy(ctx, &networkfirewall.DescribeFirewallPolicyInput{
@@ -197,6 +202,9 @@ func (c *Client) GetNetworkFirewall(ctx context.Context, firewallID string) (*do
 			for _, action := range policy.StatefulDefaultActions {
 				defaultActions.StatefulDefaultActions = append(defaultActions.StatefulDefaultActions, string(action))
 			}
+			if policy.PolicyVariables != nil {
+				variables = ipSetVariables(policy.PolicyVariables.RuleVariables)
+			}
 
 			for _, ref := range policy.StatelessRuleGroupReferences {
 				group, err := c.getStatelessRuleGroup(ctx, derefString(ref.ResourceArn))
@@ -233,6 +241,15 @@ func (c *Client) GetNetworkFirewall(ctx context.Context, firewallID string) (*do
 		}
 	}
 
+	// Without an override, Suricata's HOME_NET is the firewall VPC. If the VPC
+	// can't be read, HOME_NET stays undefined and rules using it can't be
+	// decided.
+	if _, ok := variables["HOME_NET"]; !ok {
+		if vpc, err := c.GetVPC(ctx, derefString(fw.VpcId)); err == nil {
+			variables["HOME_NET"] = vpcCIDRs(vpc)
+		}
+	}
+
 	data := &domain.NetworkFirewallData{
 		ID:                  derefString(fw.FirewallArn),
 		Name:                derefString(fw.FirewallName),
@@ -242,6 +259,8 @@ func (c *Client) GetNetworkFirewall(ctx context.Context, firewallID string) (*do
 		StatelessRuleGroups: statelessGroups,
 		StatefulRuleGroups:  statefulGroups,
 		DefaultActions:      defaultActions,
+		Variables:           variables,
+		Region:              c.region,
 	}
 
 	c.cache.set(key, data)
@@ -342,6 +361,10 @@ func (c *Client) getStatefulRuleGroup(ctx context.Context, arn string) (domain.S
 		}
 	}
 
+	if out.RuleGroup != nil && out.RuleGroup.RuleVariables != nil {
+		group.Variables = ipSetVariables(out.RuleGroup.RuleVariables.IPSets)
+	}
+
 	if out.RuleGroup != nil && out.RuleGroup.RulesSource != nil {
 		if out.RuleGroup.RulesSource.StatefulRules != nil {
 			for _, rule := range out.RuleGroup.RulesSource.StatefulRules {
@@ -400,5 +423,5 @@ func (c *Client) GetNetworkFirewallByEndpoint(ctx context.Context, endpointID st
 		}
 	}
 
-	return nil, fmt.Errorf("network firewall for endpoint %s not found", endpointID)
+	return nil, &domain.NotFoundError{Kind: "network firewall for endpoint", ID: endpointID}
 }
@@ -1,11 +1,15 @@
 package aws
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
+
+	"github.com/eleven-am/argus/internal/domain"
 )
 
 func TestNewRetryer(t *testing.T) {
@@ -54,6 +58,86 @@ func TestNewRetryer_IsErrorRetryable(t *testing.T) {
 	}
 }
 
+func TestAccountContext_GetClient_PoolsPerRegion(t *testing.T) {
+	accountCtx := NewAccountContext(aws.Config{Region: "us-east-1"}, "")
+	accountCtx.credentialCache["123456789012"] = credentialEntry{expiration: time.Now().Add(time.Hour)}
+
+	defaultClient, err := accountCtx.GetClient("123456789012", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sameRegion, err := accountCtx.GetClient("123456789012", "us-east-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaultClient != sameRegion {
+		t.Error("expected the default region and us-east-1 to share a client")
+	}
+
+	otherRegion, err := accountCtx.GetClient("123456789012", "eu-west-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if otherRegion == defaultClient {
+		t.Fatal("expected a separate client for eu-west-1")
+	}
+	if region := otherRegion.(*Client).region; region != "eu-west-1" {
+		t.Errorf("expected region = eu-west-1, got %s", region)
+	}
+}
+
+func TestAccountContext_GetClient_KeepsClientAcrossRenewals(t *testing.T) {
+	accountCtx := NewAccountContext(aws.Config{Region: "us-east-1"}, "")
+	accountCtx.credentialCache["123456789012"] = credentialEntry{
+		creds:      domain.AWSCredentials{AccessKeyID: "first"},
+		expiration: time.Now().Add(time.Hour),
+	}
+
+	clients := make([]domain.AWSClient, 8)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := accountCtx.GetClient("123456789012", "us-east-1")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			clients[i] = client
+		}(i)
+	}
+	wg.Wait()
+	for _, client := range clients[1:] {
+		if client != clients[0] {
+			t.Fatal("expected concurrent callers to share one client")
+		}
+	}
+
+	accountCtx.mu.Lock()
+	accountCtx.credentialCache["123456789012"] = credentialEntry{
+		creds:      domain.AWSCredentials{AccessKeyID: "second"},
+		expiration: time.Now().Add(2 * time.Hour),
+	}
+	accountCtx.mu.Unlock()
+
+	renewed, err := accountCtx.GetClient("123456789012", "us-east-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if renewed != clients[0] {
+		t.Error("expected renewed credentials to keep the pooled client")
+	}
+
+	provider := &accountCredentials{accounts: accountCtx, accountID: "123456789012"}
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "second" {
+		t.Errorf("expected the renewed credentials, got %s", creds.AccessKeyID)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	cfg := aws.Config{}
 	client := NewClient(cfg, "123456789012", "us-east-1")
@@ -24,7 +24,7 @@ func (c *Client) GetTransitGateway(ctx context.Context, tgwID string) (*domain.T
 		return nil, fmt.Errorf("describe transit gateway %s: %w", tgwID, err)
 	}
 	if len(out.TransitGateways) == 0 {
-		return nil, fmt.Errorf("transit gateway %s not found", tgwID)
+		return nil, &domain.NotFoundError{Kind: "transit gateway", ID: tgwID}
 	}
 
 	rts, err := c.fetchTGWRouteTables(ctx, tgwID)
@@ -33,6 +33,7 @@ func (c *Client) GetTransitGateway(ctx context.Context, tgwID string) (*domain.T
 	}
 
 	data := toTransitGatewayData(&out.TransitGateways[0], rts)
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -234,7 +235,7 @@ func (c *Client) GetTransitGatewayAttachment(ctx context.Context, vpcID, tgwID s
 		return nil, fmt.Errorf("describe tgw attachment for vpc %s tgw %s: %w", vpcID, tgwID, err)
 	}
 	if len(out.TransitGatewayVpcAttachments) == 0 {
-		return nil, fmt.Errorf("tgw attachment not found for vpc %s tgw %s", vpcID, tgwID)
+		return nil, &domain.NotFoundError{Kind: "tgw attachment", ID: fmt.Sprintf("for vpc %s tgw %s", vpcID, tgwID)}
 	}
 
 	att := &out.TransitGatewayVpcAttachments[0]
@@ -256,6 +257,7 @@ func (c *Client) GetTransitGatewayAttachment(ctx context.Context, vpcID, tgwID s
 	}
 
 	data := toTGWAttachmentData(att, tgwOwnerID, state, propagatedRTIDs)
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -272,7 +274,7 @@ func (c *Client) GetTransitGatewayAttachmentByID(ctx context.Context, attachment
 		return nil, fmt.Errorf("describe tgw attachment %s: %w", attachmentID, err)
 	}
 	if len(out.TransitGatewayVpcAttachments) == 0 {
-		return nil, fmt.Errorf("tgw attachment %s not found", attachmentID)
+		return nil, &domain.NotFoundError{Kind: "tgw attachment", ID: attachmentID}
 	}
 
 	att := &out.TransitGatewayVpcAttachments[0]
@@ -296,6 +298,7 @@ func (c *Client) GetTransitGatewayAttachmentByID(ctx context.Context, attachment
 	}
 
 	data := toTGWAttachmentData(att, tgwOwnerID, state, propagatedRTIDs)
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -393,7 +396,7 @@ func (c *Client) getPropagatedRouteTableIDs(ctx context.Context, tgwID, attachme
 	return propagated, err
 }
 
-func (c *Client) GetTGWPeeringAttachment(ctx context.Context, attachmentID string) (*domain.TGWPeeringAttachmentData, error) {
+func (c *Client) GetTGWPeeringAttachment(ctx context.Context, attachmentID, tgwID string) (*domain.TGWPeeringAttachmentData, error) {
 	out, err := c.ec2Client.DescribeTransitGatewayPeeringAttachments(ctx, &ec2.DescribeTransitGatewayPeeringAttachmentsInput{
 		TransitGatewayAttachmentIds: []string{attachmentID},
 	})
@@ -401,13 +404,50 @@ func (c *Client) GetTGWPeeringAttachment(ctx context.Context, attachmentID strin
 		return nil, fmt.Errorf("describe tgw peering attachment %s: %w", attachmentID, err)
 	}
 	if len(out.TransitGatewayPeeringAttachments) == 0 {
-		return nil, fmt.Errorf("tgw peering attachment %s not found", attachmentID)
-	}
-	peering := &out.TransitGatewayPeeringAttachments[0]
-	return &domain.TGWPeeringAttachmentData{
-		ID:                   derefString(peering.TransitGatewayAttachmentId),
-		TransitGatewayID:     derefString(peering.RequesterTgwInfo.TransitGatewayId),
-		PeerTransitGatewayID: derefString(peering.AccepterTgwInfo.TransitGatewayId),
-		PeerAccountID:        derefString(peering.AccepterTgwInfo.OwnerId),
-	}, nil
+		return nil, &domain.NotFoundError{Kind: "tgw peering attachment", ID: attachmentID}
+	}
+	data := toTGWPeeringAttachmentData(&out.TransitGatewayPeeringAttachments[0], tgwID)
+	data.Region = c.region
+	return data, nil
+}
+
+func (c *Client) GetTGWConnectAttachment(ctx context.Context, attachmentID string) (*domain.TGWConnectAttachmentData, error) {
+	key := c.cacheKey("tgw-connect", attachmentID)
+	if v, ok := c.cache.get(key); ok {
+		return v.(*domain.TGWConnectAttachmentData), nil
+	}
+
+	out, err := c.ec2Client.DescribeTransitGatewayConnects(ctx, &ec2.DescribeTransitGatewayConnectsInput{
+		TransitGatewayAttachmentIds: []string{attachmentID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe tgw connect attachment %s: %w", attachmentID, err)
+	}
+	if len(out.TransitGatewayConnects) == 0 {
+		return nil, &domain.NotFoundError{Kind: "tgw connect attachment", ID: attachmentID}
+	}
+
+	paginator := ec2.NewDescribeTransitGatewayConnectPeersPaginator(c.ec2Client, &ec2.DescribeTransitGatewayConnectPeersInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("transit-gateway-attachment-id"), Values: []string{attachmentID}},
+		},
+	})
+	peers, err := CollectPages(
+		ctx,
+		paginator.HasMorePages,
+		func(ctx context.Context) (*ec2.DescribeTransitGatewayConnectPeersOutput, error) {
+			return paginator.NextPage(ctx)
+		},
+		func(out *ec2.DescribeTransitGatewayConnectPeersOutput) []ec2types.TransitGatewayConnectPeer {
+			return out.TransitGatewayConnectPeers
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("describe tgw connect peers for %s: %w", attachmentID, err)
+	}
+
+	data := toTGWConnectAttachmentData(&out.TransitGatewayConnects[0], peers)
+	data.Region = c.region
+	c.cache.set(key, data)
+	return data, nil
 }
@@ -23,9 +23,10 @@ func (c *Client) GetSecurityGroup(ctx context.Context, sgID string) (*domain.Sec
 		return nil, fmt.Errorf("describe security group %s: %w", sgID, err)
 	}
 	if len(out.SecurityGroups) == 0 {
-		return nil, fmt.Errorf("security group %s not found", sgID)
+		return nil, &domain.NotFoundError{Kind: "security group", ID: sgID}
 	}
 	data := toSecurityGroupData(&out.SecurityGroups[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -42,7 +43,7 @@ func (c *Client) GetSubnet(ctx context.Context, subnetID string) (*domain.Subnet
 		return nil, fmt.Errorf("describe subnet %s: %w", subnetID, err)
 	}
 	if len(subnetOut.Subnets) == 0 {
-		return nil, fmt.Errorf("subnet %s not found", subnetID)
+		return nil, &domain.NotFoundError{Kind: "subnet", ID: subnetID}
 	}
 	subnet := &subnetOut.Subnets[0]
 
@@ -57,10 +58,42 @@ func (c *Client) GetSubnet(ctx context.Context, subnetID string) (*domain.Subnet
 	}
 
 	data := toSubnetData(subnet, naclID, rtID)
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
 
+// GetSubnetByIP returns the subnet of vpcID whose IPv4 or IPv6 CIDR contains
+// ip, or nil if none does.
+func (c *Client) GetSubnetByIP(ctx context.Context, ip, vpcID string) (*domain.SubnetData, error) {
+	input := &ec2.DescribeSubnetsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+		},
+	}
+	paginator := ec2.NewDescribeSubnetsPaginator(c.ec2Client, input)
+	subnets, err := CollectPages(
+		ctx,
+		paginator.HasMorePages,
+		func(ctx context.Context) (*ec2.DescribeSubnetsOutput, error) {
+			return paginator.NextPage(ctx)
+		},
+		func(out *ec2.DescribeSubnetsOutput) []ec2types.Subnet {
+			return out.Subnets
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("describe subnets for vpc %s: %w", vpcID, err)
+	}
+
+	for i := range subnets {
+		if subnetContainsIP(&subnets[i], ip) {
+			return c.GetSubnet(ctx, derefString(subnets[i].SubnetId))
+		}
+	}
+	return nil, nil
+}
+
 func (c *Client) findNACLForSubnet(ctx context.Context, subnetID string) (string, error) {
 	out, err := c.ec2Client.DescribeNetworkAcls(ctx, &ec2.DescribeNetworkAclsInput{
 		Filters: []ec2types.Filter{
@@ -116,9 +149,10 @@ func (c *Client) GetNACL(ctx context.Context, naclID string) (*domain.NACLData,
 		return nil, fmt.Errorf("describe network acl %s: %w", naclID, err)
 	}
 	if len(out.NetworkAcls) == 0 {
-		return nil, fmt.Errorf("network acl %s not found", naclID)
+		return nil, &domain.NotFoundError{Kind: "network acl", ID: naclID}
 	}
 	data := toNACLData(&out.NetworkAcls[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -135,13 +169,88 @@ func (c *Client) GetRouteTable(ctx context.Context, rtID string) (*domain.RouteT
 		return nil, fmt.Errorf("describe route table %s: %w", rtID, err)
 	}
 	if len(out.RouteTables) == 0 {
-		return nil, fmt.Errorf("route table %s not found", rtID)
+		return nil, &domain.NotFoundError{Kind: "route table", ID: rtID}
 	}
 	data := toRouteTableData(&out.RouteTables[0])
+	data.Region = c.region
+	if c.classifyPropagatedRoutes(ctx, data) {
+		c.cache.set(key, data)
+	}
+	return data, nil
+}
+
+// classifyPropagatedRoutes records where each route propagated into rt was
+// learned, which decides its priority against other routes to the same prefix.
+// A route whose gateway's VPN connections cannot be read carries the error
+// instead of failing the whole table, and classifyPropagatedRoutes reports
+// false so that the table is not cached with it.
+func (c *Client) classifyPropagatedRoutes(ctx context.Context, rt *domain.RouteTableData) bool {
+	vpnsByVGW := make(map[string][]*domain.VPNConnectionData)
+	unreadable := make(map[string]error)
+	for i := range rt.Routes {
+		route := &rt.Routes[i]
+		if route.Origin != "propagated" || route.TargetType != "vpn-gateway" {
+			continue
+		}
+		if err, ok := unreadable[route.TargetID]; ok {
+			route.PropagationErr = err
+			continue
+		}
+		vpns, ok := vpnsByVGW[route.TargetID]
+		if !ok {
+			var err error
+			vpns, err = c.GetVPNConnectionsByVGW(ctx, route.TargetID)
+			if err != nil {
+				unreadable[route.TargetID] = err
+				route.PropagationErr = err
+				continue
+			}
+			vpnsByVGW[route.TargetID] = vpns
+		}
+		route.PropagatedFrom = propagationSource(*route, vpns)
+	}
+	return len(unreadable) == 0
+}
+
+// GetGatewayRouteTable returns the route table edge-associated with an
+// internet or virtual private gateway, or nil when there is none. Most gateways
+// have none, so the absence is cached too.
+func (c *Client) GetGatewayRouteTable(ctx context.Context, gatewayID string) (*domain.RouteTableData, error) {
+	key := c.cacheKey("rt-gateway", gatewayID)
+	if v, ok := c.cache.get(key); ok {
+		return v.(*domain.RouteTableData), nil
+	}
+	out, err := c.ec2Client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("association.gateway-id"), Values: []string{gatewayID}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe route table for gateway %s: %w", gatewayID, err)
+	}
+	var data *domain.RouteTableData
+	for i := range out.RouteTables {
+		candidate := toRouteTableData(&out.RouteTables[i])
+		if associatedWithGateway(candidate, gatewayID) {
+			data = candidate
+			data.Region = c.region
+			break
+		}
+	}
 	c.cache.set(key, data)
 	return data, nil
 }
 
+// associatedWithGateway reports whether rt is edge-associated with gatewayID.
+func associatedWithGateway(rt *domain.RouteTableData, gatewayID string) bool {
+	for _, association := range rt.Associations {
+		if association.GatewayID == gatewayID {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) GetVPC(ctx context.Context, vpcID string) (*domain.VPCData, error) {
 	key := c.cacheKey("vpc", vpcID)
 	if v, ok := c.cache.get(key); ok {
@@ -154,11 +263,12 @@ func (c *Client) GetVPC(ctx context.Context, vpcID string) (*domain.VPCData, err
 		return nil, fmt.Errorf("describe vpc %s: %w", vpcID, err)
 	}
 	if len(out.Vpcs) == 0 {
-		return nil, fmt.Errorf("vpc %s not found", vpcID)
+		return nil, &domain.NotFoundError{Kind: "vpc", ID: vpcID}
 	}
 
 	mainRtID, _ := c.findMainRouteTable(ctx, vpcID)
 	data := toVPCData(&out.Vpcs[0], mainRtID)
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -191,9 +301,34 @@ func (c *Client) GetInternetGateway(ctx context.Context, igwID string) (*domain.
 		return nil, fmt.Errorf("describe internet gateway %s: %w", igwID, err)
 	}
 	if len(out.InternetGateways) == 0 {
-		return nil, fmt.Errorf("internet gateway %s not found", igwID)
+		return nil, &domain.NotFoundError{Kind: "internet gateway", ID: igwID}
+	}
+	data := toInternetGatewayData(&out.InternetGateways[0])
+	data.Region = c.region
+	c.cache.set(key, data)
+	return data, nil
+}
+
+// GetInternetGatewayByVPC returns the internet gateway attached to vpcID, or
+// nil if it has none.
+func (c *Client) GetInternetGatewayByVPC(ctx context.Context, vpcID string) (*domain.InternetGatewayData, error) {
+	key := c.cacheKey("igw-by-vpc", vpcID)
+	if v, ok := c.cache.get(key); ok {
+		return v.(*domain.InternetGatewayData), nil
+	}
+	out, err := c.ec2Client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("attachment.vpc-id"), Values: []string{vpcID}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe internet gateway for vpc %s: %w", vpcID, err)
+	}
+	if len(out.InternetGateways) == 0 {
+		return nil, nil
 	}
 	data := toInternetGatewayData(&out.InternetGateways[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -210,9 +345,10 @@ func (c *Client) GetEgressOnlyInternetGateway(ctx context.Context, eigwID string
 		return nil, fmt.Errorf("describe egress-only internet gateway %s: %w", eigwID, err)
 	}
 	if len(out.EgressOnlyInternetGateways) == 0 {
-		return nil, fmt.Errorf("egress-only internet gateway %s not found", eigwID)
+		return nil, &domain.NotFoundError{Kind: "egress-only internet gateway", ID: eigwID}
 	}
 	data := toEgressOnlyInternetGatewayData(&out.EgressOnlyInternetGateways[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -229,9 +365,10 @@ func (c *Client) GetNATGateway(ctx context.Context, natID string) (*domain.NATGa
 		return nil, fmt.Errorf("describe nat gateway %s: %w", natID, err)
 	}
 	if len(out.NatGateways) == 0 {
-		return nil, fmt.Errorf("nat gateway %s not found", natID)
+		return nil, &domain.NotFoundError{Kind: "nat gateway", ID: natID}
 	}
 	data := toNATGatewayData(&out.NatGateways[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -248,9 +385,10 @@ func (c *Client) GetVPCEndpoint(ctx context.Context, endpointID string) (*domain
 		return nil, fmt.Errorf("describe vpc endpoint %s: %w", endpointID, err)
 	}
 	if len(out.VpcEndpoints) == 0 {
-		return nil, fmt.Errorf("vpc endpoint %s not found", endpointID)
+		return nil, &domain.NotFoundError{Kind: "vpc endpoint", ID: endpointID}
 	}
 	data := toVPCEndpointData(&out.VpcEndpoints[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -267,9 +405,10 @@ func (c *Client) GetVPCPeering(ctx context.Context, peeringID string) (*domain.V
 		return nil, fmt.Errorf("describe vpc peering %s: %w", peeringID, err)
 	}
 	if len(out.VpcPeeringConnections) == 0 {
-		return nil, fmt.Errorf("vpc peering %s not found", peeringID)
+		return nil, &domain.NotFoundError{Kind: "vpc peering", ID: peeringID}
 	}
 	data := toVPCPeeringData(&out.VpcPeeringConnections[0])
+	data.Region = c.region
 	c.cache.set(key, data)
 	return data, nil
 }
@@ -286,7 +425,7 @@ func (c *Client) GetManagedPrefixList(ctx context.Context, prefixListID string)
 		return nil, fmt.Errorf("describe managed prefix list %s: %w", prefixListID, err)
 	}
 	if len(out.PrefixLists) == 0 {
-		return nil, fmt.Errorf("managed prefix list %s not found", prefixListID)
+		return nil, &domain.NotFoundError{Kind: "managed prefix list", ID: prefixListID}
 	}
 
 	entriesOut, err := c.ec2Client.GetManagedPrefixListEntries(ctx, &ec2.GetManagedPrefixListEntriesInput{
@@ -2,14 +2,17 @@ package aws
 
 import (
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 
+	dxtypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
 	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	nfwtypes "github.com/aws/aws-sdk-go-v2/service/networkfirewall/types"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -92,6 +95,23 @@ func toSubnetData(subnet *ec2types.Subnet, naclID, rtID string) *domain.SubnetDa
 	}
 }
 
+func subnetContainsIP(subnet *ec2types.Subnet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	cidrs := []string{derefString(subnet.CidrBlock)}
+	for _, assoc := range subnet.Ipv6CidrBlockAssociationSet {
+		cidrs = append(cidrs, derefString(assoc.Ipv6CidrBlock))
+	}
+	for _, cidr := range cidrs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
 func toNACLData(nacl *ec2types.NetworkAcl) *domain.NACLData {
 	var inbound, outbound []domain.NACLRule
 	for _, entry := range nacl.Entries {
@@ -136,12 +156,28 @@ func toRouteTableData(rt *ec2types.RouteTable) *domain.RouteTableData {
 		}
 
 		route.TargetType, route.TargetID = determineRouteTarget(r)
+		route.State = string(r.State)
+		route.Origin = routeOrigin(r.Origin)
 		routes = append(routes, route)
 	}
+
+	var associations []domain.RouteTableAssociation
+	for _, a := range rt.Associations {
+		if a.AssociationState != nil && a.AssociationState.State != ec2types.RouteTableAssociationStateCodeAssociated {
+			continue
+		}
+		associations = append(associations, domain.RouteTableAssociation{
+			SubnetID:  derefString(a.SubnetId),
+			GatewayID: derefString(a.GatewayId),
+			Main:      a.Main != nil && *a.Main,
+		})
+	}
+
 	return &domain.RouteTableData{
-		ID:     derefString(rt.RouteTableId),
-		VPCID:  derefString(rt.VpcId),
-		Routes: routes,
+		ID:           derefString(rt.RouteTableId),
+		VPCID:        derefString(rt.VpcId),
+		Routes:       routes,
+		Associations: associations,
 	}
 }
 
@@ -155,6 +191,8 @@ func determineRouteTarget(r ec2types.Route) (targetType, targetID string) {
 		return "vpc-endpoint", *r.GatewayId
 	case r.GatewayId != nil && *r.GatewayId == "local":
 		return "local", "local"
+	case r.EgressOnlyInternetGatewayId != nil:
+		return "egress-only-internet-gateway", *r.EgressOnlyInternetGatewayId
 	case r.NatGatewayId != nil:
 		return "nat-gateway", *r.NatGatewayId
 	case r.TransitGatewayId != nil:
@@ -172,20 +210,121 @@ func determineRouteTarget(r ec2types.Route) (targetType, targetID string) {
 	}
 }
 
+// routeOrigin names where a route came from: the VPC's own local route, a
+// route added to the table, or one propagated by a virtual private gateway.
+func routeOrigin(origin ec2types.RouteOrigin) string {
+	switch origin {
+	case ec2types.RouteOriginCreateRouteTable:
+		return "local"
+	case ec2types.RouteOriginEnableVgwRoutePropagation:
+		return "propagated"
+	case ec2types.RouteOriginCreateRoute:
+		return "static"
+	default:
+		return ""
+	}
+}
+
+// propagationSource returns where a route the gateway propagated was learned,
+// given the gateway's VPN connections. A route that matches a static route of
+// one of the connections came from that VPN. Otherwise it was learned over BGP,
+// from a dynamic VPN when the gateway has one and from Direct Connect when it
+// has none.
+func propagationSource(route domain.Route, vpns []*domain.VPNConnectionData) string {
+	dynamic := false
+	for _, vpn := range vpns {
+		if vpn.State != "available" {
+			continue
+		}
+		for _, cidr := range vpn.StaticRoutes {
+			if cidr == route.DestinationCIDR || cidr == route.DestinationIPv6CIDR {
+				return "vpn-static"
+			}
+		}
+		if !vpn.StaticRoutesOnly {
+			dynamic = true
+		}
+	}
+	if dynamic {
+		return "vpn-bgp"
+	}
+	return "direct-connect"
+}
+
+func toVPNConnectionData(vpn *ec2types.VpnConnection) *domain.VPNConnectionData {
+	data := &domain.VPNConnectionData{
+		ID:    derefString(vpn.VpnConnectionId),
+		VGWID: derefString(vpn.VpnGatewayId),
+		State: string(vpn.State),
+	}
+	for _, tel := range vpn.VgwTelemetry {
+		if tel.Status != ec2types.TelemetryStatusUp {
+			continue
+		}
+		data.HasUpTunnel = true
+		if tel.AcceptedRouteCount != nil {
+			data.AcceptedRouteCount += int(*tel.AcceptedRouteCount)
+		}
+	}
+	if vpn.Options != nil && vpn.Options.StaticRoutesOnly != nil {
+		data.StaticRoutesOnly = *vpn.Options.StaticRoutesOnly
+	}
+	for _, r := range vpn.Routes {
+		if r.State == ec2types.VpnStateDeleted || r.State == ec2types.VpnStateDeleting {
+			continue
+		}
+		if cidr := derefString(r.DestinationCidrBlock); cidr != "" {
+			data.StaticRoutes = append(data.StaticRoutes, cidr)
+		}
+	}
+	return data
+}
+
 func toVPCData(vpc *ec2types.Vpc, mainRtID string) *domain.VPCData {
-	var ipv6CIDR string
+	var secondaryCIDRs []string
+	for _, assoc := range vpc.CidrBlockAssociationSet {
+		cidr := derefString(assoc.CidrBlock)
+		if cidr == "" || cidr == derefString(vpc.CidrBlock) {
+			continue
+		}
+		if assoc.CidrBlockState == nil || assoc.CidrBlockState.State != ec2types.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		secondaryCIDRs = append(secondaryCIDRs, cidr)
+	}
+	var ipv6CIDRs []string
 	for _, assoc := range vpc.Ipv6CidrBlockAssociationSet {
 		if assoc.Ipv6CidrBlock != nil {
-			ipv6CIDR = *assoc.Ipv6CidrBlock
-			break
+			ipv6CIDRs = append(ipv6CIDRs, *assoc.Ipv6CidrBlock)
 		}
 	}
 	return &domain.VPCData{
-		ID:               derefString(vpc.VpcId),
-		CIDRBlock:        derefString(vpc.CidrBlock),
-		IPv6CIDRBlock:    ipv6CIDR,
-		MainRouteTableID: mainRtID,
+		ID:                  derefString(vpc.VpcId),
+		CIDRBlock:           derefString(vpc.CidrBlock),
+		SecondaryCIDRBlocks: secondaryCIDRs,
+		IPv6CIDRBlocks:      ipv6CIDRs,
+		MainRouteTableID:    mainRtID,
+	}
+}
+
+// vpcCIDRs returns every IPv4 and IPv6 CIDR of vpc.
+func vpcCIDRs(vpc *domain.VPCData) []string {
+	var cidrs []string
+	if vpc.CIDRBlock != "" {
+		cidrs = append(cidrs, vpc.CIDRBlock)
 	}
+	cidrs = append(cidrs, vpc.SecondaryCIDRBlocks...)
+	return append(cidrs, vpc.IPv6CIDRBlocks...)
+}
+
+// ipSetVariables converts Network Firewall IP set variables to their CIDRs by
+// name.
+func ipSetVariables(sets map[string]nfwtypes.IPSet) map[string][]string {
+	variables := make(map[string][]string, len(sets))
+	for name, set := range sets {
+		variables[name] = set.Definition
+	}
+	return variables
 }
 
 func toTransitGatewayData(tgw *ec2types.TransitGateway, rts []domain.TGWRouteTableData) *domain.TransitGatewayData {
@@ -215,9 +354,89 @@ func toTGWAttachmentData(att *ec2types.TransitGatewayVpcAttachment, tgwOwnerID,
 		SubnetIDs:               subnets,
 		State:                   state,
 		PropagatedRouteTableIDs: propagatedRTIDs,
+		ApplianceModeSupport:    att.Options != nil && att.Options.ApplianceModeSupport == ec2types.ApplianceModeSupportValueEnable,
 	}
 }
 
+// toTGWPeeringAttachmentData converts a peering attachment as seen from
+// tgwID. The local side is the one that transit gateway is on, which region
+// alone can't tell when both are in the same region; the requester is local
+// when tgwID is on neither side.
+func toTGWPeeringAttachmentData(peering *ec2types.TransitGatewayPeeringAttachment, tgwID string) *domain.TGWPeeringAttachmentData {
+	local, peer := peering.RequesterTgwInfo, peering.AccepterTgwInfo
+	if local == nil {
+		local = &ec2types.PeeringTgwInfo{}
+	}
+	if peer == nil {
+		peer = &ec2types.PeeringTgwInfo{}
+	}
+	if derefString(local.TransitGatewayId) != tgwID && derefString(peer.TransitGatewayId) == tgwID {
+		local, peer = peer, local
+	}
+	return &domain.TGWPeeringAttachmentData{
+		ID:                   derefString(peering.TransitGatewayAttachmentId),
+		TransitGatewayID:     derefString(local.TransitGatewayId),
+		PeerTransitGatewayID: derefString(peer.TransitGatewayId),
+		PeerAccountID:        derefString(peer.OwnerId),
+		PeerRegion:           derefString(peer.Region),
+	}
+}
+
+func toTGWConnectAttachmentData(connect *ec2types.TransitGatewayConnect, peers []ec2types.TransitGatewayConnectPeer) *domain.TGWConnectAttachmentData {
+	data := &domain.TGWConnectAttachmentData{
+		ID:                    derefString(connect.TransitGatewayAttachmentId),
+		TransitGatewayID:      derefString(connect.TransitGatewayId),
+		TransportAttachmentID: derefString(connect.TransportTransitGatewayAttachmentId),
+		State:                 string(connect.State),
+	}
+	for _, p := range peers {
+		peer := domain.TGWConnectPeerData{
+			ID:    derefString(p.TransitGatewayConnectPeerId),
+			State: string(p.State),
+		}
+		if cfg := p.ConnectPeerConfiguration; cfg != nil {
+			peer.PeerAddress = derefString(cfg.PeerAddress)
+			for _, bgp := range cfg.BgpConfigurations {
+				if bgp.BgpStatus == ec2types.BgpStatusUp {
+					peer.BGPUp = true
+				}
+			}
+		}
+		data.Peers = append(data.Peers, peer)
+	}
+	return data
+}
+
+// toDirectConnectGatewayData converts a Direct Connect gateway and its gateway
+// associations. Only associations in the associated state advertise prefixes.
+func toDirectConnectGatewayData(dxgw *dxtypes.DirectConnectGateway, associations []dxtypes.DirectConnectGatewayAssociation) *domain.DirectConnectGatewayData {
+	data := &domain.DirectConnectGatewayData{
+		ID:      derefString(dxgw.DirectConnectGatewayId),
+		OwnerID: derefString(dxgw.OwnerAccount),
+		State:   string(dxgw.DirectConnectGatewayState),
+	}
+	for _, association := range associations {
+		if association.AssociationState != dxtypes.DirectConnectGatewayAssociationStateAssociated {
+			continue
+		}
+		gatewayID := derefString(association.VirtualGatewayId)
+		if association.AssociatedGateway != nil && association.AssociatedGateway.Id != nil {
+			gatewayID = *association.AssociatedGateway.Id
+		}
+		if data.AllowedPrefixes == nil {
+			data.AllowedPrefixes = make(map[string][]string)
+		}
+		prefixes := []string{}
+		for _, prefix := range association.AllowedPrefixesToDirectConnectGateway {
+			if cidr := derefString(prefix.Cidr); cidr != "" {
+				prefixes = append(prefixes, cidr)
+			}
+		}
+		data.AllowedPrefixes[gatewayID] = append(data.AllowedPrefixes[gatewayID], prefixes...)
+	}
+	return data
+}
+
 func toEC2InstanceData(inst *ec2types.Instance) *domain.EC2InstanceData {
 	var sgs []string
 	for _, sg := range inst.SecurityGroups {
@@ -228,9 +447,33 @@ func toEC2InstanceData(inst *ec2types.Instance) *domain.EC2InstanceData {
 	return &domain.EC2InstanceData{
 		ID:             derefString(inst.InstanceId),
 		PrivateIP:      derefString(inst.PrivateIpAddress),
+		IPv6Addresses:  instanceIPv6Addresses(inst),
 		SecurityGroups: sgs,
 		SubnetID:       derefString(inst.SubnetId),
+		VPCID:          derefString(inst.VpcId),
+	}
+}
+
+// instanceIPv6Addresses returns the IPv6 addresses of the instance's primary
+// network interface, its primary IPv6 address first.
+func instanceIPv6Addresses(inst *ec2types.Instance) []string {
+	var addresses []string
+	for _, eni := range inst.NetworkInterfaces {
+		if eni.Attachment == nil || derefInt32(eni.Attachment.DeviceIndex) != 0 {
+			continue
+		}
+		for _, addr := range eni.Ipv6Addresses {
+			if addr.Ipv6Address == nil {
+				continue
+			}
+			if addr.IsPrimaryIpv6 != nil && *addr.IsPrimaryIpv6 {
+				addresses = append([]string{*addr.Ipv6Address}, addresses...)
+			} else {
+				addresses = append(addresses, *addr.Ipv6Address)
+			}
+		}
 	}
+	return addresses
 }
 
 func toRDSInstanceData(db *rdstypes.DBInstance, privateIP string) *domain.RDSInstanceData {
@@ -241,7 +484,9 @@ func toRDSInstanceData(db *rdstypes.DBInstance, privateIP string) *domain.RDSIns
 		}
 	}
 	var subnets []string
+	vpcID := ""
 	if db.DBSubnetGroup != nil {
+		vpcID = derefString(db.DBSubnetGroup.VpcId)
 		for _, subnet := range db.DBSubnetGroup.Subnets {
 			if subnet.SubnetIdentifier != nil {
 				subnets = append(subnets, *subnet.SubnetIdentifier)
@@ -263,6 +508,7 @@ func toRDSInstanceData(db *rdstypes.DBInstance, privateIP string) *domain.RDSIns
 		Port:           port,
 		SecurityGroups: sgs,
 		SubnetIDs:      subnets,
+		VPCID:          vpcID,
 	}
 }
 
@@ -274,6 +520,7 @@ func toLambdaFunctionData(fn *lambda.GetFunctionOutput) *domain.LambdaFunctionDa
 		data.VPCID = derefString(fn.Configuration.VpcConfig.VpcId)
 		data.SubnetIDs = fn.Configuration.VpcConfig.SubnetIds
 		data.SecurityGroups = fn.Configuration.VpcConfig.SecurityGroupIds
+		data.DualStack = fn.Configuration.VpcConfig.Ipv6AllowedForDualStack != nil && *fn.Configuration.VpcConfig.Ipv6AllowedForDualStack
 	}
 	return data
 }
@@ -301,17 +548,22 @@ func toEgressOnlyInternetGatewayData(eigw *ec2types.EgressOnlyInternetGateway) *
 }
 
 func toNATGatewayData(nat *ec2types.NatGateway) *domain.NATGatewayData {
-	var publicIP string
+	var publicIP, privateIP string
 	for _, addr := range nat.NatGatewayAddresses {
-		if addr.PublicIp != nil {
+		if publicIP == "" && addr.PublicIp != nil {
 			publicIP = *addr.PublicIp
-			break
+		}
+		primary := addr.IsPrimary != nil && *addr.IsPrimary
+		if addr.PrivateIp != nil && (privateIP == "" || primary) {
+			privateIP = *addr.PrivateIp
 		}
 	}
 	return &domain.NATGatewayData{
-		ID:       derefString(nat.NatGatewayId),
-		SubnetID: derefString(nat.SubnetId),
-		PublicIP: publicIP,
+		ID:               derefString(nat.NatGatewayId),
+		SubnetID:         derefString(nat.SubnetId),
+		PublicIP:         publicIP,
+		PrivateIP:        privateIP,
+		ConnectivityType: string(nat.ConnectivityType),
 	}
 }
 
@@ -343,10 +595,12 @@ func toVPCPeeringData(pcx *ec2types.VpcPeeringConnection) *domain.VPCPeeringData
 	if pcx.RequesterVpcInfo != nil {
 		data.RequesterVPC = derefString(pcx.RequesterVpcInfo.VpcId)
 		data.RequesterOwner = derefString(pcx.RequesterVpcInfo.OwnerId)
+		data.RequesterRegion = derefString(pcx.RequesterVpcInfo.Region)
 	}
 	if pcx.AccepterVpcInfo != nil {
 		data.AccepterVPC = derefString(pcx.AccepterVpcInfo.VpcId)
 		data.AccepterOwner = derefString(pcx.AccepterVpcInfo.OwnerId)
+		data.AccepterRegion = derefString(pcx.AccepterVpcInfo.Region)
 	}
 	if pcx.Status != nil {
 		data.Status = string(pcx.Status.Code)
@@ -392,6 +646,58 @@ func protocolNumberToString(proto string) string {
 	}
 }
 
+// toListenerData converts a listener together with the rules of an HTTP or
+// HTTPS listener. Redirect and fixed-response actions forward nowhere, so a
+// listener made only of them has no target groups.
+func toListenerData(listener *elbv2types.Listener, rules []elbv2types.Rule) domain.ListenerData {
+	data := domain.ListenerData{
+		ARN:             derefString(listener.ListenerArn),
+		Port:            int(derefInt32(listener.Port)),
+		Protocol:        string(listener.Protocol),
+		TargetGroupARNs: forwardTargetGroupARNs(listener.DefaultActions),
+	}
+	for _, rule := range rules {
+		for _, arn := range forwardTargetGroupARNs(rule.Actions) {
+			if !slices.Contains(data.TargetGroupARNs, arn) {
+				data.TargetGroupARNs = append(data.TargetGroupARNs, arn)
+			}
+		}
+	}
+	return data
+}
+
+func forwardTargetGroupARNs(actions []elbv2types.Action) []string {
+	var arns []string
+	add := func(arn *string) {
+		if arn != nil && !slices.Contains(arns, *arn) {
+			arns = append(arns, *arn)
+		}
+	}
+	for _, action := range actions {
+		add(action.TargetGroupArn)
+		if action.ForwardConfig != nil {
+			for _, tg := range action.ForwardConfig.TargetGroups {
+				add(tg.TargetGroupArn)
+			}
+		}
+	}
+	return arns
+}
+
+// listenerTargetGroupARNs returns the target groups any of listeners forwards
+// to.
+func listenerTargetGroupARNs(listeners []domain.ListenerData) []string {
+	var arns []string
+	for _, listener := range listeners {
+		for _, arn := range listener.TargetGroupARNs {
+			if !slices.Contains(arns, arn) {
+				arns = append(arns, arn)
+			}
+		}
+	}
+	return arns
+}
+
 func toALBData(lb *elbv2types.LoadBalancer, tgARNs []string) *domain.ALBData {
 	var subnets []string
 	for _, az := range lb.AvailabilityZones {
@@ -479,7 +785,7 @@ func toCLBData(lb *elbtypes.LoadBalancerDescription) *domain.CLBData {
 	}
 }
 
-func toTargetGroupData(tg *elbv2types.TargetGroup, healthDescs []elbv2types.TargetHealthDescription) *domain.TargetGroupData {
+func toTargetGroupData(tg *elbv2types.TargetGroup, healthDescs []elbv2types.TargetHealthDescription, attrs []elbv2types.TargetGroupAttribute) *domain.TargetGroupData {
 	var targets []domain.TargetData
 	for _, h := range healthDescs {
 		if h.Target != nil {
@@ -488,23 +794,41 @@ func toTargetGroupData(tg *elbv2types.TargetGroup, healthDescs []elbv2types.Targ
 				status = string(h.TargetHealth.State)
 			}
 			targets = append(targets, domain.TargetData{
-				ID:           derefString(h.Target.Id),
-				Port:         int(derefInt32(h.Target.Port)),
-				HealthStatus: status,
+				ID:               derefString(h.Target.Id),
+				Port:             int(derefInt32(h.Target.Port)),
+				HealthStatus:     status,
+				AvailabilityZone: derefString(h.Target.AvailabilityZone),
 			})
 		}
 	}
 	return &domain.TargetGroupData{
-		ARN:        derefString(tg.TargetGroupArn),
-		Name:       derefString(tg.TargetGroupName),
-		TargetType: string(tg.TargetType),
-		Protocol:   string(tg.Protocol),
-		Port:       int(derefInt32(tg.Port)),
-		VPCID:      derefString(tg.VpcId),
-		Targets:    targets,
+		ARN:              derefString(tg.TargetGroupArn),
+		Name:             derefString(tg.TargetGroupName),
+		TargetType:       string(tg.TargetType),
+		Protocol:         string(tg.Protocol),
+		Port:             int(derefInt32(tg.Port)),
+		VPCID:            derefString(tg.VpcId),
+		Targets:          targets,
+		PreserveClientIP: preserveClientIP(tg, attrs),
 	}
 }
 
+// preserveClientIP reads the preserve_client_ip.enabled attribute, falling back
+// to the default when it is absent: on for instance targets and UDP, off for IP
+// targets over TCP or TLS.
+func preserveClientIP(tg *elbv2types.TargetGroup, attrs []elbv2types.TargetGroupAttribute) bool {
+	for _, attr := range attrs {
+		if derefString(attr.Key) == "preserve_client_ip.enabled" {
+			return derefString(attr.Value) == "true"
+		}
+	}
+	switch tg.Protocol {
+	case elbv2types.ProtocolEnumUdp, elbv2types.ProtocolEnumTcpUdp:
+		return true
+	}
+	return tg.TargetType == elbv2types.TargetTypeEnumInstance
+}
+
 func toENIData(eni *ec2types.NetworkInterface) *domain.ENIData {
 	var privateIPs []string
 	for _, addr := range eni.PrivateIpAddresses {
@@ -516,12 +840,33 @@ func toENIData(eni *ec2types.NetworkInterface) *domain.ENIData {
 		sgs = append(sgs, derefString(sg.GroupId))
 	}
 
+	var instanceID string
+	if eni.Attachment != nil {
+		instanceID = derefString(eni.Attachment.InstanceId)
+	}
+
+	var publicIP string
+	if eni.Association != nil {
+		publicIP = derefString(eni.Association.PublicIp)
+	}
+
+	var ipv6Addresses []string
+	for _, addr := range eni.Ipv6Addresses {
+		ipv6Addresses = append(ipv6Addresses, derefString(addr.Ipv6Address))
+	}
+
 	return &domain.ENIData{
 		ID:             derefString(eni.NetworkInterfaceId),
 		PrivateIP:      derefString(eni.PrivateIpAddress),
 		PrivateIPs:     privateIPs,
 		SubnetID:       derefString(eni.SubnetId),
 		SecurityGroups: sgs,
+		VPCID:          derefString(eni.VpcId),
+		PublicIP:       publicIP,
+		IPv6Addresses:  ipv6Addresses,
+		InterfaceType:  string(eni.InterfaceType),
+		InstanceID:     instanceID,
+		Description:    derefString(eni.Description),
 	}
 }
 
@@ -552,6 +897,9 @@ func toElastiCacheClusterData(cluster *elasticachetypes.CacheCluster) *domain.El
 				}
 			}
 			nodeData.PrivateIP = resolveEndpointToIP(nodeData.Endpoint)
+			if cluster.NetworkType == elasticachetypes.NetworkTypeDualStack || cluster.NetworkType == elasticachetypes.NetworkTypeIpv6 {
+				nodeData.IPv6Addresses = resolveEndpointToIPv6(nodeData.Endpoint)
+			}
 		}
 		nodes = append(nodes, nodeData)
 	}
@@ -578,3 +926,21 @@ func resolveEndpointToIP(endpoint string) string {
 	}
 	return ips[0]
 }
+
+// resolveEndpointToIPv6 returns the IPv6 addresses endpoint resolves to.
+func resolveEndpointToIPv6(endpoint string) []string {
+	if endpoint == "" {
+		return nil
+	}
+	ips, err := net.LookupHost(endpoint)
+	if err != nil {
+		return nil
+	}
+	var addresses []string
+	for _, ip := range ips {
+		if strings.Contains(ip, ":") {
+			addresses = append(addresses, ip)
+		}
+	}
+	return addresses
+}
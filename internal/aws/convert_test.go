@@ -4,8 +4,13 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	dxtypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	nfwtypes "github.com/aws/aws-sdk-go-v2/service/networkfirewall/types"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/eleven-am/argus/internal/domain"
 )
 
 func TestToSecurityGroupData(t *testing.T) {
@@ -151,6 +156,32 @@ func TestToSubnetData(t *testing.T) {
 	}
 }
 
+func TestSubnetContainsIP(t *testing.T) {
+	subnet := &ec2types.Subnet{
+		CidrBlock: aws.String("10.0.1.0/24"),
+		Ipv6CidrBlockAssociationSet: []ec2types.SubnetIpv6CidrBlockAssociation{
+			{Ipv6CidrBlock: aws.String("2001:db8:1::/64")},
+		},
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.0.1.20", true},
+		{"10.0.2.20", false},
+		{"2001:db8:1::20", true},
+		{"2001:db8:2::20", false},
+		{"not-an-ip", false},
+	}
+
+	for _, tt := range tests {
+		if got := subnetContainsIP(subnet, tt.ip); got != tt.want {
+			t.Errorf("subnetContainsIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
 func TestToNACLData(t *testing.T) {
 	nacl := &ec2types.NetworkAcl{
 		NetworkAclId: aws.String("acl-123"),
@@ -228,6 +259,233 @@ func TestToRouteTableData(t *testing.T) {
 	}
 }
 
+func TestToRouteTableData_Associations(t *testing.T) {
+	rt := &ec2types.RouteTable{
+		RouteTableId: aws.String("rtb-123"),
+		Associations: []ec2types.RouteTableAssociation{
+			{Main: aws.Bool(true)},
+			{SubnetId: aws.String("subnet-1")},
+			{GatewayId: aws.String("igw-1")},
+			{
+				SubnetId:         aws.String("subnet-old"),
+				AssociationState: &ec2types.RouteTableAssociationState{State: ec2types.RouteTableAssociationStateCodeDisassociated},
+			},
+		},
+	}
+
+	result := toRouteTableData(rt)
+
+	if len(result.Associations) != 3 {
+		t.Fatalf("expected 3 associations, got %d", len(result.Associations))
+	}
+	if !result.Associations[0].Main {
+		t.Error("expected the first association to be the main one")
+	}
+	if result.Associations[1].SubnetID != "subnet-1" {
+		t.Errorf("expected subnet-1, got %s", result.Associations[1].SubnetID)
+	}
+	if result.Associations[2].GatewayID != "igw-1" {
+		t.Errorf("expected igw-1, got %s", result.Associations[2].GatewayID)
+	}
+}
+
+func TestToRouteTableData_StateAndOrigin(t *testing.T) {
+	rt := &ec2types.RouteTable{
+		RouteTableId: aws.String("rtb-123"),
+		Routes: []ec2types.Route{
+			{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local"), State: ec2types.RouteStateActive, Origin: ec2types.RouteOriginCreateRouteTable},
+			{DestinationCidrBlock: aws.String("10.1.0.0/16"), NetworkInterfaceId: aws.String("eni-gone"), State: ec2types.RouteStateBlackhole, Origin: ec2types.RouteOriginCreateRoute},
+			{DestinationCidrBlock: aws.String("192.168.0.0/16"), GatewayId: aws.String("vgw-1"), State: ec2types.RouteStateActive, Origin: ec2types.RouteOriginEnableVgwRoutePropagation},
+		},
+	}
+
+	result := toRouteTableData(rt)
+
+	want := []struct{ state, origin string }{
+		{"active", "local"},
+		{"blackhole", "static"},
+		{"active", "propagated"},
+	}
+	for i, w := range want {
+		if result.Routes[i].State != w.state || result.Routes[i].Origin != w.origin {
+			t.Errorf("route %d: expected %s/%s, got %s/%s", i, w.state, w.origin, result.Routes[i].State, result.Routes[i].Origin)
+		}
+	}
+}
+
+func TestPropagationSource(t *testing.T) {
+	route := domain.Route{DestinationCIDR: "192.168.0.0/16", Origin: "propagated", TargetType: "vpn-gateway", TargetID: "vgw-1"}
+
+	tests := []struct {
+		name string
+		vpns []*domain.VPNConnectionData
+		want string
+	}{
+		{"no vpn connections", nil, "direct-connect"},
+		{"matching static route", []*domain.VPNConnectionData{{State: "available", StaticRoutesOnly: true, StaticRoutes: []string{"192.168.0.0/16"}}}, "vpn-static"},
+		{"static vpn for other prefixes", []*domain.VPNConnectionData{{State: "available", StaticRoutesOnly: true, StaticRoutes: []string{"172.16.0.0/12"}}}, "direct-connect"},
+		{"dynamic vpn", []*domain.VPNConnectionData{{State: "available"}}, "vpn-bgp"},
+		{"deleted vpn", []*domain.VPNConnectionData{{State: "deleted"}}, "direct-connect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := propagationSource(route, tt.vpns); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToVPNConnectionData(t *testing.T) {
+	vpn := &ec2types.VpnConnection{
+		VpnConnectionId: aws.String("vpn-1"),
+		VpnGatewayId:    aws.String("vgw-1"),
+		State:           ec2types.VpnStateAvailable,
+		Options:         &ec2types.VpnConnectionOptions{StaticRoutesOnly: aws.Bool(true)},
+		Routes: []ec2types.VpnStaticRoute{
+			{DestinationCidrBlock: aws.String("192.168.0.0/16"), State: ec2types.VpnStateAvailable},
+			{DestinationCidrBlock: aws.String("172.16.0.0/12"), State: ec2types.VpnStateDeleted},
+		},
+		VgwTelemetry: []ec2types.VgwTelemetry{
+			{Status: ec2types.TelemetryStatusDown},
+			{Status: ec2types.TelemetryStatusUp},
+		},
+	}
+
+	result := toVPNConnectionData(vpn)
+
+	if result.ID != "vpn-1" || result.VGWID != "vgw-1" || result.State != "available" {
+		t.Errorf("unexpected identity: %+v", result)
+	}
+	if !result.HasUpTunnel {
+		t.Error("expected an up tunnel")
+	}
+	if !result.StaticRoutesOnly {
+		t.Error("expected static routes only")
+	}
+	if len(result.StaticRoutes) != 1 || result.StaticRoutes[0] != "192.168.0.0/16" {
+		t.Errorf("expected only the available static route, got %v", result.StaticRoutes)
+	}
+}
+
+func TestToTGWAttachmentData_ApplianceMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *ec2types.TransitGatewayVpcAttachmentOptions
+		want    bool
+	}{
+		{"enabled", &ec2types.TransitGatewayVpcAttachmentOptions{ApplianceModeSupport: ec2types.ApplianceModeSupportValueEnable}, true},
+		{"disabled", &ec2types.TransitGatewayVpcAttachmentOptions{ApplianceModeSupport: ec2types.ApplianceModeSupportValueDisable}, false},
+		{"no options", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			att := &ec2types.TransitGatewayVpcAttachment{
+				TransitGatewayAttachmentId: aws.String("tgw-attach-1"),
+				VpcId:                      aws.String("vpc-inspection"),
+				Options:                    tt.options,
+			}
+			if got := toTGWAttachmentData(att, "111111111111", "available", nil).ApplianceModeSupport; got != tt.want {
+				t.Errorf("expected ApplianceModeSupport %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToVPNConnectionData_AcceptedRoutes(t *testing.T) {
+	vpn := &ec2types.VpnConnection{
+		VpnConnectionId: aws.String("vpn-1"),
+		VgwTelemetry: []ec2types.VgwTelemetry{
+			{Status: ec2types.TelemetryStatusUp, AcceptedRouteCount: aws.Int32(3)},
+			{Status: ec2types.TelemetryStatusUp, AcceptedRouteCount: aws.Int32(2)},
+			{Status: ec2types.TelemetryStatusDown, AcceptedRouteCount: aws.Int32(7)},
+		},
+	}
+
+	if got := toVPNConnectionData(vpn).AcceptedRouteCount; got != 5 {
+		t.Errorf("expected 5 routes accepted on up tunnels, got %d", got)
+	}
+}
+
+func TestToTGWConnectAttachmentData(t *testing.T) {
+	connect := &ec2types.TransitGatewayConnect{
+		TransitGatewayAttachmentId:          aws.String("tgw-attach-connect"),
+		TransitGatewayId:                    aws.String("tgw-1"),
+		TransportTransitGatewayAttachmentId: aws.String("tgw-attach-transport"),
+		State:                               ec2types.TransitGatewayAttachmentStateAvailable,
+	}
+	peers := []ec2types.TransitGatewayConnectPeer{
+		{
+			TransitGatewayConnectPeerId: aws.String("tgw-connect-peer-1"),
+			State:                       ec2types.TransitGatewayConnectPeerStateAvailable,
+			ConnectPeerConfiguration: &ec2types.TransitGatewayConnectPeerConfiguration{
+				PeerAddress: aws.String("10.50.0.10"),
+				BgpConfigurations: []ec2types.TransitGatewayAttachmentBgpConfiguration{
+					{BgpStatus: ec2types.BgpStatusDown},
+					{BgpStatus: ec2types.BgpStatusUp},
+				},
+			},
+		},
+		{TransitGatewayConnectPeerId: aws.String("tgw-connect-peer-2"), State: ec2types.TransitGatewayConnectPeerStatePending},
+	}
+
+	result := toTGWConnectAttachmentData(connect, peers)
+
+	if result.ID != "tgw-attach-connect" || result.TransportAttachmentID != "tgw-attach-transport" || result.State != "available" {
+		t.Errorf("unexpected attachment: %+v", result)
+	}
+	if len(result.Peers) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(result.Peers))
+	}
+	if !result.Peers[0].BGPUp || result.Peers[0].PeerAddress != "10.50.0.10" {
+		t.Errorf("expected the first peer up at 10.50.0.10, got %+v", result.Peers[0])
+	}
+	if result.Peers[1].BGPUp {
+		t.Error("expected the second peer to have no BGP session up")
+	}
+}
+
+func TestToDirectConnectGatewayData(t *testing.T) {
+	dxgw := &dxtypes.DirectConnectGateway{
+		DirectConnectGatewayId:    aws.String("dxgw-1"),
+		OwnerAccount:              aws.String("111111111111"),
+		DirectConnectGatewayState: dxtypes.DirectConnectGatewayStateAvailable,
+	}
+	associations := []dxtypes.DirectConnectGatewayAssociation{
+		{
+			AssociationState:                      dxtypes.DirectConnectGatewayAssociationStateAssociated,
+			AssociatedGateway:                     &dxtypes.AssociatedGateway{Id: aws.String("tgw-1")},
+			AllowedPrefixesToDirectConnectGateway: []dxtypes.RouteFilterPrefix{{Cidr: aws.String("10.0.0.0/16")}},
+		},
+		{
+			AssociationState: dxtypes.DirectConnectGatewayAssociationStateAssociated,
+			VirtualGatewayId: aws.String("vgw-1"),
+		},
+		{
+			AssociationState:                      dxtypes.DirectConnectGatewayAssociationStateDisassociated,
+			AssociatedGateway:                     &dxtypes.AssociatedGateway{Id: aws.String("tgw-2")},
+			AllowedPrefixesToDirectConnectGateway: []dxtypes.RouteFilterPrefix{{Cidr: aws.String("10.1.0.0/16")}},
+		},
+	}
+
+	result := toDirectConnectGatewayData(dxgw, associations)
+
+	if result.ID != "dxgw-1" || result.OwnerID != "111111111111" || result.State != "available" {
+		t.Errorf("unexpected gateway: %+v", result)
+	}
+	if len(result.AllowedPrefixes) != 2 {
+		t.Fatalf("expected only the associated gateways, got %v", result.AllowedPrefixes)
+	}
+	if prefixes := result.AllowedPrefixes["tgw-1"]; len(prefixes) != 1 || prefixes[0] != "10.0.0.0/16" {
+		t.Errorf("expected tgw-1 to advertise 10.0.0.0/16, got %v", prefixes)
+	}
+	if prefixes, ok := result.AllowedPrefixes["vgw-1"]; !ok || len(prefixes) != 0 {
+		t.Errorf("expected vgw-1 associated with no prefixes, got %v", prefixes)
+	}
+}
+
 func TestDetermineRouteTarget(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -247,6 +505,12 @@ func TestDetermineRouteTarget(t *testing.T) {
 			wantType: "local",
 			wantID:   "local",
 		},
+		{
+			name:     "egress-only internet gateway",
+			route:    ec2types.Route{EgressOnlyInternetGatewayId: aws.String("eigw-123")},
+			wantType: "egress-only-internet-gateway",
+			wantID:   "eigw-123",
+		},
 		{
 			name:     "nat gateway",
 			route:    ec2types.Route{NatGatewayId: aws.String("nat-123")},
@@ -311,11 +575,74 @@ func TestToVPCData(t *testing.T) {
 	}
 }
 
+func TestToVPCData_IPv6CIDRs(t *testing.T) {
+	vpc := &ec2types.Vpc{
+		VpcId:     aws.String("vpc-123"),
+		CidrBlock: aws.String("10.0.0.0/16"),
+		Ipv6CidrBlockAssociationSet: []ec2types.VpcIpv6CidrBlockAssociation{
+			{Ipv6CidrBlock: aws.String("2600:1f18:0:100::/56")},
+			{Ipv6CidrBlock: aws.String("2600:1f18:0:200::/56")},
+		},
+	}
+
+	result := toVPCData(vpc, "rtb-main")
+
+	if len(result.IPv6CIDRBlocks) != 2 || result.IPv6CIDRBlocks[1] != "2600:1f18:0:200::/56" {
+		t.Errorf("expected both IPv6 CIDRs, got %v", result.IPv6CIDRBlocks)
+	}
+}
+
+func TestToVPCData_SecondaryCIDRs(t *testing.T) {
+	associated := &ec2types.VpcCidrBlockState{State: ec2types.VpcCidrBlockStateCodeAssociated}
+	vpc := &ec2types.Vpc{
+		VpcId:     aws.String("vpc-123"),
+		CidrBlock: aws.String("10.0.0.0/16"),
+		CidrBlockAssociationSet: []ec2types.VpcCidrBlockAssociation{
+			{CidrBlock: aws.String("10.0.0.0/16"), CidrBlockState: associated},
+			{CidrBlock: aws.String("100.64.0.0/16"), CidrBlockState: associated},
+			{CidrBlock: aws.String("10.9.0.0/16"), CidrBlockState: &ec2types.VpcCidrBlockState{State: ec2types.VpcCidrBlockStateCodeDisassociated}},
+		},
+	}
+
+	result := toVPCData(vpc, "rtb-main")
+
+	if len(result.SecondaryCIDRBlocks) != 1 || result.SecondaryCIDRBlocks[0] != "100.64.0.0/16" {
+		t.Errorf("expected only the associated secondary CIDR, got %v", result.SecondaryCIDRBlocks)
+	}
+}
+
+func TestToEC2InstanceData_IPv6Addresses(t *testing.T) {
+	inst := &ec2types.Instance{
+		InstanceId:       aws.String("i-123"),
+		PrivateIpAddress: aws.String("10.0.1.10"),
+		NetworkInterfaces: []ec2types.InstanceNetworkInterface{
+			{
+				Attachment:    &ec2types.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int32(1)},
+				Ipv6Addresses: []ec2types.InstanceIpv6Address{{Ipv6Address: aws.String("2600:1f18::99")}},
+			},
+			{
+				Attachment: &ec2types.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int32(0)},
+				Ipv6Addresses: []ec2types.InstanceIpv6Address{
+					{Ipv6Address: aws.String("2600:1f18::11")},
+					{Ipv6Address: aws.String("2600:1f18::10"), IsPrimaryIpv6: aws.Bool(true)},
+				},
+			},
+		},
+	}
+
+	result := toEC2InstanceData(inst)
+
+	if len(result.IPv6Addresses) != 2 || result.IPv6Addresses[0] != "2600:1f18::10" {
+		t.Errorf("expected the primary interface's addresses, primary first, got %v", result.IPv6Addresses)
+	}
+}
+
 func TestToEC2InstanceData(t *testing.T) {
 	inst := &ec2types.Instance{
 		InstanceId:       aws.String("i-123"),
 		PrivateIpAddress: aws.String("10.0.1.50"),
 		SubnetId:         aws.String("subnet-456"),
+		VpcId:            aws.String("vpc-789"),
 		SecurityGroups: []ec2types.GroupIdentifier{
 			{GroupId: aws.String("sg-111")},
 			{GroupId: aws.String("sg-222")},
@@ -333,6 +660,9 @@ func TestToEC2InstanceData(t *testing.T) {
 	if result.SubnetID != "subnet-456" {
 		t.Errorf("expected SubnetID subnet-456, got %s", result.SubnetID)
 	}
+	if result.VPCID != "vpc-789" {
+		t.Errorf("expected VPCID vpc-789, got %s", result.VPCID)
+	}
 	if len(result.SecurityGroups) != 2 {
 		t.Fatalf("expected 2 security groups, got %d", len(result.SecurityGroups))
 	}
@@ -349,6 +679,7 @@ func TestToRDSInstanceData(t *testing.T) {
 			{VpcSecurityGroupId: aws.String("sg-333")},
 		},
 		DBSubnetGroup: &rdstypes.DBSubnetGroup{
+			VpcId: aws.String("vpc-789"),
 			Subnets: []rdstypes.Subnet{
 				{SubnetIdentifier: aws.String("subnet-a")},
 				{SubnetIdentifier: aws.String("subnet-b")},
@@ -373,6 +704,9 @@ func TestToRDSInstanceData(t *testing.T) {
 	if len(result.SubnetIDs) != 2 {
 		t.Fatalf("expected 2 subnets, got %d", len(result.SubnetIDs))
 	}
+	if result.VPCID != "vpc-789" {
+		t.Errorf("expected VPCID vpc-789, got %s", result.VPCID)
+	}
 }
 
 func TestToInternetGatewayData(t *testing.T) {
@@ -415,6 +749,30 @@ func TestToNATGatewayData(t *testing.T) {
 	}
 }
 
+func TestToNATGatewayData_Private(t *testing.T) {
+	nat := &ec2types.NatGateway{
+		NatGatewayId:     aws.String("nat-123"),
+		SubnetId:         aws.String("subnet-456"),
+		ConnectivityType: ec2types.ConnectivityTypePrivate,
+		NatGatewayAddresses: []ec2types.NatGatewayAddress{
+			{PrivateIp: aws.String("100.64.0.11"), IsPrimary: aws.Bool(false)},
+			{PrivateIp: aws.String("100.64.0.10"), IsPrimary: aws.Bool(true)},
+		},
+	}
+
+	result := toNATGatewayData(nat)
+
+	if result.ConnectivityType != "private" {
+		t.Errorf("expected ConnectivityType private, got %s", result.ConnectivityType)
+	}
+	if result.PrivateIP != "100.64.0.10" {
+		t.Errorf("expected primary PrivateIP 100.64.0.10, got %s", result.PrivateIP)
+	}
+	if result.PublicIP != "" {
+		t.Errorf("expected no PublicIP, got %s", result.PublicIP)
+	}
+}
+
 func TestToVPCEndpointData(t *testing.T) {
 	ep := &ec2types.VpcEndpoint{
 		VpcEndpointId:   aws.String("vpce-123"),
@@ -462,6 +820,176 @@ func TestToVPCPeeringData(t *testing.T) {
 	}
 }
 
+func TestToVPCPeeringData_Regions(t *testing.T) {
+	pcx := &ec2types.VpcPeeringConnection{
+		VpcPeeringConnectionId: aws.String("pcx-dr"),
+		RequesterVpcInfo:       &ec2types.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-us"), Region: aws.String("us-east-1")},
+		AccepterVpcInfo:        &ec2types.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-eu"), Region: aws.String("eu-west-1")},
+	}
+
+	result := toVPCPeeringData(pcx)
+
+	if result.RequesterRegion != "us-east-1" {
+		t.Errorf("expected RequesterRegion us-east-1, got %s", result.RequesterRegion)
+	}
+	if result.AccepterRegion != "eu-west-1" {
+		t.Errorf("expected AccepterRegion eu-west-1, got %s", result.AccepterRegion)
+	}
+}
+
+func TestToTGWPeeringAttachmentData(t *testing.T) {
+	peering := &ec2types.TransitGatewayPeeringAttachment{
+		TransitGatewayAttachmentId: aws.String("tgw-attach-dr"),
+		RequesterTgwInfo: &ec2types.PeeringTgwInfo{
+			TransitGatewayId: aws.String("tgw-us"),
+			OwnerId:          aws.String("111111111111"),
+			Region:           aws.String("us-east-1"),
+		},
+		AccepterTgwInfo: &ec2types.PeeringTgwInfo{
+			TransitGatewayId: aws.String("tgw-eu"),
+			OwnerId:          aws.String("222222222222"),
+			Region:           aws.String("eu-west-1"),
+		},
+	}
+
+	tests := []struct {
+		from      string
+		local     string
+		peer      string
+		peerOwner string
+		peerIn    string
+	}{
+		{"tgw-us", "tgw-us", "tgw-eu", "222222222222", "eu-west-1"},
+		{"tgw-eu", "tgw-eu", "tgw-us", "111111111111", "us-east-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			result := toTGWPeeringAttachmentData(peering, tt.from)
+			if result.ID != "tgw-attach-dr" {
+				t.Errorf("expected ID tgw-attach-dr, got %s", result.ID)
+			}
+			if result.TransitGatewayID != tt.local {
+				t.Errorf("expected TransitGatewayID %s, got %s", tt.local, result.TransitGatewayID)
+			}
+			if result.PeerTransitGatewayID != tt.peer {
+				t.Errorf("expected PeerTransitGatewayID %s, got %s", tt.peer, result.PeerTransitGatewayID)
+			}
+			if result.PeerAccountID != tt.peerOwner {
+				t.Errorf("expected PeerAccountID %s, got %s", tt.peerOwner, result.PeerAccountID)
+			}
+			if result.PeerRegion != tt.peerIn {
+				t.Errorf("expected PeerRegion %s, got %s", tt.peerIn, result.PeerRegion)
+			}
+		})
+	}
+}
+
+func TestToTGWPeeringAttachmentData_IntraRegion(t *testing.T) {
+	peering := &ec2types.TransitGatewayPeeringAttachment{
+		TransitGatewayAttachmentId: aws.String("tgw-attach-shared"),
+		RequesterTgwInfo: &ec2types.PeeringTgwInfo{
+			TransitGatewayId: aws.String("tgw-prod"),
+			OwnerId:          aws.String("111111111111"),
+			Region:           aws.String("us-east-1"),
+		},
+		AccepterTgwInfo: &ec2types.PeeringTgwInfo{
+			TransitGatewayId: aws.String("tgw-shared"),
+			OwnerId:          aws.String("222222222222"),
+			Region:           aws.String("us-east-1"),
+		},
+	}
+
+	result := toTGWPeeringAttachmentData(peering, "tgw-shared")
+	if result.TransitGatewayID != "tgw-shared" || result.PeerTransitGatewayID != "tgw-prod" {
+		t.Errorf("expected the accepter to see the requester as its peer, got local %s and peer %s", result.TransitGatewayID, result.PeerTransitGatewayID)
+	}
+	if result.PeerAccountID != "111111111111" {
+		t.Errorf("expected PeerAccountID 111111111111, got %s", result.PeerAccountID)
+	}
+}
+
+func TestToENIData(t *testing.T) {
+	eni := &ec2types.NetworkInterface{
+		NetworkInterfaceId: aws.String("eni-123"),
+		PrivateIpAddress:   aws.String("10.0.1.10"),
+		VpcId:              aws.String("vpc-abc"),
+		InterfaceType:      ec2types.NetworkInterfaceTypeInterface,
+		Association:        &ec2types.NetworkInterfaceAssociation{PublicIp: aws.String("54.1.2.3")},
+		Ipv6Addresses: []ec2types.NetworkInterfaceIpv6Address{
+			{Ipv6Address: aws.String("2600:1f18::10")},
+		},
+		Attachment: &ec2types.NetworkInterfaceAttachment{InstanceId: aws.String("i-123")},
+	}
+
+	result := toENIData(eni)
+
+	if result.PublicIP != "54.1.2.3" {
+		t.Errorf("expected PublicIP 54.1.2.3, got %s", result.PublicIP)
+	}
+	if len(result.IPv6Addresses) != 1 || result.IPv6Addresses[0] != "2600:1f18::10" {
+		t.Errorf("expected IPv6Addresses [2600:1f18::10], got %v", result.IPv6Addresses)
+	}
+	if result.InstanceID != "i-123" {
+		t.Errorf("expected InstanceID i-123, got %s", result.InstanceID)
+	}
+	if result.InterfaceType != "interface" {
+		t.Errorf("expected InterfaceType interface, got %s", result.InterfaceType)
+	}
+}
+
+func TestToTargetGroupData_PreserveClientIP(t *testing.T) {
+	tests := []struct {
+		name  string
+		tg    elbv2types.TargetGroup
+		attrs []elbv2types.TargetGroupAttribute
+		want  bool
+	}{
+		{
+			name:  "attribute off",
+			tg:    elbv2types.TargetGroup{TargetType: elbv2types.TargetTypeEnumInstance, Protocol: elbv2types.ProtocolEnumTcp},
+			attrs: []elbv2types.TargetGroupAttribute{{Key: aws.String("preserve_client_ip.enabled"), Value: aws.String("false")}},
+			want:  false,
+		},
+		{
+			name: "instance default",
+			tg:   elbv2types.TargetGroup{TargetType: elbv2types.TargetTypeEnumInstance, Protocol: elbv2types.ProtocolEnumTcp},
+			want: true,
+		},
+		{
+			name: "ip over tcp default",
+			tg:   elbv2types.TargetGroup{TargetType: elbv2types.TargetTypeEnumIp, Protocol: elbv2types.ProtocolEnumTcp},
+			want: false,
+		},
+		{
+			name: "ip over udp default",
+			tg:   elbv2types.TargetGroup{TargetType: elbv2types.TargetTypeEnumIp, Protocol: elbv2types.ProtocolEnumUdp},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toTargetGroupData(&tt.tg, nil, tt.attrs).PreserveClientIP; got != tt.want {
+				t.Errorf("PreserveClientIP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToTargetGroupData_TargetAvailabilityZone(t *testing.T) {
+	tg := &elbv2types.TargetGroup{TargetType: elbv2types.TargetTypeEnumIp, Protocol: elbv2types.ProtocolEnumTcp}
+	health := []elbv2types.TargetHealthDescription{
+		{Target: &elbv2types.TargetDescription{Id: aws.String("10.0.1.10"), AvailabilityZone: aws.String("us-east-1a")}},
+	}
+
+	result := toTargetGroupData(tg, health, nil)
+
+	if len(result.Targets) != 1 || result.Targets[0].AvailabilityZone != "us-east-1a" {
+		t.Errorf("expected the target in us-east-1a, got %+v", result.Targets)
+	}
+}
+
 func TestPrefixLength(t *testing.T) {
 	tests := []struct {
 		cidr string
@@ -525,3 +1053,61 @@ func TestDerefInt32(t *testing.T) {
 		t.Error("expected 0 for nil")
 	}
 }
+
+func TestToListenerData(t *testing.T) {
+	listener := &elbv2types.Listener{
+		ListenerArn: aws.String("listener-1"),
+		Port:        aws.Int32(443),
+		Protocol:    elbv2types.ProtocolEnumHttps,
+		DefaultActions: []elbv2types.Action{
+			{Type: elbv2types.ActionTypeEnumForward, TargetGroupArn: aws.String("tg-default")},
+		},
+	}
+	rules := []elbv2types.Rule{
+		{Actions: []elbv2types.Action{{
+			Type: elbv2types.ActionTypeEnumForward,
+			ForwardConfig: &elbv2types.ForwardActionConfig{TargetGroups: []elbv2types.TargetGroupTuple{
+				{TargetGroupArn: aws.String("tg-blue")},
+				{TargetGroupArn: aws.String("tg-default")},
+			}},
+		}}},
+		{Actions: []elbv2types.Action{{Type: elbv2types.ActionTypeEnumRedirect}}},
+	}
+
+	data := toListenerData(listener, rules)
+
+	if data.ARN != "listener-1" || data.Port != 443 || data.Protocol != "HTTPS" {
+		t.Errorf("unexpected listener: %+v", data)
+	}
+	want := []string{"tg-default", "tg-blue"}
+	if len(data.TargetGroupARNs) != len(want) {
+		t.Fatalf("expected target groups %v, got %v", want, data.TargetGroupARNs)
+	}
+	for i := range want {
+		if data.TargetGroupARNs[i] != want[i] {
+			t.Errorf("expected target groups %v, got %v", want, data.TargetGroupARNs)
+		}
+	}
+
+	redirectOnly := toListenerData(&elbv2types.Listener{
+		Port:           aws.Int32(80),
+		DefaultActions: []elbv2types.Action{{Type: elbv2types.ActionTypeEnumRedirect}},
+	}, nil)
+	if len(redirectOnly.TargetGroupARNs) != 0 {
+		t.Errorf("expected a redirect-only listener to forward nowhere, got %v", redirectOnly.TargetGroupARNs)
+	}
+}
+
+func TestIPSetVariables(t *testing.T) {
+	variables := ipSetVariables(map[string]nfwtypes.IPSet{
+		"HOME_NET": {Definition: []string{"10.0.0.0/16", "10.1.0.0/16"}},
+	})
+	if got := variables["HOME_NET"]; len(got) != 2 || got[1] != "10.1.0.0/16" {
+		t.Errorf("expected HOME_NET to carry both CIDRs, got %v", got)
+	}
+
+	cidrs := vpcCIDRs(&domain.VPCData{CIDRBlock: "10.0.0.0/16", IPv6CIDRBlocks: []string{"2600:1f18::/56"}})
+	if len(cidrs) != 2 {
+		t.Errorf("expected the VPC's IPv4 and IPv6 CIDRs, got %v", cidrs)
+	}
+}
@@ -19,7 +19,7 @@ func NewALB(data *domain.ALBData, accountID string) *ALB {
 }
 
 func (alb *ALB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(alb.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(alb.accountID, alb.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -35,13 +35,18 @@ func (alb *ALB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analyz
 		sgDatas = append(sgDatas, sgData)
 	}
 
+	tgARNs, err := listenerTargetGroups(alb.GetID(), alb.data.Listeners, alb.data.ListenersLoaded, alb.data.TargetGroupARNs, dest)
+	if err != nil {
+		return nil, err
+	}
+
 	var targets []domain.Component
-	for _, tgARN := range alb.data.TargetGroupARNs {
+	for _, tgARN := range tgARNs {
 		tgData, err := client.GetTargetGroup(ctx, tgARN)
 		if err != nil {
 			return nil, err
 		}
-		targets = append(targets, NewTargetGroup(tgData, alb.accountID))
+		targets = append(targets, alb.proxiedTargetGroups(tgData)...)
 	}
 
 	if len(targets) == 0 {
@@ -57,16 +62,29 @@ func (alb *ALB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analyz
 
 	var components []domain.Component
 	for _, target := range targets {
-		var next domain.Component = target
-		for i := len(sgDatas) - 1; i >= 0; i-- {
-			next = NewSecurityGroupWithNext(sgDatas[i], alb.accountID, next)
-		}
-		components = append(components, next)
+		components = append(components, attachedSecurityGroups(sgDatas, alb.accountID, target))
 	}
 
 	return components, nil
 }
 
+// proxiedTargetGroups returns tgData once per load balancer node. An ALB
+// terminates the client's connection and opens its own from a node, which may
+// sit in any zone since cross-zone load balancing is always on, so targets'
+// security groups are checked against each node's address.
+func (alb *ALB) proxiedTargetGroups(tgData *domain.TargetGroupData) []domain.Component {
+	if len(alb.data.FrontendIPs) == 0 {
+		return []domain.Component{NewTargetGroup(tgData, alb.accountID)}
+	}
+	var groups []domain.Component
+	for _, ip := range alb.data.FrontendIPs {
+		tg := NewTargetGroup(tgData, alb.accountID)
+		tg.proxyIP = ip
+		groups = append(groups, tg)
+	}
+	return groups
+}
+
 func (alb *ALB) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -88,7 +106,7 @@ func (alb *ALB) GetVPCID() string {
 }
 
 func (alb *ALB) GetRegion() string {
-	return ""
+	return alb.data.Region
 }
 
 func (alb *ALB) GetSubnetID() string {
@@ -31,7 +31,7 @@ func (a *APIGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.A
 				Reason:      "private API has no VPC endpoints configured",
 			}
 		}
-		client, err := analyzerCtx.GetAccountContext().GetClient(a.accountID)
+		client, err := analyzerCtx.GetAccountContext().GetClient(a.accountID, a.data.Region)
 		if err != nil {
 			return nil, err
 		}
@@ -54,7 +54,7 @@ func (a *APIGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.A
 
 	case "REGIONAL":
 		if len(a.data.VPCLinkIDs) > 0 {
-			client, err := analyzerCtx.GetAccountContext().GetClient(a.accountID)
+			client, err := analyzerCtx.GetAccountContext().GetClient(a.accountID, a.data.Region)
 			if err != nil {
 				return nil, err
 			}
@@ -120,7 +120,7 @@ func (a *APIGateway) GetVPCID() string {
 }
 
 func (a *APIGateway) GetRegion() string {
-	return ""
+	return a.data.Region
 }
 
 func (a *APIGateway) GetSubnetID() string {
@@ -151,7 +151,7 @@ func (v *VPCLink) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Anal
 		}
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(v.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(v.accountID, v.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -211,17 +211,8 @@ func (v *VPCLink) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Anal
 				continue
 			}
 
-			var terminal domain.Component = NewSubnet(subnetData, v.accountID)
-			for i := len(v.data.SecurityGroups) - 1; i >= 0; i-- {
-				sgData, err := client.GetSecurityGroup(ctx, v.data.SecurityGroups[i])
-				if err != nil {
-					terminal = nil
-					break
-				}
-				terminal = NewSecurityGroupWithNext(sgData, v.accountID, terminal)
-			}
-
-			if terminal != nil {
+			terminal, err := securityGroupFilter(ctx, client, v.data.SecurityGroups, v.accountID, NewSubnet(subnetData, v.accountID))
+			if err == nil {
 				components = append(components, terminal)
 			}
 		}
@@ -263,7 +254,7 @@ func (v *VPCLink) GetVPCID() string {
 }
 
 func (v *VPCLink) GetRegion() string {
-	return ""
+	return v.data.Region
 }
 
 func (v *VPCLink) GetSubnetID() string {
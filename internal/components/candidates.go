@@ -0,0 +1,413 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// forwardingInterfaceTypes only carry traffic on behalf of something else, so
+// they are never the origin of a connection.
+var forwardingInterfaceTypes = map[string]bool{
+	"natGateway":                     true,
+	"transit_gateway":                true,
+	"vpc_endpoint":                   true,
+	"gateway_load_balancer":          true,
+	"gateway_load_balancer_endpoint": true,
+}
+
+type vpcRef struct {
+	id        string
+	accountID string
+	region    string
+}
+
+// CandidateSources lists the resources that may be able to send dest to
+// destination: network interfaces in the destination's VPC, in the VPCs its
+// subnet routes to through peering connections and transit gateways, and in
+// the security groups its inbound rules reference. Candidates the
+// destination's security groups do not admit are left out; the rest still have
+// to be traversed to know whether they get through. A VPC, connection,
+// referenced group or interface that cannot be looked up is returned as an
+// undecided source and the search goes on without it.
+func CandidateSources(analyzerCtx domain.AnalyzerContext, destination domain.Component, dest domain.RoutingTarget) ([]domain.Component, []domain.SourcePath, error) {
+	destENI, err := destinationENI(analyzerCtx, destination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountID := destination.GetAccountID()
+	client, err := analyzerCtx.GetAccountContext().GetClient(accountID, destENI.Region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	finder := &sourceFinder{
+		analyzerCtx: analyzerCtx,
+		dest:        dest,
+		members:     make(map[string]map[string]bool),
+		seen:        map[string]bool{destENI.ID: true},
+	}
+	for _, ip := range interfaceAddresses(destENI) {
+		finder.seen[ip] = true
+	}
+
+	for _, sgID := range destENI.SecurityGroups {
+		sgData, err := client.GetSecurityGroup(ctx, sgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		finder.groups = append(finder.groups, NewSecurityGroup(sgData, accountID))
+	}
+	finder.loadMembers(client, accountID)
+
+	subnetData, err := client.GetSubnet(ctx, destENI.SubnetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	vpcs, unreached, err := connectedVPCs(analyzerCtx, accountID, subnetData)
+	if err != nil {
+		return nil, nil, err
+	}
+	finder.undecided = append(finder.undecided, unreached...)
+	for _, vpc := range vpcs {
+		if err := finder.addVPC(vpc); err != nil {
+			finder.undecided = append(finder.undecided, undecidedSource(vpc.accountID+":"+vpc.id, "VPC", "", err))
+		}
+	}
+
+	return finder.candidates, finder.undecided, nil
+}
+
+// undecidedSource is a source that could not be looked up, and so may or may
+// not reach the destination.
+func undecidedSource(id, componentType, ip string, err error) domain.SourcePath {
+	return domain.SourcePath{
+		SourceID:   id,
+		SourceType: componentType,
+		SourceIP:   ip,
+		Verdict:    domain.VerdictUnknown,
+		Reason:     domain.ClassifyError(err).Error(),
+	}
+}
+
+func destinationENI(analyzerCtx domain.AnalyzerContext, destination domain.Component) (*domain.ENIData, error) {
+	provider, ok := destination.(domain.IngressProvider)
+	if !ok {
+		return nil, fmt.Errorf("%s has no network interface to find sources for", destination.GetID())
+	}
+
+	hops, err := provider.GetIngressHops(analyzerCtx)
+	if err != nil {
+		return nil, err
+	}
+	for _, hop := range hops {
+		if ingress, ok := hop.(*ENIIngress); ok {
+			return ingress.eni, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no network interface to find sources for", destination.GetID())
+}
+
+// connectedVPCs returns the subnet's VPC followed by the VPCs its route table
+// reaches through active peering connections and transit gateways. A
+// connection that cannot be looked up is returned as undecided.
+func connectedVPCs(analyzerCtx domain.AnalyzerContext, accountID string, subnet *domain.SubnetData) ([]vpcRef, []domain.SourcePath, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(accountID, subnet.Region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	routeTableID := subnet.RouteTableID
+	if routeTableID == "" {
+		vpcData, err := client.GetVPC(ctx, subnet.VPCID)
+		if err != nil {
+			return nil, nil, err
+		}
+		routeTableID = vpcData.MainRouteTableID
+	}
+	rtData, err := client.GetRouteTable(ctx, routeTableID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vpcs := []vpcRef{{id: subnet.VPCID, accountID: accountID, region: subnet.Region}}
+	seen := map[string]bool{subnet.VPCID: true}
+	add := func(id, owner, region string) {
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		if owner == "" {
+			owner = accountID
+		}
+		if region == "" {
+			region = subnet.Region
+		}
+		vpcs = append(vpcs, vpcRef{id: id, accountID: owner, region: region})
+	}
+
+	var unreached []domain.SourcePath
+	tgwsSeen := make(map[string]bool)
+	for _, route := range rtData.Routes {
+		switch route.TargetType {
+		case "vpc-peering":
+			peering, err := client.GetVPCPeering(ctx, route.TargetID)
+			if err != nil {
+				unreached = append(unreached, undecidedSource(accountID+":"+route.TargetID, "VPCPeering", "", err))
+				continue
+			}
+			if peering.Status != "" && peering.Status != "active" {
+				continue
+			}
+			if peering.RequesterVPC == subnet.VPCID {
+				add(peering.AccepterVPC, peering.AccepterOwner, peering.AccepterRegion)
+			} else {
+				add(peering.RequesterVPC, peering.RequesterOwner, peering.RequesterRegion)
+			}
+
+		case "transit-gateway":
+			if tgwsSeen[route.TargetID] {
+				continue
+			}
+			tgwsSeen[route.TargetID] = true
+
+			tgwData, err := attachedTransitGateway(analyzerCtx, client, accountID, subnet.VPCID, route.TargetID)
+			if err != nil {
+				unreached = append(unreached, undecidedSource(accountID+":"+route.TargetID, "TransitGateway", "", err))
+				continue
+			}
+			for _, tgwRouteTable := range tgwData.RouteTables {
+				for _, tgwRoute := range tgwRouteTable.Routes {
+					for _, att := range tgwRoute.Attachments {
+						if att.Type == "vpc" {
+							add(att.ResourceID, att.OwnerID, "")
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return vpcs, unreached, nil
+}
+
+// attachedTransitGateway returns transit gateway tgwID as seen from its owner
+// account, through vpcID's attachment to it.
+func attachedTransitGateway(analyzerCtx domain.AnalyzerContext, client domain.AWSClient, accountID, vpcID, tgwID string) (*domain.TransitGatewayData, error) {
+	ctx := analyzerCtx.Context()
+
+	attachment, err := client.GetTransitGatewayAttachment(ctx, vpcID, tgwID)
+	if err != nil {
+		return nil, err
+	}
+	tgwAccountID := attachment.TGWAccountID
+	if tgwAccountID == "" {
+		tgwAccountID = accountID
+	}
+	tgwClient, err := analyzerCtx.GetAccountContext().GetClient(tgwAccountID, attachment.Region)
+	if err != nil {
+		return nil, err
+	}
+	return tgwClient.GetTransitGateway(ctx, attachment.TransitGatewayID)
+}
+
+// sourceFinder turns network interfaces into source components, skipping
+// duplicates and addresses the destination's security groups do not admit.
+type sourceFinder struct {
+	analyzerCtx domain.AnalyzerContext
+	dest        domain.RoutingTarget
+	groups      []*SecurityGroup
+	// members holds the addresses in each security group referenced by an
+	// inbound rule, so rules are checked without a lookup per candidate.
+	members    map[string]map[string]bool
+	seen       map[string]bool
+	candidates []domain.Component
+	undecided  []domain.SourcePath
+}
+
+// addVPC adds the interfaces in vpc.
+func (f *sourceFinder) addVPC(vpc vpcRef) error {
+	client, err := f.analyzerCtx.GetAccountContext().GetClient(vpc.accountID, vpc.region)
+	if err != nil {
+		return err
+	}
+	enis, err := client.GetENIsByVPC(f.analyzerCtx.Context(), vpc.id)
+	if err != nil {
+		return err
+	}
+	for i := range enis {
+		f.add(client, &enis[i], vpc.accountID)
+	}
+	return nil
+}
+
+// loadMembers adds the interfaces of the security groups referenced by inbound
+// rules that cover the flow. Referenced groups may live in peered VPCs that
+// are not otherwise connected. A group whose members cannot be listed is
+// undecided.
+func (f *sourceFinder) loadMembers(client domain.AWSClient, accountID string) {
+	for _, sg := range f.groups {
+		for _, rule := range sg.data.InboundRules {
+			if !protocolMatches(rule.Protocol, f.dest.Protocol) || !flowPortsInRange(f.dest, rule.FromPort, rule.ToPort) {
+				continue
+			}
+			for _, refSGID := range rule.ReferencedSecurityGroups {
+				if _, ok := f.members[refSGID]; ok {
+					continue
+				}
+				enis, err := client.GetENIsBySecurityGroup(f.analyzerCtx.Context(), refSGID)
+				if err != nil {
+					f.members[refSGID] = nil
+					f.undecided = append(f.undecided, undecidedSource(accountID+":"+refSGID, "SecurityGroup", "", err))
+					continue
+				}
+				ips := make(map[string]bool)
+				for i := range enis {
+					for _, ip := range interfaceAddresses(&enis[i]) {
+						ips[ip] = true
+					}
+				}
+				f.members[refSGID] = ips
+				for i := range enis {
+					f.add(client, &enis[i], accountID)
+				}
+			}
+		}
+	}
+}
+
+// admits reports whether the destination's security groups let ip in. A rule
+// that could not be evaluated only matters when no other rule admits ip.
+func (f *sourceFinder) admits(ip string) (bool, error) {
+	target := f.dest
+	target.SourceIP = ip
+	var lookupErr error
+	for _, sg := range f.groups {
+		for _, rule := range sg.data.InboundRules {
+			for _, refSGID := range rule.ReferencedSecurityGroups {
+				if f.members[refSGID][ip] && protocolMatches(rule.Protocol, target.Protocol) && flowPortsInRange(target, rule.FromPort, rule.ToPort) {
+					return true, nil
+				}
+			}
+			byAddress := rule
+			byAddress.ReferencedSecurityGroups = nil
+			allowed, err := sg.ruleAllows(byAddress, ip, target, f.analyzerCtx)
+			if allowed {
+				return true, nil
+			}
+			if err != nil && lookupErr == nil {
+				lookupErr = err
+			}
+		}
+	}
+	return false, lookupErr
+}
+
+// add turns eni into candidates: the interface or the resource that owns it,
+// admitted on its address in the flow's IP version, and any pod addresses it
+// carries. An address whose admission or owner cannot be looked up is
+// undecided.
+func (f *sourceFinder) add(client domain.AWSClient, eni *domain.ENIData, accountID string) {
+	if f.seen[eni.ID] || forwardingInterfaceTypes[eni.InterfaceType] {
+		return
+	}
+	f.seen[eni.ID] = true
+
+	if ip, ok := f.sendingAddress(eni); ok {
+		iface := NewNetworkInterfaceFromData(eni, accountID)
+		admitted, err := f.admits(ip)
+		if err == nil && admitted {
+			var source domain.Component
+			source, err = f.owner(client, eni, accountID)
+			if err == nil {
+				f.candidates = append(f.candidates, source)
+			}
+		}
+		if err != nil {
+			f.undecided = append(f.undecided, undecidedSource(iface.GetID(), iface.GetComponentType(), ip, err))
+		}
+	}
+
+	// The VPC CNI hands pods secondary addresses of the node's interfaces. An
+	// address may also be idle in the CNI's warm pool. Those are IPv4, so
+	// they cannot send an IPv6 flow.
+	if isIPv6(f.dest.IP) {
+		return
+	}
+	if !strings.Contains(eni.Description, "aws-K8S-") && !strings.Contains(eni.Description, "amazon-vpc-cni") {
+		return
+	}
+	for _, ip := range eni.PrivateIPs {
+		if ip == eni.PrivateIP || f.seen[ip] {
+			continue
+		}
+		f.seen[ip] = true
+		pod := NewEKSPod(&domain.EKSPodData{
+			PodIP:          ip,
+			HostIP:         eni.PrivateIP,
+			ENIID:          eni.ID,
+			SecurityGroups: eni.SecurityGroups,
+			SubnetID:       eni.SubnetID,
+			Region:         eni.Region,
+		}, accountID)
+		admitted, err := f.admits(ip)
+		if err != nil {
+			f.undecided = append(f.undecided, undecidedSource(pod.GetID(), pod.GetComponentType(), ip, err))
+			continue
+		}
+		if admitted {
+			f.candidates = append(f.candidates, pod)
+		}
+	}
+}
+
+// sendingAddress returns the address eni sends the flow from: its primary
+// private address for an IPv4 flow and its first IPv6 address for an IPv6
+// one. The boolean is false when eni has no such address or it was already
+// considered.
+func (f *sourceFinder) sendingAddress(eni *domain.ENIData) (string, bool) {
+	ip := eni.PrivateIP
+	if isIPv6(f.dest.IP) {
+		if len(eni.IPv6Addresses) == 0 {
+			return "", false
+		}
+		ip = eni.IPv6Addresses[0]
+	}
+	if ip == "" || f.seen[ip] {
+		return "", false
+	}
+	f.seen[ip] = true
+	return ip, true
+}
+
+// owner returns the resource sending from eni's primary address: its instance
+// or Lambda function when it has one, and the interface itself otherwise.
+func (f *sourceFinder) owner(client domain.AWSClient, eni *domain.ENIData, accountID string) (domain.Component, error) {
+	ctx := f.analyzerCtx.Context()
+
+	switch {
+	case eni.InstanceID != "":
+		instance, err := client.GetEC2Instance(ctx, eni.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if instance.PrivateIP == eni.PrivateIP {
+			return NewEC2Instance(instance, accountID), nil
+		}
+	case eni.InterfaceType == "lambda":
+		function, err := client.GetLambdaFunctionByENIIP(ctx, eni.PrivateIP, eni.VPCID)
+		if err != nil {
+			return nil, err
+		}
+		if function != nil {
+			return NewLambdaFunction(function, accountID), nil
+		}
+	}
+	return NewNetworkInterfaceFromData(eni, accountID), nil
+}
@@ -0,0 +1,209 @@
+package components
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+func newCandidateFixture() (*mockAccountContext, *EC2Instance) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-db"] = &domain.ENIData{ID: "eni-db", PrivateIP: "10.0.1.100", SubnetID: "subnet-1", VPCID: "vpc-1", SecurityGroups: []string{"sg-db"}}
+	client.securityGroups["sg-db"] = &domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-1",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.1.0/24", "10.1.0.0/16"}},
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, ReferencedSecurityGroups: []string{"sg-nodes"}},
+			{Protocol: "tcp", FromPort: 22, ToPort: 22, ReferencedSecurityGroups: []string{"sg-bastion"}},
+		},
+	}
+	client.subnets["subnet-1"] = &domain.SubnetData{ID: "subnet-1", VPCID: "vpc-1", CIDRBlock: "10.0.1.0/24", RouteTableID: "rtb-1"}
+	client.routeTables["rtb-1"] = &domain.RouteTableData{
+		ID:    "rtb-1",
+		VPCID: "vpc-1",
+		Routes: []domain.Route{
+			{DestinationCIDR: "10.0.0.0/16", TargetType: "local", TargetID: "local"},
+			{DestinationCIDR: "10.1.0.0/16", TargetType: "vpc-peering", TargetID: "pcx-1"},
+		},
+	}
+	client.vpcPeerings["pcx-1"] = &domain.VPCPeeringData{
+		ID: "pcx-1", RequesterVPC: "vpc-1", RequesterOwner: "111111111111",
+		AccepterVPC: "vpc-2", AccepterOwner: "222222222222", Status: "active",
+	}
+	client.ec2Instances["i-app"] = &domain.EC2InstanceData{ID: "i-app", PrivateIP: "10.0.1.10", SubnetID: "subnet-1"}
+	client.lambdaFunctions["fn"] = &domain.LambdaFunctionData{Name: "fn", VPCID: "vpc-1", ENIIPs: []string{"10.0.1.30"}}
+	client.enisByVPC["vpc-1"] = []domain.ENIData{
+		{ID: "eni-db", PrivateIP: "10.0.1.100", SubnetID: "subnet-1"},
+		{ID: "eni-app", PrivateIP: "10.0.1.10", SubnetID: "subnet-1", InstanceID: "i-app", InterfaceType: "interface"},
+		{ID: "eni-fn", PrivateIP: "10.0.1.30", SubnetID: "subnet-1", VPCID: "vpc-1", InterfaceType: "lambda"},
+		{ID: "eni-nat", PrivateIP: "10.0.1.5", SubnetID: "subnet-1", InterfaceType: "natGateway"},
+		{ID: "eni-web", PrivateIP: "10.0.2.20", SubnetID: "subnet-2", InterfaceType: "interface"},
+	}
+	client.enisBySG["sg-nodes"] = []domain.ENIData{
+		{ID: "eni-node", PrivateIP: "10.0.3.5", PrivateIPs: []string{"10.0.3.5", "10.0.3.6"}, SubnetID: "subnet-3", Description: "aws-K8S-i-node"},
+	}
+	client.enisBySG["sg-bastion"] = []domain.ENIData{
+		{ID: "eni-bastion", PrivateIP: "10.0.4.4", SubnetID: "subnet-4"},
+	}
+
+	peerClient := newMockAWSClient()
+	peerClient.enisByVPC["vpc-2"] = []domain.ENIData{
+		{ID: "eni-peer", PrivateIP: "10.1.0.5", SubnetID: "subnet-9", InterfaceType: "interface"},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	accountCtx.addClient("222222222222", peerClient)
+
+	dest := NewEC2Instance(&domain.EC2InstanceData{ID: "i-db", PrivateIP: "10.0.1.100", SubnetID: "subnet-1", SecurityGroups: []string{"sg-db"}}, "111111111111")
+	return accountCtx, dest
+}
+
+func TestCandidateSources_AdmittedInterfacesInConnectedVPCs(t *testing.T) {
+	accountCtx, dest := newCandidateFixture()
+	target := domain.RoutingTarget{IP: "10.0.1.100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+
+	candidates, undecided, err := CandidateSources(newMockAnalyzerContext(accountCtx), dest, target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(undecided) != 0 {
+		t.Errorf("expected every lookup to succeed, got undecided %+v", undecided)
+	}
+
+	var ids []string
+	for _, c := range candidates {
+		ids = append(ids, c.GetComponentType()+" "+c.GetID())
+	}
+	sort.Strings(ids)
+
+	want := []string{
+		"EC2Instance 111111111111:i-app",
+		"EKSPod 111111111111:eks-pod:10.0.3.6",
+		"LambdaFunction " + NewLambdaFunction(&domain.LambdaFunctionData{Name: "fn"}, "111111111111").GetID(),
+		"NetworkInterface 111111111111:eni-node",
+		"NetworkInterface 222222222222:eni-peer",
+	}
+	sort.Strings(want)
+
+	if len(ids) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, ids)
+			break
+		}
+	}
+}
+
+func TestCandidateSources_DestinationWithoutInterface(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	igw := NewInternetGateway(&domain.InternetGatewayData{ID: "igw-1"}, "111111111111")
+
+	if _, _, err := CandidateSources(newMockAnalyzerContext(accountCtx), igw, domain.RoutingTarget{}); err == nil {
+		t.Error("expected an error for a destination without a network interface")
+	}
+}
+
+func TestCandidateSources_PeerVPCInAnotherRegion(t *testing.T) {
+	accountCtx, dest := newCandidateFixture()
+	peerClient := accountCtx.clients["222222222222"]
+	delete(accountCtx.clients, "222222222222")
+	accountCtx.addRegionalClient("222222222222", "eu-west-1", peerClient)
+
+	client := accountCtx.clients["111111111111"]
+	client.vpcPeerings["pcx-1"].AccepterRegion = "eu-west-1"
+
+	target := domain.RoutingTarget{IP: "10.0.1.100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	candidates, _, err := CandidateSources(newMockAnalyzerContext(accountCtx), dest, target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := false
+	for _, c := range candidates {
+		if c.GetID() == "222222222222:eni-peer" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the peer VPC's interface to be looked up in eu-west-1, got %v", candidates)
+	}
+}
+
+func TestCandidateSources_FailedLookupsAreUndecided(t *testing.T) {
+	accountCtx, dest := newCandidateFixture()
+	delete(accountCtx.clients, "222222222222")
+	delete(accountCtx.clients["111111111111"].ec2Instances, "i-app")
+
+	target := domain.RoutingTarget{IP: "10.0.1.100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	candidates, undecided, err := CandidateSources(newMockAnalyzerContext(accountCtx), dest, target)
+	if err != nil {
+		t.Fatalf("expected failed lookups not to abort the search, got %v", err)
+	}
+
+	var undecidedIDs []string
+	for _, source := range undecided {
+		if source.Verdict != domain.VerdictUnknown || source.Reason == "" {
+			t.Errorf("expected %s to be unknown with a reason, got %+v", source.SourceID, source)
+		}
+		undecidedIDs = append(undecidedIDs, source.SourceType+" "+source.SourceID)
+	}
+	sort.Strings(undecidedIDs)
+	want := []string{"NetworkInterface 111111111111:eni-app", "VPC 222222222222:vpc-2"}
+	if len(undecidedIDs) != len(want) || undecidedIDs[0] != want[0] || undecidedIDs[1] != want[1] {
+		t.Errorf("expected undecided %v, got %v", want, undecidedIDs)
+	}
+
+	found := false
+	for _, c := range candidates {
+		if c.GetID() == "111111111111:eni-node" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the remaining candidates to still be listed")
+	}
+}
+
+func TestCandidateSources_IPv6FlowAdmitsIPv6Addresses(t *testing.T) {
+	accountCtx, dest := newCandidateFixture()
+	client := accountCtx.clients["111111111111"]
+	client.securityGroups["sg-db"].InboundRules = []domain.SecurityGroupRule{
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, IPv6CIDRBlocks: []string{"2600:1f18:aaaa:1::/64"}},
+		{Protocol: "tcp", FromPort: 5432, ToPort: 5432, ReferencedSecurityGroups: []string{"sg-nodes"}},
+	}
+	client.enisByVPC["vpc-1"] = []domain.ENIData{
+		{ID: "eni-db", PrivateIP: "10.0.1.100", SubnetID: "subnet-1"},
+		{ID: "eni-v6", PrivateIP: "10.0.1.11", IPv6Addresses: []string{"2600:1f18:aaaa:1::11"}, SubnetID: "subnet-1", InterfaceType: "interface"},
+		{ID: "eni-v4", PrivateIP: "10.0.1.12", SubnetID: "subnet-1", InterfaceType: "interface"},
+		{ID: "eni-other", PrivateIP: "10.0.1.13", IPv6Addresses: []string{"2600:1f18:aaaa:2::13"}, SubnetID: "subnet-1", InterfaceType: "interface"},
+	}
+	client.enisBySG["sg-nodes"] = []domain.ENIData{
+		{ID: "eni-node", PrivateIP: "10.0.3.5", PrivateIPs: []string{"10.0.3.5", "10.0.3.6"}, IPv6Addresses: []string{"2600:1f18:aaaa:3::5"}, SubnetID: "subnet-3", Description: "aws-K8S-i-node"},
+	}
+	delete(accountCtx.clients, "222222222222")
+	client.routeTables["rtb-1"].Routes = client.routeTables["rtb-1"].Routes[:1]
+
+	target := domain.RoutingTarget{IP: "2600:1f18:aaaa:1::100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	candidates, undecided, err := CandidateSources(newMockAnalyzerContext(accountCtx), dest, target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(undecided) != 0 {
+		t.Errorf("expected every lookup to succeed, got undecided %+v", undecided)
+	}
+
+	var ids []string
+	for _, c := range candidates {
+		ids = append(ids, c.GetID())
+	}
+	sort.Strings(ids)
+	want := []string{"111111111111:eni-node", "111111111111:eni-v6"}
+	if len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] {
+		t.Errorf("expected candidates admitted on their IPv6 address %v, got %v", want, ids)
+	}
+}
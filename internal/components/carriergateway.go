@@ -9,12 +9,14 @@ import (
 type CarrierGateway struct {
 	id        string
 	accountID string
+	region    string
 }
 
-func NewCarrierGateway(id, accountID string) *CarrierGateway {
+func NewCarrierGateway(id, accountID, region string) *CarrierGateway {
 	return &CarrierGateway{
 		id:        id,
 		accountID: accountID,
+		region:    region,
 	}
 }
 
@@ -68,7 +70,7 @@ func (cgw *CarrierGateway) GetVPCID() string {
 }
 
 func (cgw *CarrierGateway) GetRegion() string {
-	return ""
+	return cgw.region
 }
 
 func (cgw *CarrierGateway) GetSubnetID() string {
@@ -9,6 +9,9 @@ import (
 type CLB struct {
 	data      *domain.CLBData
 	accountID string
+	// proxyIP is the node the CLB was narrowed to. Instances see connections
+	// from it rather than from the client.
+	proxyIP string
 }
 
 func NewCLB(data *domain.CLBData, accountID string) *CLB {
@@ -19,7 +22,19 @@ func NewCLB(data *domain.CLBData, accountID string) *CLB {
 }
 
 func (clb *CLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(clb.accountID)
+	// Each node opens its own connection to the instances, so a CLB with
+	// several nodes is split into one per node address.
+	if clb.proxyIP == "" && len(clb.data.FrontendIPs) > 1 {
+		var nodes []domain.Component
+		for _, ip := range clb.data.FrontendIPs {
+			node := *clb
+			node.proxyIP = ip
+			nodes = append(nodes, &node)
+		}
+		return nodes, nil
+	}
+
+	client, err := analyzerCtx.GetAccountContext().GetClient(clb.accountID, clb.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -57,16 +72,29 @@ func (clb *CLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analyz
 
 	var components []domain.Component
 	for _, target := range targets {
-		var next domain.Component = target
-		for i := len(sgDatas) - 1; i >= 0; i-- {
-			next = NewSecurityGroupWithNext(sgDatas[i], clb.accountID, next)
-		}
-		components = append(components, next)
+		components = append(components, attachedSecurityGroups(sgDatas, clb.accountID, target))
 	}
 
 	return components, nil
 }
 
+// Translate rewrites the source to the node's address: the CLB terminates the
+// client's connection, so instances' security groups see the load balancer.
+func (clb *CLB) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	if target.Reply {
+		return target
+	}
+	ip := clb.proxyIP
+	if ip == "" && len(clb.data.FrontendIPs) == 1 {
+		ip = clb.data.FrontendIPs[0]
+	}
+	if ip != "" {
+		target.SourceIP = ip
+		target.SourceIsPrivate = !isExternalIP(ip)
+	}
+	return target
+}
+
 func (clb *CLB) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -75,6 +103,13 @@ func (clb *CLB) GetID() string {
 	return fmt.Sprintf("%s:clb:%s", clb.accountID, clb.data.Name)
 }
 
+func (clb *CLB) GetStateKey() string {
+	if clb.proxyIP == "" {
+		return clb.GetID()
+	}
+	return clb.GetID() + "@" + clb.proxyIP
+}
+
 func (clb *CLB) GetAccountID() string {
 	return clb.accountID
 }
@@ -88,7 +123,7 @@ func (clb *CLB) GetVPCID() string {
 }
 
 func (clb *CLB) GetRegion() string {
-	return ""
+	return clb.data.Region
 }
 
 func (clb *CLB) GetSubnetID() string {
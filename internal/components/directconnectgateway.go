@@ -9,6 +9,10 @@ import (
 type DirectConnectGateway struct {
 	data      *domain.DirectConnectGatewayData
 	accountID string
+	outbound  bool
+	// associatedGatewayID is the transit gateway outbound traffic arrives
+	// from, whose association decides which prefixes are advertised.
+	associatedGatewayID string
 }
 
 func NewDirectConnectGateway(data *domain.DirectConnectGatewayData, accountID string) *DirectConnectGateway {
@@ -18,6 +22,18 @@ func NewDirectConnectGateway(data *domain.DirectConnectGatewayData, accountID st
 	}
 }
 
+// NewOutboundDirectConnectGateway returns a Direct Connect gateway carrying
+// traffic that transit gateway tgwID routed from AWS toward on-premises
+// networks.
+func NewOutboundDirectConnectGateway(data *domain.DirectConnectGatewayData, tgwID, accountID string) *DirectConnectGateway {
+	return &DirectConnectGateway{
+		data:                data,
+		accountID:           accountID,
+		outbound:            true,
+		associatedGatewayID: tgwID,
+	}
+}
+
 func (dxgw *DirectConnectGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
 	if dest.IP == "" {
 		return nil, &domain.BlockingError{
@@ -33,18 +49,39 @@ func (dxgw *DirectConnectGateway) GetNextHops(dest domain.RoutingTarget, analyze
 		}
 	}
 
+	// On-premises networks only route to the prefixes the gateway advertises:
+	// the destination has to be in them for traffic coming in, and the source
+	// for the replies to traffic going out. Traffic coming in takes whichever
+	// association advertises its destination; traffic going out can only be
+	// answered through the association of the transit gateway it came from.
 	if len(dxgw.data.AllowedPrefixes) > 0 {
-		allowed := false
-		for _, prefix := range dxgw.data.AllowedPrefixes {
-			if IPMatchesCIDR(dest.IP, prefix) {
-				allowed = true
-				break
+		if dxgw.outbound {
+			prefixes, ok := dxgw.data.AllowedPrefixes[dxgw.associatedGatewayID]
+			if !ok {
+				return nil, &domain.BlockingError{
+					ComponentID: dxgw.GetID(),
+					Reason:      fmt.Sprintf("%s is not associated with Direct Connect gateway %s", dxgw.associatedGatewayID, dxgw.data.ID),
+				}
 			}
-		}
-		if !allowed {
-			return nil, &domain.BlockingError{
-				ComponentID: dxgw.GetID(),
-				Reason:      fmt.Sprintf("destination %s not allowed by Direct Connect gateway prefixes", dest.IP),
+			if !ipInAnyCIDR(dest.SourceIP, prefixes) {
+				return nil, &domain.BlockingError{
+					ComponentID: dxgw.GetID(),
+					Reason:      fmt.Sprintf("source %s not allowed by the Direct Connect gateway prefixes of %s", dest.SourceIP, dxgw.associatedGatewayID),
+				}
+			}
+		} else {
+			allowed := false
+			for _, prefixes := range dxgw.data.AllowedPrefixes {
+				if ipInAnyCIDR(dest.IP, prefixes) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return nil, &domain.BlockingError{
+					ComponentID: dxgw.GetID(),
+					Reason:      fmt.Sprintf("destination %s not allowed by Direct Connect gateway prefixes", dest.IP),
+				}
 			}
 		}
 	}
@@ -52,6 +89,15 @@ func (dxgw *DirectConnectGateway) GetNextHops(dest domain.RoutingTarget, analyze
 	return []domain.Component{NewIPTarget(&domain.IPTargetData{IP: dest.IP, Port: dest.Port}, dxgw.accountID)}, nil
 }
 
+func ipInAnyCIDR(ip string, cidrs []string) bool {
+	for _, cidr := range cidrs {
+		if IPMatchesCIDR(ip, cidr) {
+			return true
+		}
+	}
+	return false
+}
+
 func (dxgw *DirectConnectGateway) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
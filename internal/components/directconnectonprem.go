@@ -49,7 +49,8 @@ func (d *DirectConnectOnPrem) GetNextHops(dest domain.RoutingTarget, analyzerCtx
 		}
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(d.accountID)
+	// Direct Connect gateways are global, so any region's client finds them.
+	client, err := analyzerCtx.GetAccountContext().GetClient(d.accountID, "")
 	if err != nil {
 		return nil, err
 	}
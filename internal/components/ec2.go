@@ -19,7 +19,7 @@ func NewEC2Instance(data *domain.EC2InstanceData, accountID string) *EC2Instance
 }
 
 func (e *EC2Instance) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID, e.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -32,18 +32,23 @@ func (e *EC2Instance) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 	}
 	subnet := NewSubnet(subnetData, e.accountID)
 
-	var terminal domain.Component = subnet
-	for i := len(e.data.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, e.data.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		terminal = NewSecurityGroupWithNext(sgData, e.accountID, terminal)
+	terminal, err := securityGroupFilter(ctx, client, e.data.SecurityGroups, e.accountID, subnet)
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{terminal}, nil
 }
 
+func (e *EC2Instance) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return ingressForIP(analyzerCtx, e.accountID, e.data.VPCID, &domain.ENIData{
+		PrivateIP:      e.data.PrivateIP,
+		SubnetID:       e.data.SubnetID,
+		SecurityGroups: e.data.SecurityGroups,
+		Region:         e.data.Region,
+	}, nil)
+}
+
 func (e *EC2Instance) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{
 		IP:       e.data.PrivateIP,
@@ -52,6 +57,10 @@ func (e *EC2Instance) GetRoutingTarget() domain.RoutingTarget {
 	}
 }
 
+func (e *EC2Instance) GetIPv6Addresses() []string {
+	return e.data.IPv6Addresses
+}
+
 func (e *EC2Instance) GetID() string {
 	return fmt.Sprintf("%s:%s", e.accountID, e.data.ID)
 }
@@ -65,11 +74,11 @@ func (e *EC2Instance) GetComponentType() string {
 }
 
 func (e *EC2Instance) GetVPCID() string {
-	return ""
+	return e.data.VPCID
 }
 
 func (e *EC2Instance) GetRegion() string {
-	return ""
+	return e.data.Region
 }
 
 func (e *EC2Instance) GetSubnetID() string {
@@ -77,7 +77,7 @@ func (eigw *EgressOnlyInternetGateway) GetVPCID() string {
 }
 
 func (eigw *EgressOnlyInternetGateway) GetRegion() string {
-	return ""
+	return eigw.data.Region
 }
 
 func (eigw *EgressOnlyInternetGateway) GetSubnetID() string {
@@ -87,3 +87,65 @@ func (eigw *EgressOnlyInternetGateway) GetSubnetID() string {
 func (eigw *EgressOnlyInternetGateway) GetAvailabilityZone() string {
 	return ""
 }
+
+// EgressOnlyInternetGatewayIngress is an egress-only internet gateway letting
+// replies to outbound IPv6 traffic back in to the network interface that sent
+// it. Connections opened from the internet are dropped.
+type EgressOnlyInternetGatewayIngress struct {
+	data      *domain.EgressOnlyInternetGatewayData
+	eni       *domain.ENIData
+	accountID string
+}
+
+func NewEgressOnlyInternetGatewayIngress(data *domain.EgressOnlyInternetGatewayData, eni *domain.ENIData, accountID string) *EgressOnlyInternetGatewayIngress {
+	return &EgressOnlyInternetGatewayIngress{
+		data:      data,
+		eni:       eni,
+		accountID: accountID,
+	}
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	if !dest.Reply {
+		return nil, &domain.BlockingError{
+			ComponentID: eigw.GetID(),
+			Reason:      "egress-only internet gateway does not accept connections from the internet",
+		}
+	}
+
+	addressed := *eigw.eni
+	addressed.PrivateIP = dest.IP
+	return []domain.Component{NewNetworkInterfaceFromData(&addressed, eigw.accountID)}, nil
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetID() string {
+	return fmt.Sprintf("%s:%s", eigw.accountID, eigw.data.ID)
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetAccountID() string {
+	return eigw.accountID
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetComponentType() string {
+	return "EgressOnlyInternetGateway"
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetVPCID() string {
+	return eigw.data.VPCID
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetRegion() string {
+	return eigw.data.Region
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetSubnetID() string {
+	return ""
+}
+
+func (eigw *EgressOnlyInternetGatewayIngress) GetAvailabilityZone() string {
+	return ""
+}
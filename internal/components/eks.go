@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -19,7 +20,7 @@ func NewEKSPod(data *domain.EKSPodData, accountID string) *EKSPod {
 }
 
 func (e *EKSPod) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID, e.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -38,19 +39,24 @@ func (e *EKSPod) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analy
 		return nil, err
 	}
 
-	var next domain.Component = NewSubnet(subnetData, e.accountID)
-
-	for i := len(e.data.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, e.data.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		next = NewSecurityGroupWithNext(sgData, e.accountID, next)
+	next, err := securityGroupFilter(ctx, client, e.data.SecurityGroups, e.accountID, NewSubnet(subnetData, e.accountID))
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{next}, nil
 }
 
+func (e *EKSPod) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return ingressForIP(analyzerCtx, e.accountID, e.data.VPCID, &domain.ENIData{
+		ID:             e.data.ENIID,
+		PrivateIP:      e.data.PodIP,
+		SubnetID:       e.data.SubnetID,
+		Region:         e.data.Region,
+		SecurityGroups: e.data.SecurityGroups,
+	}, nil)
+}
+
 func (e *EKSPod) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{
 		IP:       e.data.PodIP,
@@ -58,6 +64,15 @@ func (e *EKSPod) GetRoutingTarget() domain.RoutingTarget {
 	}
 }
 
+// GetIPv6Addresses returns the pod's address in an IPv6 cluster. Pods in an
+// IPv4 cluster have none.
+func (e *EKSPod) GetIPv6Addresses() []string {
+	if strings.Contains(e.data.PodIP, ":") {
+		return []string{e.data.PodIP}
+	}
+	return nil
+}
+
 func (e *EKSPod) GetID() string {
 	return fmt.Sprintf("%s:eks-pod:%s", e.accountID, e.data.PodIP)
 }
@@ -71,11 +86,11 @@ func (e *EKSPod) GetComponentType() string {
 }
 
 func (e *EKSPod) GetVPCID() string {
-	return ""
+	return e.data.VPCID
 }
 
 func (e *EKSPod) GetRegion() string {
-	return ""
+	return e.data.Region
 }
 
 func (e *EKSPod) GetSubnetID() string {
@@ -19,7 +19,7 @@ func NewElastiCacheCluster(data *domain.ElastiCacheClusterData, accountID string
 }
 
 func (e *ElastiCacheCluster) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID, e.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -38,19 +38,31 @@ func (e *ElastiCacheCluster) GetNextHops(dest domain.RoutingTarget, analyzerCtx
 		return nil, err
 	}
 
-	var next domain.Component = NewSubnet(subnetData, e.accountID)
-
-	for i := len(e.data.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, e.data.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		next = NewSecurityGroupWithNext(sgData, e.accountID, next)
+	next, err := securityGroupFilter(ctx, client, e.data.SecurityGroups, e.accountID, NewSubnet(subnetData, e.accountID))
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{next}, nil
 }
 
+func (e *ElastiCacheCluster) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return ingressForIP(analyzerCtx, e.accountID, e.data.VPCID, &domain.ENIData{
+		PrivateIP:      e.GetRoutingTarget().IP,
+		SecurityGroups: e.data.SecurityGroups,
+		Region:         e.data.Region,
+	}, e.data.SubnetIDs)
+}
+
+// GetIPv6Addresses returns the IPv6 addresses of the node the cluster is
+// addressed through.
+func (e *ElastiCacheCluster) GetIPv6Addresses() []string {
+	if len(e.data.Nodes) == 0 {
+		return nil
+	}
+	return e.data.Nodes[0].IPv6Addresses
+}
+
 func (e *ElastiCacheCluster) GetRoutingTarget() domain.RoutingTarget {
 	ip := ""
 	port := e.data.Port
@@ -100,7 +112,7 @@ func (e *ElastiCacheCluster) GetVPCID() string {
 }
 
 func (e *ElastiCacheCluster) GetRegion() string {
-	return ""
+	return e.data.Region
 }
 
 func (e *ElastiCacheCluster) GetSubnetID() string {
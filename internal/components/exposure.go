@@ -0,0 +1,245 @@
+package components
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+// PublicInterface is a network interface the internet can address, together
+// with the resource that owns it.
+type PublicInterface struct {
+	ENI   *domain.ENIData
+	Owner domain.Component
+	// Err is why the owner could not be looked up. Owner is then the
+	// interface itself and whether it is exposed is undecided.
+	Err error
+}
+
+// PublicInterfaces lists the network interfaces in vpcID, in region, that have
+// a public IPv4 address or an IPv6 address. Interfaces of internal load
+// balancers and of components that only forward traffic are left out. An
+// interface whose owner cannot be looked up is listed with the error.
+func PublicInterfaces(analyzerCtx domain.AnalyzerContext, accountID, region, vpcID string) ([]PublicInterface, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(accountID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	enis, err := client.GetENIsByVPC(ctx, vpcID)
+	if err != nil {
+		return nil, err
+	}
+
+	var public []PublicInterface
+	for i := range enis {
+		eni := &enis[i]
+		if (eni.PublicIP == "" && len(eni.IPv6Addresses) == 0) || forwardingInterfaceTypes[eni.InterfaceType] {
+			continue
+		}
+
+		var owner domain.Component = NewNetworkInterfaceFromData(eni, accountID)
+		switch {
+		case eni.InstanceID != "":
+			instance, err := client.GetEC2Instance(ctx, eni.InstanceID)
+			if err != nil {
+				public = append(public, PublicInterface{ENI: eni, Owner: owner, Err: err})
+				continue
+			}
+			if instance.PrivateIP == eni.PrivateIP {
+				owner = NewEC2Instance(instance, accountID)
+			}
+
+		case strings.HasPrefix(eni.Description, "ELB app/"):
+			alb, err := client.GetALBByPrivateIP(ctx, eni.PrivateIP, vpcID)
+			if err != nil {
+				public = append(public, PublicInterface{ENI: eni, Owner: owner, Err: err})
+				continue
+			}
+			if alb != nil {
+				if alb.Scheme != "internet-facing" {
+					continue
+				}
+				owner = NewALB(alb, accountID)
+			}
+
+		case strings.HasPrefix(eni.Description, "ELB net/"):
+			nlb, err := client.GetNLBByPrivateIP(ctx, eni.PrivateIP, vpcID)
+			if err != nil {
+				public = append(public, PublicInterface{ENI: eni, Owner: owner, Err: err})
+				continue
+			}
+			if nlb != nil {
+				if nlb.Scheme != "internet-facing" {
+					continue
+				}
+				owner = NewNLB(nlb, accountID)
+			}
+
+		case strings.HasPrefix(eni.Description, "ELB "):
+			clb, err := client.GetCLBByPrivateIP(ctx, eni.PrivateIP, vpcID)
+			if err != nil {
+				public = append(public, PublicInterface{ENI: eni, Owner: owner, Err: err})
+				continue
+			}
+			if clb != nil {
+				if clb.Scheme != "internet-facing" {
+					continue
+				}
+				owner = NewCLB(clb, accountID)
+			}
+		}
+
+		public = append(public, PublicInterface{ENI: eni, Owner: owner})
+	}
+
+	return public, nil
+}
+
+// InternetFlows returns the TCP and UDP port ranges of eni that its security
+// groups open to the whole of source, 0.0.0.0/0 or ::/0. Only rules naming
+// exactly that range count, not ones covering part of it. Ranges are split
+// wherever a security group or NACL rule starts or ends, so each one is
+// treated the same way port by port and can be tested as a single flow.
+func InternetFlows(analyzerCtx domain.AnalyzerContext, eni *domain.ENIData, accountID, source string) ([]domain.FlowSpec, error) {
+	_, network, err := net.ParseCIDR(source)
+	if err != nil {
+		return nil, fmt.Errorf("internet source %q: %w", source, err)
+	}
+	sourceIP := network.IP.String()
+
+	client, err := analyzerCtx.GetAccountContext().GetClient(accountID, eni.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	var groups []*SecurityGroup
+	for _, sgID := range eni.SecurityGroups {
+		sgData, err := client.GetSecurityGroup(ctx, sgID)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, NewSecurityGroup(sgData, accountID))
+	}
+
+	subnetData, err := client.GetSubnet(ctx, eni.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	naclData, err := client.GetNACL(ctx, subnetData.NaclID)
+	if err != nil {
+		return nil, err
+	}
+
+	var flows []domain.FlowSpec
+	for _, protocol := range []string{"tcp", "udp"} {
+		var open [][2]int
+		boundaries := []int{minPort, maxPort + 1}
+
+		for _, sg := range groups {
+			for _, rule := range sg.data.InboundRules {
+				if !protocolMatches(rule.Protocol, protocol) {
+					continue
+				}
+				admitted, err := sg.ruleOpensTo(rule, source, analyzerCtx)
+				if err != nil {
+					return nil, err
+				}
+				if !admitted {
+					continue
+				}
+				from, to := clampPorts(rule.FromPort, rule.ToPort)
+				open = append(open, [2]int{from, to})
+				boundaries = append(boundaries, from, to+1)
+			}
+		}
+		if len(open) == 0 {
+			continue
+		}
+
+		for _, rule := range naclData.InboundRules {
+			if !protocolMatches(rule.Protocol, protocol) {
+				continue
+			}
+			if !IPMatchesCIDR(sourceIP, rule.CIDRBlock) && !IPMatchesCIDR(sourceIP, rule.IPv6CIDRBlock) {
+				continue
+			}
+			from, to := clampPorts(rule.FromPort, rule.ToPort)
+			boundaries = append(boundaries, from, to+1)
+		}
+
+		sort.Ints(boundaries)
+		for i := 0; i+1 < len(boundaries); i++ {
+			from, to := boundaries[i], boundaries[i+1]-1
+			if from > to || !rangeCovered(open, from) {
+				continue
+			}
+			flow := domain.FlowSpec{Protocol: protocol, FromPort: from}
+			if to > from {
+				flow.ToPort = to
+			}
+			flows = append(flows, flow)
+		}
+	}
+
+	return flows, nil
+}
+
+// ruleOpensTo reports whether rule names source itself, directly or through a
+// prefix list, ignoring ports and protocol.
+func (sg *SecurityGroup) ruleOpensTo(rule domain.SecurityGroupRule, source string, analyzerCtx domain.AnalyzerContext) (bool, error) {
+	for _, cidr := range append(append([]string{}, rule.CIDRBlocks...), rule.IPv6CIDRBlocks...) {
+		if SameCIDR(cidr, source) {
+			return true, nil
+		}
+	}
+	if len(rule.PrefixListIDs) == 0 || analyzerCtx == nil {
+		return false, nil
+	}
+	client, err := analyzerCtx.GetAccountContext().GetClient(sg.accountID, sg.data.Region)
+	if err != nil {
+		return false, err
+	}
+	for _, plID := range rule.PrefixListIDs {
+		pl, err := client.GetManagedPrefixList(analyzerCtx.Context(), plID)
+		if err != nil {
+			return false, fmt.Errorf("prefix list %s: %w", plID, err)
+		}
+		for _, entry := range pl.Entries {
+			if SameCIDR(entry.CIDR, source) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func clampPorts(fromPort, toPort int) (int, int) {
+	if isAnyPort(fromPort, toPort) {
+		return minPort, maxPort
+	}
+	return max(fromPort, minPort), min(toPort, maxPort)
+}
+
+// rangeCovered reports whether port falls in one of ranges. Ranges are split
+// at every rule boundary, so one port decides for the whole range.
+func rangeCovered(ranges [][2]int, port int) bool {
+	for _, r := range ranges {
+		if port >= r[0] && port <= r[1] {
+			return true
+		}
+	}
+	return false
+}
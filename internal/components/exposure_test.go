@@ -0,0 +1,107 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+func TestPublicInterfaces_SkipsInternalLoadBalancers(t *testing.T) {
+	client := newMockAWSClient()
+	client.enisByVPC["vpc-1"] = []domain.ENIData{
+		{ID: "eni-web", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.3", InstanceID: "i-web", InterfaceType: "interface"},
+		{ID: "eni-v6", PrivateIP: "10.0.1.11", IPv6Addresses: []string{"2600:1f18::11"}, InterfaceType: "interface"},
+		{ID: "eni-alb", PrivateIP: "10.0.1.12", PublicIP: "54.1.2.5", InterfaceType: "interface", Description: "ELB app/internal/abc"},
+		{ID: "eni-nat", PrivateIP: "10.0.1.13", PublicIP: "54.1.2.6", InterfaceType: "natGateway"},
+		{ID: "eni-app", PrivateIP: "10.0.1.14", InterfaceType: "interface"},
+	}
+	client.ec2Instances["i-web"] = &domain.EC2InstanceData{ID: "i-web", PrivateIP: "10.0.1.10"}
+	client.albs["internal"] = &domain.ALBData{ARN: "internal", Scheme: "internal", VPCID: "vpc-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+
+	public, err := PublicInterfaces(newMockAnalyzerContext(accountCtx), "111111111111", "", "vpc-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"EC2Instance 111111111111:i-web", "NetworkInterface 111111111111:eni-v6"}
+	if len(public) != len(want) {
+		t.Fatalf("expected %d interfaces, got %d", len(want), len(public))
+	}
+	for i, iface := range public {
+		if got := iface.Owner.GetComponentType() + " " + iface.Owner.GetID(); got != want[i] {
+			t.Errorf("interface %d: expected %s, got %s", i, want[i], got)
+		}
+	}
+}
+
+func TestPublicInterfaces_FailedOwnerLookupKeepsScanning(t *testing.T) {
+	client := newMockAWSClient()
+	client.enisByVPC["vpc-1"] = []domain.ENIData{
+		{ID: "eni-gone", PrivateIP: "10.0.1.9", PublicIP: "54.1.2.2", InstanceID: "i-gone", InterfaceType: "interface"},
+		{ID: "eni-web", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.3", InstanceID: "i-web", InterfaceType: "interface"},
+	}
+	client.ec2Instances["i-web"] = &domain.EC2InstanceData{ID: "i-web", PrivateIP: "10.0.1.10"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+
+	public, err := PublicInterfaces(newMockAnalyzerContext(accountCtx), "111111111111", "", "vpc-1")
+	if err != nil {
+		t.Fatalf("expected a failed owner lookup not to abort the scan, got %v", err)
+	}
+	if len(public) != 2 {
+		t.Fatalf("expected both interfaces, got %d", len(public))
+	}
+	if public[0].Err == nil || public[0].Owner.GetID() != "111111111111:eni-gone" {
+		t.Errorf("expected eni-gone listed as itself with the lookup error, got %s, %v", public[0].Owner.GetID(), public[0].Err)
+	}
+	if public[1].Err != nil || public[1].Owner.GetComponentType() != "EC2Instance" {
+		t.Errorf("expected i-web to be found, got %s, %v", public[1].Owner.GetComponentType(), public[1].Err)
+	}
+}
+
+func TestInternetFlows_SplitsAtRuleBoundaries(t *testing.T) {
+	client := newMockAWSClient()
+	client.securityGroups["sg-web"] = &domain.SecurityGroupData{
+		ID: "sg-web",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRBlocks: []string{"0.0.0.0/0"}},
+			{Protocol: "tcp", FromPort: 80, ToPort: 443, CIDRBlocks: []string{"0.0.0.0/0"}},
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.0.0/16"}},
+			{Protocol: "tcp", FromPort: 3306, ToPort: 3306, CIDRBlocks: []string{"0.0.0.0/8", "0.0.0.0/1"}},
+			{Protocol: "udp", FromPort: 53, ToPort: 53, IPv6CIDRBlocks: []string{"::/0"}},
+		},
+	}
+	client.subnets["subnet-1"] = &domain.SubnetData{ID: "subnet-1", NaclID: "acl-1"}
+	client.nacls["acl-1"] = &domain.NACLData{
+		ID: "acl-1",
+		InboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "tcp", FromPort: 100, ToPort: 199, CIDRBlock: "0.0.0.0/0", Action: "deny"},
+			{RuleNumber: 200, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"},
+		},
+	}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	eni := &domain.ENIData{ID: "eni-web", SubnetID: "subnet-1", SecurityGroups: []string{"sg-web"}}
+
+	flows, err := InternetFlows(newMockAnalyzerContext(accountCtx), eni, "111111111111", "0.0.0.0/0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.FlowSpec{
+		{Protocol: "tcp", FromPort: 22},
+		{Protocol: "tcp", FromPort: 80, ToPort: 99},
+		{Protocol: "tcp", FromPort: 100, ToPort: 199},
+		{Protocol: "tcp", FromPort: 200, ToPort: 443},
+	}
+	if len(flows) != len(want) {
+		t.Fatalf("expected %v, got %v", want, flows)
+	}
+	for i := range want {
+		if flows[i] != want[i] {
+			t.Errorf("flow %d: expected %+v, got %+v", i, want[i], flows[i])
+		}
+	}
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -185,6 +186,66 @@ func TestNATGateway_GetID(t *testing.T) {
 	}
 }
 
+func TestNATGateway_TranslatesSourceToPublicIP(t *testing.T) {
+	nat := NewNATGateway(&domain.NATGatewayData{ID: "nat-abc", PublicIP: "54.1.2.3"}, "111111111111")
+
+	translated := nat.Translate(domain.RoutingTarget{IP: "8.8.8.8", SourceIP: "10.0.1.10", SourceIsPrivate: true})
+	if translated.SourceIP != "54.1.2.3" || translated.SourceIsPrivate {
+		t.Errorf("expected source 54.1.2.3 and public, got %s (private %v)", translated.SourceIP, translated.SourceIsPrivate)
+	}
+
+	reply := nat.Translate(domain.RoutingTarget{IP: "10.0.1.10", SourceIP: "8.8.8.8", Reply: true})
+	if reply.SourceIP != "8.8.8.8" {
+		t.Errorf("expected replies to be left alone, got source %s", reply.SourceIP)
+	}
+}
+
+func TestNATGateway_Private_RoutesPrivateDestinationThroughSubnet(t *testing.T) {
+	client := newMockAWSClient()
+	client.subnets["subnet-123"] = &domain.SubnetData{
+		ID:           "subnet-123",
+		VPCID:        "vpc-1",
+		NaclID:       "nacl-1",
+		RouteTableID: "rtb-1",
+	}
+	client.nacls["nacl-1"] = &domain.NACLData{ID: "nacl-1"}
+	client.routeTables["rtb-1"] = &domain.RouteTableData{
+		ID:    "rtb-1",
+		VPCID: "vpc-1",
+		Routes: []domain.Route{
+			{DestinationCIDR: "10.0.0.0/8", PrefixLength: 8, TargetType: "transit-gateway", TargetID: "tgw-1"},
+		},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	nat := NewNATGateway(&domain.NATGatewayData{
+		ID:               "nat-123",
+		SubnetID:         "subnet-123",
+		PrivateIP:        "100.64.0.10",
+		ConnectivityType: "private",
+	}, "111111111111")
+
+	dest := domain.RoutingTarget{IP: "10.20.1.5", Port: 443, Protocol: "tcp", Direction: "outbound", SourceIsPrivate: true}
+	hops, err := nat.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	if _, ok := hops[0].(*Subnet); !ok {
+		t.Fatalf("expected Subnet hop, got %T", hops[0])
+	}
+
+	translated := nat.Translate(domain.RoutingTarget{IP: "10.20.1.5", SourceIP: "10.0.1.10", SourceIsPrivate: true})
+	if translated.SourceIP != "100.64.0.10" || !translated.SourceIsPrivate {
+		t.Errorf("expected private source 100.64.0.10, got %s (private %v)", translated.SourceIP, translated.SourceIsPrivate)
+	}
+}
+
 func TestVPCEndpoint_GetNextHops(t *testing.T) {
 	client := newMockAWSClient()
 	client.subnets["subnet-1"] = &domain.SubnetData{
@@ -254,6 +315,7 @@ func TestVPCEndpoint_GetID(t *testing.T) {
 
 func TestVPCPeering_GetNextHops_ToAccepterVPC(t *testing.T) {
 	sourceClient := newMockAWSClient()
+	sourceClient.vpcs["vpc-123"] = &domain.VPCData{ID: "vpc-123", CIDRBlock: "10.0.0.0/16"}
 
 	accepterClient := newMockAWSClient()
 	accepterClient.vpcs["vpc-456"] = &domain.VPCData{
@@ -312,8 +374,12 @@ func TestVPCPeering_GetNextHops_ToRequesterVPC(t *testing.T) {
 		VPCID: "vpc-123",
 	}
 
+	accepterClient := newMockAWSClient()
+	accepterClient.vpcs["vpc-456"] = &domain.VPCData{ID: "vpc-456", CIDRBlock: "10.1.0.0/16"}
+
 	accountCtx := newMockAccountContext()
 	accountCtx.addClient("111111111111", requesterClient)
+	accountCtx.addClient("222222222222", accepterClient)
 	analyzerCtx := newMockAnalyzerContext(accountCtx)
 
 	peering := NewVPCPeering(&domain.VPCPeeringData{
@@ -345,6 +411,214 @@ func TestVPCPeering_GetNextHops_ToRequesterVPC(t *testing.T) {
 	}
 }
 
+func TestVPCPeering_GetNextHops_EntersPeerRegion(t *testing.T) {
+	homeClient := newMockAWSClient()
+	homeClient.vpcs["vpc-eu"] = &domain.VPCData{ID: "vpc-eu", MainRouteTableID: "rtb-wrong"}
+	homeClient.vpcs["vpc-us"] = &domain.VPCData{ID: "vpc-us", CIDRBlock: "10.0.0.0/16"}
+
+	peerClient := newMockAWSClient()
+	peerClient.vpcs["vpc-eu"] = &domain.VPCData{ID: "vpc-eu", CIDRBlock: "10.1.0.0/16", MainRouteTableID: "rtb-eu"}
+	peerClient.routeTables["rtb-eu"] = &domain.RouteTableData{ID: "rtb-eu", VPCID: "vpc-eu", Region: "eu-west-1"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addRegionalClient("111111111111", "us-east-1", homeClient)
+	accountCtx.addRegionalClient("111111111111", "eu-west-1", peerClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	peering := NewVPCPeering(&domain.VPCPeeringData{
+		ID:              "pcx-dr",
+		RequesterVPC:    "vpc-us",
+		RequesterOwner:  "111111111111",
+		RequesterRegion: "us-east-1",
+		AccepterVPC:     "vpc-eu",
+		AccepterOwner:   "111111111111",
+		AccepterRegion:  "eu-west-1",
+		Region:          "us-east-1",
+	}, "111111111111", "vpc-us")
+
+	hops, err := peering.GetNextHops(domain.RoutingTarget{IP: "10.1.1.100", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	rt, ok := hops[0].(*RouteTable)
+	if !ok {
+		t.Fatalf("expected RouteTable, got %T", hops[0])
+	}
+	if rt.data.ID != "rtb-eu" || rt.GetRegion() != "eu-west-1" {
+		t.Errorf("expected rtb-eu in eu-west-1, got %s in %q", rt.data.ID, rt.GetRegion())
+	}
+}
+
+func TestVPCPeering_GetNextHops_EntersDestinationSubnet(t *testing.T) {
+	accepterClient := newMockAWSClient()
+	accepterClient.vpcs["vpc-456"] = &domain.VPCData{ID: "vpc-456", CIDRBlock: "10.1.0.0/16", MainRouteTableID: "rtb-main"}
+	accepterClient.subnets["subnet-db"] = &domain.SubnetData{
+		ID:           "subnet-db",
+		VPCID:        "vpc-456",
+		CIDRBlock:    "10.1.1.0/24",
+		NaclID:       "nacl-db",
+		RouteTableID: "rtb-db",
+	}
+	accepterClient.nacls["nacl-db"] = &domain.NACLData{
+		ID: "nacl-db",
+		InboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlock: "10.0.0.0/16", Action: "allow"},
+		},
+	}
+	accepterClient.routeTables["rtb-db"] = &domain.RouteTableData{ID: "rtb-db", VPCID: "vpc-456"}
+
+	requesterClient := newMockAWSClient()
+	requesterClient.vpcs["vpc-123"] = &domain.VPCData{ID: "vpc-123", CIDRBlock: "10.0.0.0/16"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", requesterClient)
+	accountCtx.addClient("222222222222", accepterClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	peering := NewVPCPeering(&domain.VPCPeeringData{
+		ID:             "pcx-123",
+		RequesterVPC:   "vpc-123",
+		RequesterOwner: "111111111111",
+		AccepterVPC:    "vpc-456",
+		AccepterOwner:  "222222222222",
+	}, "111111111111", "vpc-123")
+
+	dest := domain.RoutingTarget{IP: "10.1.1.20", Port: 5432, Protocol: "tcp", SourceIP: "10.0.1.10"}
+	hops, err := peering.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	ingress, ok := hops[0].(*SubnetIngress)
+	if !ok {
+		t.Fatalf("expected SubnetIngress, got %T", hops[0])
+	}
+	if ingress.GetSubnetID() != "subnet-db" {
+		t.Errorf("expected the hop to record subnet-db, got %q", ingress.GetSubnetID())
+	}
+
+	hops, err = ingress.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rt, ok := hops[0].(*RouteTable)
+	if !ok {
+		t.Fatalf("expected RouteTable, got %T", hops[0])
+	}
+	if rt.data.ID != "rtb-db" {
+		t.Errorf("expected the subnet's route table rtb-db, got %s", rt.data.ID)
+	}
+
+	dest.SourceIP = "10.9.0.10"
+	_, err = ingress.GetNextHops(dest, analyzerCtx)
+	var blockingErr *domain.BlockingError
+	if !errors.As(err, &blockingErr) {
+		t.Fatalf("expected the subnet's NACL to block 10.9.0.10, got %v", err)
+	}
+}
+
+func newPeeringFixture(requesterCIDR string) (*VPCPeering, *mockAnalyzerContext) {
+	requesterClient := newMockAWSClient()
+	requesterClient.vpcs["vpc-123"] = &domain.VPCData{ID: "vpc-123", CIDRBlock: requesterCIDR}
+
+	accepterClient := newMockAWSClient()
+	accepterClient.vpcs["vpc-456"] = &domain.VPCData{ID: "vpc-456", CIDRBlock: "10.1.0.0/16", MainRouteTableID: "rtb-456"}
+	accepterClient.routeTables["rtb-456"] = &domain.RouteTableData{ID: "rtb-456", VPCID: "vpc-456"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", requesterClient)
+	accountCtx.addClient("222222222222", accepterClient)
+
+	peering := NewVPCPeering(&domain.VPCPeeringData{
+		ID:             "pcx-123",
+		RequesterVPC:   "vpc-123",
+		RequesterOwner: "111111111111",
+		AccepterVPC:    "vpc-456",
+		AccepterOwner:  "222222222222",
+	}, "111111111111", "vpc-123")
+	return peering, newMockAnalyzerContext(accountCtx)
+}
+
+func TestVPCPeering_GetNextHops_PeeringRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		requesterCIDR string
+		dest          domain.RoutingTarget
+		reason        string
+	}{
+		{
+			name:          "transitive peering",
+			requesterCIDR: "10.0.0.0/16",
+			dest:          domain.RoutingTarget{IP: "10.1.1.100", Port: 443, Protocol: "tcp", ViaPeering: "pcx-other"},
+			reason:        "peering is not transitive",
+		},
+		{
+			name:          "destination beyond the peer VPC",
+			requesterCIDR: "10.0.0.0/16",
+			dest:          domain.RoutingTarget{IP: "10.9.1.100", Port: 443, Protocol: "tcp"},
+			reason:        "outside peer VPC vpc-456",
+		},
+		{
+			name:          "overlapping CIDRs",
+			requesterCIDR: "10.1.128.0/17",
+			dest:          domain.RoutingTarget{IP: "10.1.1.100", Port: 443, Protocol: "tcp"},
+			reason:        "overlaps peer VPC vpc-456",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peering, analyzerCtx := newPeeringFixture(tt.requesterCIDR)
+
+			_, err := peering.GetNextHops(tt.dest, analyzerCtx)
+			var blockingErr *domain.BlockingError
+			if !errors.As(err, &blockingErr) {
+				t.Fatalf("expected BlockingError, got %v", err)
+			}
+			if !strings.Contains(blockingErr.Reason, tt.reason) {
+				t.Errorf("expected reason to contain %q, got %q", tt.reason, blockingErr.Reason)
+			}
+		})
+	}
+}
+
+func TestVPCPeering_GetNextHops_SecondaryCIDRs(t *testing.T) {
+	peering, analyzerCtx := newPeeringFixture("10.0.0.0/16")
+	accepterClient, _ := analyzerCtx.GetAccountContext().GetClient("222222222222", "")
+	accepterClient.(*mockAWSClient).vpcs["vpc-456"].SecondaryCIDRBlocks = []string{"100.64.0.0/16"}
+
+	dest := domain.RoutingTarget{IP: "100.64.1.10", Port: 443, Protocol: "tcp"}
+	if _, err := peering.GetNextHops(dest, analyzerCtx); err != nil {
+		t.Fatalf("expected a destination in the peer's secondary CIDR to be delivered, got %v", err)
+	}
+
+	requesterClient, _ := analyzerCtx.GetAccountContext().GetClient("111111111111", "")
+	requesterClient.(*mockAWSClient).vpcs["vpc-123"].SecondaryCIDRBlocks = []string{"100.64.128.0/17"}
+
+	_, err := peering.GetNextHops(dest, analyzerCtx)
+	var blockingErr *domain.BlockingError
+	if !errors.As(err, &blockingErr) || !strings.Contains(blockingErr.Reason, "100.64.128.0/17") {
+		t.Fatalf("expected overlapping secondary CIDRs to block, got %v", err)
+	}
+}
+
+func TestVPCPeering_TranslateMarksFlow(t *testing.T) {
+	peering, analyzerCtx := newPeeringFixture("10.0.0.0/16")
+
+	dest := domain.RoutingTarget{IP: "10.1.1.100", Port: 443, Protocol: "tcp"}
+	if _, err := peering.GetNextHops(dest, analyzerCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := peering.Translate(dest); got.ViaPeering != "pcx-123" || got.Flow() != dest.Flow() {
+		t.Errorf("expected only ViaPeering to be set to pcx-123, got %+v", got)
+	}
+}
+
 func TestVPCPeering_GetID(t *testing.T) {
 	peering := NewVPCPeering(&domain.VPCPeeringData{ID: "pcx-abc", RequesterOwner: "111111111111"}, "111111111111", "vpc-123")
 
@@ -507,13 +781,13 @@ func TestTransitGatewayVPCAttachmentInbound_GetNextHops_DestInSubnet(t *testing.
 		t.Fatalf("expected 1 hop, got %d", len(hops))
 	}
 
-	rt, ok := hops[0].(*RouteTable)
+	ingress, ok := hops[0].(*SubnetIngress)
 	if !ok {
-		t.Fatalf("expected RouteTable, got %T", hops[0])
+		t.Fatalf("expected SubnetIngress, got %T", hops[0])
 	}
 
-	if rt.data.ID != "rtb-123" {
-		t.Errorf("expected rtb-123, got %s", rt.data.ID)
+	if ingress.GetSubnetID() != "subnet-123" {
+		t.Errorf("expected subnet-123, got %s", ingress.GetSubnetID())
 	}
 }
 
@@ -936,9 +1210,9 @@ func TestTransitGatewayVPCAttachmentInbound_GetNextHops_MultipleSubnets(t *testi
 		t.Fatalf("expected 1 hop, got %d", len(hops))
 	}
 
-	rt := hops[0].(*RouteTable)
-	if rt.data.ID != "rtb-2" {
-		t.Errorf("expected rtb-2, got %s", rt.data.ID)
+	ingress := hops[0].(*SubnetIngress)
+	if ingress.data.RouteTableID != "rtb-2" {
+		t.Errorf("expected subnet-2 with rtb-2, got %s with %s", ingress.GetSubnetID(), ingress.data.RouteTableID)
 	}
 }
 
@@ -1034,10 +1308,11 @@ func TestVirtualPrivateGateway_GetRoutingTarget(t *testing.T) {
 
 func TestVPNConnection_GetNextHops(t *testing.T) {
 	vpn := NewVPNConnection(&domain.VPNConnectionData{
-		ID:          "vpn-123",
-		VGWID:       "vgw-123",
-		State:       "available",
-		HasUpTunnel: true,
+		ID:                 "vpn-123",
+		VGWID:              "vgw-123",
+		State:              "available",
+		HasUpTunnel:        true,
+		AcceptedRouteCount: 2,
 	}, "111111111111")
 
 	dest := domain.RoutingTarget{IP: "192.168.1.100", Port: 443, Protocol: "tcp"}
@@ -1050,6 +1325,58 @@ func TestVPNConnection_GetNextHops(t *testing.T) {
 	}
 }
 
+func TestVPNConnection_GetNextHops_OnPremRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *domain.VPNConnectionData
+		wantErr string
+	}{
+		{
+			name: "static route covers destination",
+			data: &domain.VPNConnectionData{VGWID: "vgw-123", StaticRoutesOnly: true, StaticRoutes: []string{"192.168.0.0/16"}},
+		},
+		{
+			name:    "no static route to destination",
+			data:    &domain.VPNConnectionData{VGWID: "vgw-123", StaticRoutesOnly: true, StaticRoutes: []string{"172.16.0.0/12"}},
+			wantErr: "no static VPN route",
+		},
+		{
+			name: "static transit gateway connection",
+			data: &domain.VPNConnectionData{StaticRoutesOnly: true},
+		},
+		{
+			name:    "no BGP routes learned",
+			data:    &domain.VPNConnectionData{VGWID: "vgw-123"},
+			wantErr: "over BGP",
+		},
+		{
+			name: "BGP routes learned",
+			data: &domain.VPNConnectionData{AcceptedRouteCount: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.data.ID = "vpn-123"
+			tt.data.State = "available"
+			tt.data.HasUpTunnel = true
+			vpn := NewVPNConnection(tt.data, "111111111111")
+
+			_, err := vpn.GetNextHops(domain.RoutingTarget{IP: "192.168.1.100", Port: 443, Protocol: "tcp"}, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var blockErr *domain.BlockingError
+			if !errors.As(err, &blockErr) || !strings.Contains(blockErr.Reason, tt.wantErr) {
+				t.Fatalf("expected BlockingError containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestVPNConnection_GetID(t *testing.T) {
 	vpn := NewVPNConnection(&domain.VPNConnectionData{ID: "vpn-abc"}, "111111111111")
 
@@ -1091,6 +1418,41 @@ func TestDirectConnectGateway_GetNextHops(t *testing.T) {
 	}
 }
 
+func TestDirectConnectGateway_GetNextHops_AllowedPrefixes(t *testing.T) {
+	data := &domain.DirectConnectGatewayData{
+		ID:    "dxgw-123",
+		State: "available",
+		AllowedPrefixes: map[string][]string{
+			"tgw-a": {"10.0.0.0/16"},
+			"tgw-b": {"10.2.0.0/16"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		dxgw    *DirectConnectGateway
+		dest    domain.RoutingTarget
+		wantErr bool
+	}{
+		{"inbound to advertised prefix", NewDirectConnectGateway(data, "111111111111"), domain.RoutingTarget{IP: "10.0.1.5", SourceIP: "192.168.1.10"}, false},
+		{"inbound to unadvertised prefix", NewDirectConnectGateway(data, "111111111111"), domain.RoutingTarget{IP: "10.1.1.5", SourceIP: "192.168.1.10"}, true},
+		{"inbound to another association's prefix", NewDirectConnectGateway(data, "111111111111"), domain.RoutingTarget{IP: "10.2.1.5", SourceIP: "192.168.1.10"}, false},
+		{"outbound from advertised prefix", NewOutboundDirectConnectGateway(data, "tgw-a", "111111111111"), domain.RoutingTarget{IP: "192.168.1.10", SourceIP: "10.0.1.5"}, false},
+		{"outbound from unadvertised prefix", NewOutboundDirectConnectGateway(data, "tgw-a", "111111111111"), domain.RoutingTarget{IP: "192.168.1.10", SourceIP: "10.1.1.5"}, true},
+		{"outbound from prefix of another association", NewOutboundDirectConnectGateway(data, "tgw-a", "111111111111"), domain.RoutingTarget{IP: "192.168.1.10", SourceIP: "10.2.1.5"}, true},
+		{"outbound from unassociated gateway", NewOutboundDirectConnectGateway(data, "tgw-c", "111111111111"), domain.RoutingTarget{IP: "192.168.1.10", SourceIP: "10.0.1.5"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.dxgw.GetNextHops(tt.dest, nil)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestDirectConnectGateway_GetID(t *testing.T) {
 	dxgw := NewDirectConnectGateway(&domain.DirectConnectGatewayData{ID: "dxgw-abc"}, "111111111111")
 
@@ -1170,6 +1532,44 @@ func TestTGWPeeringAttachment_GetNextHops_Success(t *testing.T) {
 	}
 }
 
+func TestTGWPeeringAttachment_GetNextHops_EntersPeerRegion(t *testing.T) {
+	peerClient := newMockAWSClient()
+	peerClient.transitGWs["tgw-eu"] = &domain.TransitGatewayData{ID: "tgw-eu", OwnerID: "111111111111", Region: "eu-west-1"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addRegionalClient("111111111111", "eu-west-1", peerClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	peering := NewTGWPeeringAttachment(&domain.TGWPeeringAttachmentData{
+		ID:                   "tgw-attach-dr",
+		TransitGatewayID:     "tgw-us",
+		PeerTransitGatewayID: "tgw-eu",
+		PeerAccountID:        "111111111111",
+		PeerRegion:           "eu-west-1",
+		Region:               "us-east-1",
+	}, "111111111111")
+
+	hops, err := peering.GetNextHops(domain.RoutingTarget{IP: "10.1.1.100", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	peerTGW, ok := hops[0].(*TransitGateway)
+	if !ok {
+		t.Fatalf("expected TransitGateway, got %T", hops[0])
+	}
+	if peerTGW.GetRegion() != "eu-west-1" {
+		t.Errorf("expected peer transit gateway in eu-west-1, got %q", peerTGW.GetRegion())
+	}
+
+	lineage := domain.HopLineage{SourceID: peering.GetID(), SourceType: "TGWPeeringAttachment", SourceRegion: peering.GetRegion()}
+	if hop := domain.HopFromComponent(peerTGW, lineage, domain.HopActionRouted, ""); !hop.CrossRegion {
+		t.Error("expected hop into the peer region to be marked cross-region")
+	}
+}
+
 func TestTGWPeeringAttachment_GetID(t *testing.T) {
 	peering := NewTGWPeeringAttachment(&domain.TGWPeeringAttachmentData{ID: "tgw-attach-peer"}, "111111111111")
 
@@ -1661,3 +2061,81 @@ func TestMultiTGW_TraversalPath(t *testing.T) {
 		t.Fatalf("expected TransitGatewayVPCAttachmentInbound from TGW2, got %T", hops3[0])
 	}
 }
+
+func TestVirtualPrivateGatewayIngress_FollowsEdgeRouteTable(t *testing.T) {
+	client := newMockAWSClient()
+	client.gatewayRouteTables["vgw-1"] = &domain.RouteTableData{
+		ID:    "rtb-edge",
+		VPCID: "vpc-1",
+		Routes: []domain.Route{
+			{DestinationCIDR: "10.0.0.0/16", PrefixLength: 16, TargetType: "local", TargetID: "local"},
+			{DestinationCIDR: "10.0.1.0/24", PrefixLength: 24, TargetType: "vpc-endpoint", TargetID: "vpce-gwlb"},
+		},
+		Associations: []domain.RouteTableAssociation{{GatewayID: "vgw-1"}},
+	}
+	client.subnets["subnet-db"] = &domain.SubnetData{ID: "subnet-db", VPCID: "vpc-1", CIDRBlock: "10.0.2.0/24", NaclID: "nacl-db", RouteTableID: "rtb-db"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	vgw := NewVirtualPrivateGatewayIngress(&domain.VirtualPrivateGatewayData{ID: "vgw-1", VPCID: "vpc-1"}, "111111111111")
+
+	hops, err := vgw.GetNextHops(domain.RoutingTarget{IP: "10.0.1.10", SourceIP: "192.168.1.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetID() != "111111111111:rtb-edge" {
+		t.Fatalf("expected the edge route table, got %v", hops)
+	}
+
+	hops, err = vgw.GetNextHops(domain.RoutingTarget{IP: "10.0.2.10", SourceIP: "192.168.1.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	if ingress, ok := hops[0].(*SubnetIngress); !ok || ingress.GetSubnetID() != "subnet-db" {
+		t.Errorf("expected delivery into subnet-db, got %v", hops)
+	}
+}
+
+func TestVirtualPrivateGatewayIngress_BlocksWhenDetached(t *testing.T) {
+	vgw := NewVirtualPrivateGatewayIngress(&domain.VirtualPrivateGatewayData{ID: "vgw-1"}, "111111111111")
+
+	_, err := vgw.GetNextHops(domain.RoutingTarget{IP: "10.0.1.10"}, newMockAnalyzerContext(newMockAccountContext()))
+	var blockingErr *domain.BlockingError
+	if !errors.As(err, &blockingErr) {
+		t.Fatalf("expected BlockingError, got %v", err)
+	}
+}
+
+func TestVPNOnPrem_EntersThroughVirtualPrivateGateway(t *testing.T) {
+	client := newMockAWSClient()
+	client.vgws["vgw-1"] = &domain.VirtualPrivateGatewayData{ID: "vgw-1", VPCID: "vpc-1"}
+	client.vpnConnections["vpn-down"] = &domain.VPNConnectionData{ID: "vpn-down", VGWID: "vgw-1", State: "available"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	source := NewVPNOnPrem(&domain.VPNOnPremData{SourceIP: "192.168.1.10", VGWID: "vgw-1"}, "111111111111")
+	dest := domain.RoutingTarget{IP: "10.0.1.10", Port: 443, Protocol: "tcp"}
+
+	_, err := source.GetNextHops(dest, analyzerCtx)
+	var blockingErr *domain.BlockingError
+	if !errors.As(err, &blockingErr) {
+		t.Fatalf("expected BlockingError without a tunnel up, got %v", err)
+	}
+
+	client.vpnConnections["vpn-up"] = &domain.VPNConnectionData{ID: "vpn-up", VGWID: "vgw-1", State: "available", HasUpTunnel: true}
+	hops, err := source.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	if _, ok := hops[0].(*VirtualPrivateGatewayIngress); !ok {
+		t.Errorf("expected VirtualPrivateGatewayIngress, got %T", hops[0])
+	}
+}
@@ -19,7 +19,7 @@ func NewGWLB(data *domain.GWLBData, accountID string) *GWLB {
 }
 
 func (gwlb *GWLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(gwlb.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(gwlb.accountID, gwlb.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (gwlb *GWLB) GetVPCID() string {
 }
 
 func (gwlb *GWLB) GetRegion() string {
-	return ""
+	return gwlb.data.Region
 }
 
 func (gwlb *GWLB) GetSubnetID() string {
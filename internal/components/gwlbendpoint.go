@@ -37,7 +37,7 @@ func (ge *GWLBEndpoint) GetNextHops(dest domain.RoutingTarget, analyzerCtx domai
 		return []domain.Component{NewIPTarget(&domain.IPTargetData{IP: dest.IP, Port: dest.Port}, ge.accountID)}, nil
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(ge.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(ge.accountID, ge.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -59,17 +59,8 @@ func (ge *GWLBEndpoint) GetNextHops(dest domain.RoutingTarget, analyzerCtx domai
 		}
 		subnet := NewSubnet(subnetData, ge.accountID)
 
-		var terminal domain.Component = subnet
-		for i := len(ge.data.SecurityGroups) - 1; i >= 0; i-- {
-			sgData, err := client.GetSecurityGroup(ctx, ge.data.SecurityGroups[i])
-			if err != nil {
-				terminal = nil
-				break
-			}
-			terminal = NewSecurityGroupWithNext(sgData, ge.accountID, terminal)
-		}
-
-		if terminal != nil {
+		terminal, err := securityGroupFilter(ctx, client, ge.data.SecurityGroups, ge.accountID, subnet)
+		if err == nil {
 			components = append(components, terminal)
 		}
 	}
@@ -105,7 +96,7 @@ func (ge *GWLBEndpoint) GetVPCID() string {
 }
 
 func (ge *GWLBEndpoint) GetRegion() string {
-	return ""
+	return ge.data.Region
 }
 
 func (ge *GWLBEndpoint) GetSubnetID() string {
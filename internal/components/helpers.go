@@ -1,6 +1,7 @@
 package components
 
 import (
+	"fmt"
 	"net"
 	"sort"
 	"strings"
@@ -20,6 +21,41 @@ func IPMatchesCIDR(ip, cidr string) bool {
 	return network.Contains(parsedIP)
 }
 
+// vpcContainsIP reports whether ip falls in the VPC's IPv4 CIDR or one of its
+// IPv6 CIDRs.
+func vpcContainsIP(vpc *domain.VPCData, ip string) bool {
+	for _, cidr := range vpcCIDRBlocks(vpc) {
+		if IPMatchesCIDR(ip, cidr) {
+			return true
+		}
+	}
+	return false
+}
+
+// vpcCIDRBlocks returns every CIDR of vpc: its primary and secondary IPv4 CIDRs
+// and its IPv6 CIDRs.
+func vpcCIDRBlocks(vpc *domain.VPCData) []string {
+	var cidrs []string
+	if vpc.CIDRBlock != "" {
+		cidrs = append(cidrs, vpc.CIDRBlock)
+	}
+	cidrs = append(cidrs, vpc.SecondaryCIDRBlocks...)
+	return append(cidrs, vpc.IPv6CIDRBlocks...)
+}
+
+// SameCIDR reports whether cidr1 and cidr2 are the same network, however they
+// are written.
+func SameCIDR(cidr1, cidr2 string) bool {
+	_, net1, err1 := net.ParseCIDR(cidr1)
+	_, net2, err2 := net.ParseCIDR(cidr2)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	ones1, bits1 := net1.Mask.Size()
+	ones2, bits2 := net2.Mask.Size()
+	return net1.IP.Equal(net2.IP) && ones1 == ones2 && bits1 == bits2
+}
+
 func CIDROverlaps(cidr1, cidr2 string) bool {
 	_, net1, err1 := net.ParseCIDR(cidr1)
 	_, net2, err2 := net.ParseCIDR(cidr2)
@@ -48,13 +84,60 @@ func protocolMatches(ruleProtocol, destProtocol string) bool {
 }
 
 func portInRange(port, fromPort, toPort int) bool {
-	if fromPort == 0 && toPort == 0 {
+	if isAnyPort(fromPort, toPort) {
 		return true
 	}
-	if fromPort == -1 && toPort == -1 {
+	return port >= fromPort && port <= toPort
+}
+
+func isAnyPort(fromPort, toPort int) bool {
+	return (fromPort == 0 && toPort == 0) || (fromPort == -1 && toPort == -1)
+}
+
+func flowPortsInRange(target domain.RoutingTarget, fromPort, toPort int) bool {
+	low, high := target.PortRange()
+	return portInRange(low, fromPort, toPort) && portInRange(high, fromPort, toPort)
+}
+
+func flowPortsOverlap(target domain.RoutingTarget, fromPort, toPort int) bool {
+	if isAnyPort(fromPort, toPort) {
 		return true
 	}
-	return port >= fromPort && port <= toPort
+	low, high := target.PortRange()
+	return low <= toPort && high >= fromPort
+}
+
+func formatPorts(target domain.RoutingTarget) string {
+	low, high := target.PortRange()
+	if low == high {
+		return fmt.Sprintf("%d", low)
+	}
+	return fmt.Sprintf("%d-%d", low, high)
+}
+
+// inboundSourceIP returns the address inbound rules are matched against.
+// It is empty when the flow does not say where it came from.
+func inboundSourceIP(target domain.RoutingTarget) string {
+	return target.SourceIP
+}
+
+// unknownSourceError reports that inbound rules could not be evaluated
+// because the flow carries no source address. It is not a BlockingError,
+// so the verdict is left undecided.
+func unknownSourceError(componentID string) error {
+	return fmt.Errorf("%s: source address unknown, cannot evaluate inbound rules", componentID)
+}
+
+// interfaceAddresses returns every address eni sends from: its primary and
+// secondary private IPv4 addresses and its IPv6 addresses.
+func interfaceAddresses(eni *domain.ENIData) []string {
+	addresses := append([]string{eni.PrivateIP}, eni.PrivateIPs...)
+	return append(addresses, eni.IPv6Addresses...)
+}
+
+func isIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
 }
 
 func isExternalIP(ip string) bool {
@@ -114,3 +197,29 @@ func normalizeProtocol(p string) string {
 		return strings.ToLower(p)
 	}
 }
+
+func stateKey(c domain.Component) string {
+	if c == nil {
+		return ""
+	}
+	if provider, ok := c.(domain.StateKeyProvider); ok {
+		return provider.GetStateKey()
+	}
+	return c.GetID()
+}
+
+// regionOf returns the region c reports, or "" for the default region when c
+// does not report one.
+func regionOf(c domain.Component) string {
+	if mp, ok := c.(domain.MetadataProvider); ok {
+		return mp.GetRegion()
+	}
+	return ""
+}
+
+func vpcOf(c domain.Component) string {
+	if mp, ok := c.(domain.MetadataProvider); ok {
+		return mp.GetVPCID()
+	}
+	return ""
+}
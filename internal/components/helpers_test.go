@@ -122,3 +122,15 @@ func TestPortInRange(t *testing.T) {
 		})
 	}
 }
+
+func TestVPCContainsIP_SecondaryCIDRs(t *testing.T) {
+	vpc := &domain.VPCData{CIDRBlock: "10.0.0.0/16", SecondaryCIDRBlocks: []string{"100.64.0.0/16"}, IPv6CIDRBlocks: []string{"2600:1f18::/56"}}
+	for _, ip := range []string{"10.0.1.1", "100.64.3.3", "2600:1f18::10"} {
+		if !vpcContainsIP(vpc, ip) {
+			t.Errorf("expected %s to be in the VPC", ip)
+		}
+	}
+	if vpcContainsIP(vpc, "100.65.0.1") {
+		t.Error("expected 100.65.0.1 to be outside the VPC")
+	}
+}
@@ -0,0 +1,283 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+type ENIIngress struct {
+	eni       *domain.ENIData
+	accountID string
+}
+
+func NewENIIngress(eni *domain.ENIData, accountID string) *ENIIngress {
+	return &ENIIngress{
+		eni:       eni,
+		accountID: accountID,
+	}
+}
+
+func (e *ENIIngress) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(e.accountID, e.eni.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	// The interface's groups are one filter: AWS combines their inbound rules,
+	// so any group allowing the flow lets it in.
+	next, err := securityGroupFilter(ctx, client, e.eni.SecurityGroups, e.accountID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Traffic that came in through the subnet boundary has already been
+	// admitted by the subnet's NACL.
+	if dest.EnteredSubnet != "" && dest.EnteredSubnet == e.eni.SubnetID {
+		if next == nil {
+			return []domain.Component{}, nil
+		}
+		return []domain.Component{next}, nil
+	}
+
+	subnetData, err := client.GetSubnet(ctx, e.eni.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	naclData, err := client.GetNACL(ctx, subnetData.NaclID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Component{NewNACLWithNext(naclData, e.accountID, next)}, nil
+}
+
+func (e *ENIIngress) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{IP: e.eni.PrivateIP}
+}
+
+func (e *ENIIngress) GetID() string {
+	if e.eni.ID == "" {
+		return fmt.Sprintf("%s:eni:%s", e.accountID, e.eni.PrivateIP)
+	}
+	return fmt.Sprintf("%s:%s", e.accountID, e.eni.ID)
+}
+
+func (e *ENIIngress) GetAccountID() string {
+	return e.accountID
+}
+
+func (e *ENIIngress) GetComponentType() string {
+	return "NetworkInterface"
+}
+
+func (e *ENIIngress) GetVPCID() string {
+	return e.eni.VPCID
+}
+
+func (e *ENIIngress) GetRegion() string {
+	return e.eni.Region
+}
+
+func (e *ENIIngress) GetSubnetID() string {
+	return e.eni.SubnetID
+}
+
+func (e *ENIIngress) GetAvailabilityZone() string {
+	return ""
+}
+
+func ingressForIP(analyzerCtx domain.AnalyzerContext, accountID, vpcID string, fallback *domain.ENIData, candidateSubnets []string) ([]domain.Component, error) {
+	if net.ParseIP(fallback.PrivateIP) == nil {
+		return nil, nil
+	}
+
+	client, err := analyzerCtx.GetAccountContext().GetClient(accountID, fallback.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	// Private addresses overlap across VPCs, so the lookup is scoped to the
+	// resource's VPC, taken from its subnet when the resource doesn't say.
+	if vpcID == "" {
+		subnetID := fallback.SubnetID
+		if subnetID == "" && len(candidateSubnets) > 0 {
+			subnetID = candidateSubnets[0]
+		}
+		if subnetID != "" {
+			subnetData, err := client.GetSubnet(ctx, subnetID)
+			if err != nil {
+				return nil, err
+			}
+			vpcID = subnetData.VPCID
+		}
+	}
+
+	eni, err := client.GetNetworkInterfaceByPrivateIP(ctx, fallback.PrivateIP, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	if eni == nil {
+		guessed := *fallback
+		if guessed.VPCID == "" {
+			guessed.VPCID = vpcID
+		}
+		if guessed.SubnetID == "" {
+			guessed.SubnetID = subnetContainingIP(ctx, client, candidateSubnets, guessed.PrivateIP)
+		}
+		eni = &guessed
+	}
+
+	ingress := NewENIIngress(eni, accountID)
+	if eni.SubnetID == "" {
+		return nil, &domain.BlockingError{
+			ComponentID: ingress.GetID(),
+			Reason:      fmt.Sprintf("cannot determine the subnet of %s", eni.PrivateIP),
+		}
+	}
+
+	return []domain.Component{ingress}, nil
+}
+
+func subnetContainingIP(ctx context.Context, client domain.AWSClient, subnetIDs []string, ip string) string {
+	for _, subnetID := range subnetIDs {
+		subnetData, err := client.GetSubnet(ctx, subnetID)
+		if err != nil {
+			continue
+		}
+		if IPMatchesCIDR(ip, subnetData.CIDRBlock) || IPMatchesCIDR(ip, subnetData.IPv6CIDRBlock) {
+			return subnetID
+		}
+	}
+	return ""
+}
+
+// SubnetIngress is traffic entering a subnet from outside its VPC, over a
+// peering connection or a transit gateway attachment. The subnet's NACL
+// filters it on the way in and the subnet's own route table, rather than the
+// VPC's main one, decides where it goes next.
+type SubnetIngress struct {
+	data      *domain.SubnetData
+	accountID string
+}
+
+func NewSubnetIngress(data *domain.SubnetData, accountID string) *SubnetIngress {
+	return &SubnetIngress{
+		data:      data,
+		accountID: accountID,
+	}
+}
+
+func (s *SubnetIngress) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(s.accountID, s.data.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	naclData, err := client.GetNACL(ctx, s.data.NaclID)
+	if err != nil {
+		return nil, err
+	}
+	if err := NewNACL(naclData, s.accountID).EvaluateInbound(dest, analyzerCtx); err != nil {
+		return nil, err
+	}
+
+	rtData, err := client.GetRouteTable(ctx, s.data.RouteTableID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Component{NewRouteTable(rtData, s.accountID)}, nil
+}
+
+// Translate records that the subnet's NACL has admitted the flow, so the
+// interface it is delivered to does not evaluate the NACL again.
+func (s *SubnetIngress) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	target.EnteredSubnet = s.data.ID
+	return target
+}
+
+func (s *SubnetIngress) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (s *SubnetIngress) GetID() string {
+	return fmt.Sprintf("%s:%s:inbound", s.accountID, s.data.ID)
+}
+
+func (s *SubnetIngress) GetAccountID() string {
+	return s.accountID
+}
+
+func (s *SubnetIngress) GetComponentType() string {
+	return "Subnet"
+}
+
+func (s *SubnetIngress) GetVPCID() string {
+	return s.data.VPCID
+}
+
+func (s *SubnetIngress) GetRegion() string {
+	return s.data.Region
+}
+
+func (s *SubnetIngress) GetSubnetID() string {
+	return s.data.ID
+}
+
+func (s *SubnetIngress) GetAvailabilityZone() string {
+	return s.data.AvailabilityZone
+}
+
+// vpcIngress returns where traffic for ip continues once it has crossed into
+// vpcID: the subnet containing ip, or the VPC's main route table when no
+// subnet does.
+func vpcIngress(analyzerCtx domain.AnalyzerContext, client domain.AWSClient, accountID, vpcID, ip string) ([]domain.Component, error) {
+	ctx := analyzerCtx.Context()
+
+	subnetData, err := client.GetSubnetByIP(ctx, ip, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	if subnetData != nil {
+		return []domain.Component{NewSubnetIngress(subnetData, accountID)}, nil
+	}
+
+	vpcData, err := client.GetVPC(ctx, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	rtData, err := client.GetRouteTable(ctx, vpcData.MainRouteTableID)
+	if err != nil {
+		return nil, err
+	}
+	return []domain.Component{NewRouteTable(rtData, accountID)}, nil
+}
+
+// gatewayIngressTable returns the route table edge-associated with gatewayID
+// when it steers traffic for ip to a target other than the VPC itself, such as
+// a firewall or a Gateway Load Balancer endpoint. It returns nil when the
+// gateway has no such table or the table delivers ip locally.
+func gatewayIngressTable(analyzerCtx domain.AnalyzerContext, client domain.AWSClient, gatewayID, accountID, ip string) ([]domain.Component, error) {
+	rtData, err := client.GetGatewayRouteTable(analyzerCtx.Context(), gatewayID)
+	if err != nil || rtData == nil {
+		return nil, err
+	}
+	ingressTable := NewRouteTable(rtData, accountID)
+	route, err := ingressTable.matchRoute(ip, analyzerCtx)
+	if err != nil {
+		return nil, err
+	}
+	if route != nil && route.TargetType != "local" {
+		return []domain.Component{ingressTable}, nil
+	}
+	return nil, nil
+}
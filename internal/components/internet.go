@@ -0,0 +1,214 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// InternetSource is traffic from an address on the internet. It enters the
+// destination's VPC through the VPC's internet gateway, addressed to the public
+// IP mapped to the destination's network interface.
+type InternetSource struct {
+	ip        string
+	accountID string
+	region    string
+	vpcID     string
+}
+
+// NewInternetSource returns a source at ip. accountID, region and vpcID locate
+// the destination, where the interface and gateway are looked up. Private
+// addresses overlap across VPCs, so the interface is only looked up in vpcID
+// when it is set.
+func NewInternetSource(ip, accountID, region, vpcID string) *InternetSource {
+	return &InternetSource{
+		ip:        ip,
+		accountID: accountID,
+		region:    region,
+		vpcID:     vpcID,
+	}
+}
+
+func (i *InternetSource) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(i.accountID, i.region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	eni, err := client.GetNetworkInterfaceByPrivateIP(ctx, dest.IP, i.vpcID)
+	if err != nil {
+		return nil, err
+	}
+	if eni == nil {
+		return nil, &domain.BlockingError{
+			ComponentID: i.GetID(),
+			Reason:      fmt.Sprintf("no network interface has address %s", dest.IP),
+		}
+	}
+	if dest.Reply {
+		if hops, err := i.replyHops(eni, client, analyzerCtx); err != nil || hops != nil {
+			return hops, err
+		}
+	}
+	if !strings.Contains(dest.IP, ":") && eni.PublicIP == "" {
+		return nil, &domain.BlockingError{
+			ComponentID: i.GetID(),
+			Reason:      fmt.Sprintf("%s has no public IPv4 address", eni.ID),
+		}
+	}
+
+	igwData, err := client.GetInternetGatewayByVPC(ctx, eni.VPCID)
+	if err != nil {
+		return nil, err
+	}
+	if igwData == nil {
+		return nil, &domain.BlockingError{
+			ComponentID: i.GetID(),
+			Reason:      fmt.Sprintf("vpc %s has no internet gateway attached", eni.VPCID),
+		}
+	}
+
+	return []domain.Component{NewInternetGatewayIngress(igwData, eni, i.accountID)}, nil
+}
+
+// replyHops returns where replies to traffic eni sent to this address come
+// back in when the subnet routes the address through a gateway that only lets
+// replies in, such as an egress-only internet gateway or a NAT gateway. It
+// returns nil when replies arrive through the internet gateway like any other
+// inbound traffic.
+func (i *InternetSource) replyHops(eni *domain.ENIData, client domain.AWSClient, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	ctx := analyzerCtx.Context()
+
+	subnetData, err := client.GetSubnet(ctx, eni.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	rtData, err := client.GetRouteTable(ctx, subnetData.RouteTableID)
+	if err != nil {
+		return nil, err
+	}
+	route, err := NewRouteTable(rtData, i.accountID).matchRoute(i.ip, analyzerCtx)
+	if err != nil {
+		return nil, err
+	}
+	if route == nil {
+		return nil, nil
+	}
+
+	switch route.TargetType {
+	case "egress-only-internet-gateway":
+		eigwData, err := client.GetEgressOnlyInternetGateway(ctx, route.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Component{NewEgressOnlyInternetGatewayIngress(eigwData, eni, i.accountID)}, nil
+
+	case "nat-gateway":
+		natData, err := client.GetNATGateway(ctx, route.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Component{NewNATGateway(natData, i.accountID)}, nil
+	}
+	return nil, nil
+}
+
+func (i *InternetSource) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{IP: i.ip}
+}
+
+func (i *InternetSource) GetID() string {
+	return fmt.Sprintf("%s:internet:%s", i.accountID, i.ip)
+}
+
+func (i *InternetSource) GetAccountID() string {
+	return i.accountID
+}
+
+func (i *InternetSource) GetComponentType() string {
+	return "Internet"
+}
+
+// SourceFor returns the component that traffic from source to destination
+// starts at. An external address has no network of its own, so its traffic
+// enters through a gateway of the destination's VPC; every other source is
+// returned unchanged. The same holds for the return leg, with the roles of
+// source and destination swapped.
+func SourceFor(source, destination domain.Component) domain.Component {
+	external, ok := source.(*IPTarget)
+	if !ok || !isExternalIP(external.data.IP) || destination.GetAccountID() == "" {
+		return source
+	}
+	return NewInternetSource(external.data.IP, destination.GetAccountID(), regionOf(destination), vpcOf(destination))
+}
+
+// InternetGatewayIngress is an internet gateway delivering inbound traffic to
+// the network interface its public address maps to. When a route table is
+// edge-associated with the gateway and routes the address to an appliance,
+// traffic is handed to that route table instead.
+type InternetGatewayIngress struct {
+	data      *domain.InternetGatewayData
+	eni       *domain.ENIData
+	accountID string
+}
+
+func NewInternetGatewayIngress(data *domain.InternetGatewayData, eni *domain.ENIData, accountID string) *InternetGatewayIngress {
+	return &InternetGatewayIngress{
+		data:      data,
+		eni:       eni,
+		accountID: accountID,
+	}
+}
+
+func (igw *InternetGatewayIngress) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(igw.accountID, igw.data.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressTable, err := gatewayIngressTable(analyzerCtx, client, igw.data.ID, igw.accountID, dest.IP)
+	if err != nil || ingressTable != nil {
+		return ingressTable, err
+	}
+
+	// Address the interface as the flow does, which may be one of its IPv6
+	// addresses rather than its primary private IP.
+	addressed := *igw.eni
+	addressed.PrivateIP = dest.IP
+	return []domain.Component{NewNetworkInterfaceFromData(&addressed, igw.accountID)}, nil
+}
+
+func (igw *InternetGatewayIngress) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (igw *InternetGatewayIngress) GetID() string {
+	return fmt.Sprintf("%s:%s", igw.accountID, igw.data.ID)
+}
+
+func (igw *InternetGatewayIngress) GetAccountID() string {
+	return igw.accountID
+}
+
+func (igw *InternetGatewayIngress) GetComponentType() string {
+	return "InternetGateway"
+}
+
+func (igw *InternetGatewayIngress) GetVPCID() string {
+	return igw.data.VPCID
+}
+
+func (igw *InternetGatewayIngress) GetRegion() string {
+	return igw.data.Region
+}
+
+func (igw *InternetGatewayIngress) GetSubnetID() string {
+	return ""
+}
+
+func (igw *InternetGatewayIngress) GetAvailabilityZone() string {
+	return ""
+}
@@ -0,0 +1,183 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+func TestInternetSource_EntersThroughInternetGateway(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-web"] = &domain.ENIData{ID: "eni-web", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.3", VPCID: "vpc-1", SubnetID: "subnet-1"}
+	client.igws["igw-1"] = &domain.InternetGatewayData{ID: "igw-1", VPCID: "vpc-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	source := NewInternetSource("0.0.0.0", "111111111111", "", "")
+	hops, err := source.GetNextHops(domain.RoutingTarget{IP: "10.0.1.10", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetComponentType() != "InternetGateway" {
+		t.Fatalf("expected the internet gateway, got %v", hops)
+	}
+
+	next, err := hops[0].GetNextHops(domain.RoutingTarget{IP: "10.0.1.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next) != 1 || next[0].GetID() != "111111111111:eni-web" {
+		t.Errorf("expected the gateway to deliver to eni-web, got %v", next)
+	}
+}
+
+func TestInternetSource_Blocks(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-private"] = &domain.ENIData{ID: "eni-private", PrivateIP: "10.0.1.20", VPCID: "vpc-1"}
+	client.networkENIs["eni-isolated"] = &domain.ENIData{ID: "eni-isolated", PrivateIP: "10.0.2.20", PublicIP: "54.1.2.4", VPCID: "vpc-2"}
+	client.igws["igw-1"] = &domain.InternetGatewayData{ID: "igw-1", VPCID: "vpc-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	source := NewInternetSource("0.0.0.0", "111111111111", "", "")
+	for _, ip := range []string{"10.0.1.20", "10.0.2.20", "10.0.3.30"} {
+		_, err := source.GetNextHops(domain.RoutingTarget{IP: ip}, analyzerCtx)
+		if !domain.IsBlocking(err) {
+			t.Errorf("%s: expected a blocking error, got %v", ip, err)
+		}
+	}
+}
+
+func TestInternetSource_RepliesReturnThroughEgressOnlyGateway(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-app"] = &domain.ENIData{ID: "eni-app", PrivateIP: "10.0.2.10", VPCID: "vpc-1", SubnetID: "subnet-2", IPv6Addresses: []string{"2600:1f18::20"}}
+	client.subnets["subnet-2"] = &domain.SubnetData{ID: "subnet-2", VPCID: "vpc-1", RouteTableID: "rtb-2"}
+	client.routeTables["rtb-2"] = &domain.RouteTableData{
+		ID:    "rtb-2",
+		VPCID: "vpc-1",
+		Routes: []domain.Route{
+			{DestinationIPv6CIDR: "::/0", TargetType: "egress-only-internet-gateway", TargetID: "eigw-1"},
+		},
+	}
+	client.eigws["eigw-1"] = &domain.EgressOnlyInternetGatewayData{ID: "eigw-1", VPCID: "vpc-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	source := NewInternetSource("2600:1f18:ffff::1", "111111111111", "", "")
+	reply := domain.RoutingTarget{IP: "2600:1f18::20", Port: 443, Protocol: "tcp", Reply: true}
+	hops, err := source.GetNextHops(reply, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetComponentType() != "EgressOnlyInternetGateway" {
+		t.Fatalf("expected the egress-only gateway, got %v", hops)
+	}
+
+	next, err := hops[0].GetNextHops(reply, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next) != 1 || next[0].GetID() != "111111111111:eni-app" {
+		t.Errorf("expected the gateway to deliver to eni-app, got %v", next)
+	}
+
+	reply.Reply = false
+	if _, err := hops[0].GetNextHops(reply, analyzerCtx); !domain.IsBlocking(err) {
+		t.Errorf("expected connections from the internet to be blocked, got %v", err)
+	}
+	if _, err := source.GetNextHops(reply, analyzerCtx); !domain.IsBlocking(err) {
+		t.Errorf("expected a VPC without an internet gateway to be unreachable, got %v", err)
+	}
+}
+
+func TestSourceFor_ExternalAddressEntersThroughInternet(t *testing.T) {
+	dest := NewEC2Instance(&domain.EC2InstanceData{ID: "i-bastion", PrivateIP: "10.0.1.10"}, "111111111111")
+
+	office := NewIPTarget(&domain.IPTargetData{IP: "203.0.113.7"}, "")
+	if source := SourceFor(office, dest); source.GetID() != "111111111111:internet:203.0.113.7" {
+		t.Errorf("expected an internet source in the destination's account, got %s", source.GetID())
+	}
+
+	private := NewIPTarget(&domain.IPTargetData{IP: "10.0.2.10"}, "")
+	if source := SourceFor(private, dest); source != private {
+		t.Errorf("expected a private address to be left alone, got %s", source.GetID())
+	}
+}
+
+func TestSourceFor_EntersInDestinationRegion(t *testing.T) {
+	dest := NewEC2Instance(&domain.EC2InstanceData{ID: "i-dr", PrivateIP: "10.1.1.10", Region: "eu-west-1"}, "111111111111")
+
+	source, ok := SourceFor(NewIPTarget(&domain.IPTargetData{IP: "203.0.113.7"}, ""), dest).(*InternetSource)
+	if !ok {
+		t.Fatal("expected an internet source")
+	}
+	if source.region != "eu-west-1" {
+		t.Errorf("expected the source to look up the destination in eu-west-1, got %q", source.region)
+	}
+}
+
+func TestInternetSource_LooksUpInterfaceInDestinationVPC(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-other"] = &domain.ENIData{ID: "eni-other", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.9", VPCID: "vpc-other"}
+	client.networkENIs["eni-web"] = &domain.ENIData{ID: "eni-web", PrivateIP: "10.0.1.10", PublicIP: "54.1.2.3", VPCID: "vpc-1"}
+	client.igws["igw-other"] = &domain.InternetGatewayData{ID: "igw-other", VPCID: "vpc-other"}
+	client.igws["igw-1"] = &domain.InternetGatewayData{ID: "igw-1", VPCID: "vpc-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	dest := NewEC2Instance(&domain.EC2InstanceData{ID: "i-web", PrivateIP: "10.0.1.10", VPCID: "vpc-1"}, "111111111111")
+	source := SourceFor(NewIPTarget(&domain.IPTargetData{IP: "203.0.113.7"}, ""), dest)
+
+	hops, err := source.GetNextHops(domain.RoutingTarget{IP: "10.0.1.10", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetID() != "111111111111:igw-1" {
+		t.Fatalf("expected the destination VPC's internet gateway, got %v", hops)
+	}
+	next, err := hops[0].GetNextHops(domain.RoutingTarget{IP: "10.0.1.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next) != 1 || next[0].GetID() != "111111111111:eni-web" {
+		t.Errorf("expected delivery to eni-web, got %v", next)
+	}
+}
+
+func TestInternetGatewayIngress_FollowsEdgeRouteTable(t *testing.T) {
+	client := newMockAWSClient()
+	client.gatewayRouteTables["igw-1"] = &domain.RouteTableData{
+		ID:    "rtb-edge",
+		VPCID: "vpc-1",
+		Routes: []domain.Route{
+			{DestinationCIDR: "10.0.0.0/16", PrefixLength: 16, TargetType: "local", TargetID: "local"},
+			{DestinationCIDR: "10.0.1.0/24", PrefixLength: 24, TargetType: "network-interface", TargetID: "eni-firewall"},
+		},
+	}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	igw := &domain.InternetGatewayData{ID: "igw-1", VPCID: "vpc-1"}
+	inspected := NewInternetGatewayIngress(igw, &domain.ENIData{ID: "eni-web", PrivateIP: "10.0.1.10"}, "111111111111")
+	hops, err := inspected.GetNextHops(domain.RoutingTarget{IP: "10.0.1.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetID() != "111111111111:rtb-edge" {
+		t.Fatalf("expected the edge route table, got %v", hops)
+	}
+
+	direct := NewInternetGatewayIngress(igw, &domain.ENIData{ID: "eni-db", PrivateIP: "10.0.2.10"}, "111111111111")
+	hops, err = direct.GetNextHops(domain.RoutingTarget{IP: "10.0.2.10"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetID() != "111111111111:eni-db" {
+		t.Errorf("expected delivery to eni-db, got %v", hops)
+	}
+}
@@ -37,7 +37,7 @@ func (igw *InternetGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx d
 			Reason:      "internet gateway requires analyzer context for VPC validation",
 		}
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(igw.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(igw.accountID, igw.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +52,7 @@ func (igw *InternetGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx d
 	if err != nil {
 		return nil, err
 	}
-	if vpc.CIDRBlock != "" && !IPMatchesCIDR(dest.IP, vpc.CIDRBlock) && (vpc.IPv6CIDRBlock == "" || !IPMatchesCIDR(dest.IP, vpc.IPv6CIDRBlock)) && dest.Direction == "inbound" {
+	if vpc.CIDRBlock != "" && !vpcContainsIP(vpc, dest.IP) && dest.Direction == "inbound" {
 		return nil, &domain.BlockingError{
 			ComponentID: igw.GetID(),
 			Reason:      fmt.Sprintf("destination %s not within attached VPC %s", dest.IP, vpc.ID),
@@ -86,7 +86,7 @@ func (igw *InternetGateway) GetVPCID() string {
 }
 
 func (igw *InternetGateway) GetRegion() string {
-	return ""
+	return igw.data.Region
 }
 
 func (igw *InternetGateway) GetSubnetID() string {
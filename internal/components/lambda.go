@@ -28,7 +28,7 @@ func (l *LambdaFunction) GetNextHops(dest domain.RoutingTarget, analyzerCtx doma
 		}
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(l.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(l.accountID, l.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -47,22 +47,31 @@ func (l *LambdaFunction) GetNextHops(dest domain.RoutingTarget, analyzerCtx doma
 		return nil, err
 	}
 
-	var next domain.Component = NewSubnet(subnetData, l.accountID)
-
-	for i := len(l.data.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, l.data.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		next = NewSecurityGroupWithNext(sgData, l.accountID, next)
+	next, err := securityGroupFilter(ctx, client, l.data.SecurityGroups, l.accountID, NewSubnet(subnetData, l.accountID))
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{next}, nil
 }
 
+func (l *LambdaFunction) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return ingressForIP(analyzerCtx, l.accountID, l.data.VPCID, &domain.ENIData{
+		PrivateIP:      l.GetRoutingTarget().IP,
+		SecurityGroups: l.data.SecurityGroups,
+		Region:         l.data.Region,
+	}, l.data.SubnetIDs)
+}
+
 func (l *LambdaFunction) GetRoutingTarget() domain.RoutingTarget {
 	ip := ""
-	if len(l.data.SubnetCIDRs) > 0 {
+	for _, eniIP := range l.data.ENIIPs {
+		if !strings.Contains(eniIP, ":") {
+			ip = eniIP
+			break
+		}
+	}
+	if ip == "" && len(l.data.SubnetCIDRs) > 0 {
 		ip = getRepresentativeIP(l.data.SubnetCIDRs[0])
 	}
 	return domain.RoutingTarget{
@@ -72,6 +81,19 @@ func (l *LambdaFunction) GetRoutingTarget() domain.RoutingTarget {
 	}
 }
 
+// GetIPv6Addresses returns the IPv6 addresses of the function's interfaces.
+func (l *LambdaFunction) GetIPv6Addresses() []string {
+	var addresses []string
+	for _, ip := range l.data.ENIIPs {
+		if strings.Contains(ip, ":") {
+			addresses = append(addresses, ip)
+		}
+	}
+	return addresses
+}
+
+// getRepresentativeIP returns an address inside cidr to stand for a resource
+// whose exact address is not known.
 func getRepresentativeIP(cidr string) string {
 	parts := strings.Split(cidr, "/")
 	if len(parts) != 2 {
@@ -108,7 +130,7 @@ func (l *LambdaFunction) GetVPCID() string {
 }
 
 func (l *LambdaFunction) GetRegion() string {
-	return ""
+	return l.data.Region
 }
 
 func (l *LambdaFunction) GetSubnetID() string {
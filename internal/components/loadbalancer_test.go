@@ -2,6 +2,8 @@ package components
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -358,6 +360,79 @@ func TestNLB_GetNextHops(t *testing.T) {
 	}
 }
 
+func TestNLB_GetNextHops_ClientIPPreservationOff(t *testing.T) {
+	mockClient := newMockAWSClient()
+	mockClient.targetGroups["tg-preserved"] = &domain.TargetGroupData{ARN: "tg-preserved", TargetType: "instance", PreserveClientIP: true}
+	mockClient.targetGroups["tg-proxied"] = &domain.TargetGroupData{ARN: "tg-proxied", TargetType: "ip", Targets: []domain.TargetData{
+		{ID: "10.0.2.10", HealthStatus: "healthy", AvailabilityZone: "us-east-1b"},
+		{ID: "10.0.1.10", HealthStatus: "healthy", AvailabilityZone: "us-east-1a"},
+	}}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+
+	nlb := NewNLB(&domain.NLBData{
+		ARN:             "nlb-1",
+		TargetGroupARNs: []string{"tg-preserved", "tg-proxied"},
+		FrontendIPs:     []string{"10.0.1.50", "10.0.2.50"},
+		FrontendIPsByAZ: map[string]string{"us-east-1a": "10.0.1.50", "us-east-1b": "10.0.2.50"},
+	}, "123456789012")
+
+	hops, err := nlb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 80}, newMockAnalyzerContext(accountCtx))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 3 {
+		t.Fatalf("expected the preserving group and one proxying group per zone, got %d", len(hops))
+	}
+
+	client := domain.RoutingTarget{IP: "10.0.1.100", Port: 80, SourceIP: "203.0.113.7"}
+	if got := hops[0].(*TargetGroup).Translate(client).SourceIP; got != "203.0.113.7" {
+		t.Errorf("expected the preserving group to keep the client address, got %s", got)
+	}
+	for i, want := range []struct{ proxy, target string }{{"10.0.1.50", "10.0.1.10"}, {"10.0.2.50", "10.0.2.10"}} {
+		tg := hops[i+1].(*TargetGroup)
+		if got := tg.Translate(client).SourceIP; got != want.proxy {
+			t.Errorf("group %d: expected the NLB address %s, got %s", i, want.proxy, got)
+		}
+		if len(tg.data.Targets) != 1 || tg.data.Targets[0].ID != want.target {
+			t.Errorf("group %d: expected only target %s, got %v", i, want.target, tg.data.Targets)
+		}
+	}
+}
+
+func TestNLB_GetNextHops_CrossZoneProxiesFromEveryNode(t *testing.T) {
+	mockClient := newMockAWSClient()
+	mockClient.targetGroups["tg-proxied"] = &domain.TargetGroupData{ARN: "tg-proxied", TargetType: "ip", Targets: []domain.TargetData{
+		{ID: "10.0.1.10", HealthStatus: "healthy", AvailabilityZone: "us-east-1a"},
+	}}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+
+	nlb := NewNLB(&domain.NLBData{
+		ARN:             "nlb-1",
+		TargetGroupARNs: []string{"tg-proxied"},
+		FrontendIPs:     []string{"10.0.1.50", "10.0.2.50"},
+		FrontendIPsByAZ: map[string]string{"us-east-1a": "10.0.1.50", "us-east-1b": "10.0.2.50"},
+		CrossZone:       true,
+	}, "123456789012")
+
+	hops, err := nlb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 80}, newMockAnalyzerContext(accountCtx))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("expected one group per NLB node, got %d", len(hops))
+	}
+	client := domain.RoutingTarget{IP: "10.0.1.100", Port: 80, SourceIP: "203.0.113.7"}
+	for i, want := range []string{"10.0.1.50", "10.0.2.50"} {
+		if got := hops[i].(*TargetGroup).Translate(client).SourceIP; got != want {
+			t.Errorf("group %d: expected the NLB address %s, got %s", i, want, got)
+		}
+	}
+}
+
 func TestNLB_GetNextHops_WithSecurityGroups(t *testing.T) {
 	mockClient := newMockAWSClient()
 	allowAllRule := domain.SecurityGroupRule{Protocol: "-1", CIDRBlocks: []string{"0.0.0.0/0"}}
@@ -529,8 +604,12 @@ func TestALB_GetNextHops_SecurityGroupsEvaluatedThenTargetGroups(t *testing.T) {
 	if len(hops) != 1 {
 		t.Errorf("expected SG-wrapped TG chain head, got %d", len(hops))
 	}
-	if _, ok := hops[0].(*SecurityGroup); !ok {
-		t.Errorf("expected SecurityGroup head, got %T", hops[0])
+	set, ok := hops[0].(*SecurityGroupSet)
+	if !ok {
+		t.Fatalf("expected the combined security groups as head, got %T", hops[0])
+	}
+	if _, ok := set.next.(*TargetGroup); !ok {
+		t.Errorf("expected TargetGroup after the security groups, got %T", set.next)
 	}
 }
 
@@ -708,6 +787,142 @@ func TestCLB_GetNextHops_NoInstances(t *testing.T) {
 	}
 }
 
+func TestALB_GetNextHops_TargetsSeeTheALB(t *testing.T) {
+	mockClient := newMockAWSClient()
+	allowAllRule := domain.SecurityGroupRule{Protocol: "-1", CIDRBlocks: []string{"0.0.0.0/0"}}
+	mockClient.securityGroups["sg-alb"] = &domain.SecurityGroupData{
+		ID:            "sg-alb",
+		VPCID:         "vpc-123",
+		OutboundRules: []domain.SecurityGroupRule{allowAllRule},
+	}
+	mockClient.securityGroups["sg-web"] = &domain.SecurityGroupData{
+		ID:    "sg-web",
+		VPCID: "vpc-123",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 80, ToPort: 80, ReferencedSecurityGroups: []string{"sg-alb"}},
+		},
+	}
+	mockClient.enisBySG["sg-alb"] = []domain.ENIData{
+		{ID: "eni-alb-a", PrivateIP: "10.0.0.10", SubnetID: "subnet-public-a", VPCID: "vpc-123"},
+		{ID: "eni-alb-b", PrivateIP: "10.0.0.20", SubnetID: "subnet-public-b", VPCID: "vpc-123"},
+	}
+	mockClient.subnets["subnet-web"] = &domain.SubnetData{ID: "subnet-web", VPCID: "vpc-123", CIDRBlock: "10.0.1.0/24", NaclID: "nacl-web"}
+	mockClient.nacls["nacl-web"] = &domain.NACLData{
+		ID:           "nacl-web",
+		VPCID:        "vpc-123",
+		InboundRules: []domain.NACLRule{{RuleNumber: 100, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"}},
+	}
+	mockClient.ec2Instances["i-web"] = &domain.EC2InstanceData{
+		ID:             "i-web",
+		PrivateIP:      "10.0.1.100",
+		SecurityGroups: []string{"sg-web"},
+		SubnetID:       "subnet-web",
+		VPCID:          "vpc-123",
+	}
+	mockClient.targetGroups["tg-web"] = &domain.TargetGroupData{
+		ARN:        "tg-web",
+		TargetType: "instance",
+		Port:       80,
+		Targets:    []domain.TargetData{{ID: "i-web", Port: 80, HealthStatus: "healthy"}},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	alb := NewALB(&domain.ALBData{
+		ARN:             "alb-web",
+		VPCID:           "vpc-123",
+		SecurityGroups:  []string{"sg-alb"},
+		TargetGroupARNs: []string{"tg-web"},
+		FrontendIPs:     []string{"10.0.0.10", "10.0.0.20"},
+	}, "123456789012")
+
+	flow := domain.RoutingTarget{SourceIP: "203.0.113.7", IP: "10.0.1.100", Port: 80, Protocol: "tcp", Direction: "outbound"}
+	hops, err := alb.GetNextHops(flow, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("expected the target group once per ALB node, got %d", len(hops))
+	}
+
+	for _, hop := range hops {
+		sgHops, err := hop.GetNextHops(flow, analyzerCtx)
+		if err != nil {
+			t.Fatalf("expected the ALB's groups to allow the flow, got %v", err)
+		}
+		tg := sgHops[0].(*TargetGroup)
+		leg := tg.Translate(flow)
+		if leg.SourceIP != tg.proxyIP {
+			t.Errorf("expected targets to see ALB node %s, got %s", tg.proxyIP, leg.SourceIP)
+		}
+
+		instances, err := tg.GetNextHops(leg, analyzerCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ingress, err := instances[0].(*EC2Instance).GetIngressHops(analyzerCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		leg.Direction = "inbound"
+		current := ingress
+		for len(current) > 0 {
+			next, err := current[0].GetNextHops(leg, analyzerCtx)
+			if err != nil {
+				t.Fatalf("from %s: expected the instance to accept its ALB, blocked at %s: %v", leg.SourceIP, current[0].GetID(), err)
+			}
+			current = next
+		}
+	}
+}
+
+func TestCLB_GetNextHops_InstancesSeeEachNode(t *testing.T) {
+	mockClient := newMockAWSClient()
+	mockClient.ec2Instances["i-clb-1"] = &domain.EC2InstanceData{ID: "i-clb-1", PrivateIP: "10.0.1.10"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	clb := NewCLB(&domain.CLBData{
+		Name:        "web",
+		InstanceIDs: []string{"i-clb-1"},
+		FrontendIPs: []string{"10.0.0.10", "10.0.0.20"},
+	}, "123456789012")
+
+	flow := domain.RoutingTarget{SourceIP: "203.0.113.7", IP: "10.0.1.10", Port: 80, Protocol: "tcp"}
+	if got := clb.Translate(flow).SourceIP; got != "203.0.113.7" {
+		t.Errorf("expected the unsplit CLB to leave the source alone, got %s", got)
+	}
+
+	nodes, err := clb.GetNextHops(flow, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected one hop per CLB node, got %d", len(nodes))
+	}
+	for i, want := range []string{"10.0.0.10", "10.0.0.20"} {
+		node := nodes[i].(*CLB)
+		if got := node.Translate(flow).SourceIP; got != want {
+			t.Errorf("node %d: expected instances to see %s, got %s", i, want, got)
+		}
+		hops, err := node.GetNextHops(flow, analyzerCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := hops[0].(*EC2Instance); !ok {
+			t.Errorf("node %d: expected the instance, got %T", i, hops[0])
+		}
+	}
+	if nodes[0].(*CLB).GetStateKey() == nodes[1].(*CLB).GetStateKey() {
+		t.Error("expected CLB nodes to have distinct state keys")
+	}
+}
+
 func TestGWLB_GetNextHops(t *testing.T) {
 	mockClient := newMockAWSClient()
 	mockClient.targetGroups["arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg-appliance/abc123"] = &domain.TargetGroupData{
@@ -830,3 +1045,228 @@ func TestTargetGroup_GetID(t *testing.T) {
 		t.Errorf("expected %s, got %s", expected, tg.GetID())
 	}
 }
+
+func TestALB_GetNextHops_FollowsListenerOnDestinationPort(t *testing.T) {
+	mockClient := newMockAWSClient()
+	mockClient.targetGroups["tg-http"] = &domain.TargetGroupData{ARN: "tg-http", TargetType: "ip", Port: 8080}
+	mockClient.targetGroups["tg-https"] = &domain.TargetGroupData{ARN: "tg-https", TargetType: "ip", Port: 8443}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	alb := NewALB(&domain.ALBData{
+		ARN:             "alb-1",
+		TargetGroupARNs: []string{"tg-http", "tg-https"},
+		Listeners: []domain.ListenerData{
+			{ARN: "listener-http", Port: 80, Protocol: "HTTP", TargetGroupARNs: []string{"tg-http"}},
+			{ARN: "listener-https", Port: 443, Protocol: "HTTPS", TargetGroupARNs: []string{"tg-https"}},
+		},
+	}, "123456789012")
+
+	hops, err := alb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 443}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected only the HTTPS listener's target group, got %d hops", len(hops))
+	}
+	if tg, ok := hops[0].(*TargetGroup); !ok || tg.data.ARN != "tg-https" {
+		t.Errorf("expected tg-https, got %v", hops[0].GetID())
+	}
+
+	_, err = alb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 8080}, analyzerCtx)
+	var blockErr *domain.BlockingError
+	if !errors.As(err, &blockErr) {
+		t.Fatalf("expected BlockingError, got %v", err)
+	}
+	if !strings.Contains(blockErr.Reason, "no listener on port 8080") {
+		t.Errorf("unexpected reason: %s", blockErr.Reason)
+	}
+}
+
+func TestNLB_GetNextHops_FollowsListenerOnDestinationProtocol(t *testing.T) {
+	mockClient := newMockAWSClient()
+	mockClient.targetGroups["tg-tcp"] = &domain.TargetGroupData{ARN: "tg-tcp", TargetType: "ip"}
+	mockClient.targetGroups["tg-udp"] = &domain.TargetGroupData{ARN: "tg-udp", TargetType: "ip"}
+	mockClient.targetGroups["tg-dns"] = &domain.TargetGroupData{ARN: "tg-dns", TargetType: "ip"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", mockClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	nlb := NewNLB(&domain.NLBData{
+		ARN:             "nlb-1",
+		TargetGroupARNs: []string{"tg-tcp", "tg-udp", "tg-dns"},
+		Listeners: []domain.ListenerData{
+			{ARN: "listener-tcp", Port: 443, Protocol: "TCP", TargetGroupARNs: []string{"tg-tcp"}},
+			{ARN: "listener-udp", Port: 443, Protocol: "UDP", TargetGroupARNs: []string{"tg-udp"}},
+			{ARN: "listener-dns", Port: 53, Protocol: "TCP_UDP", TargetGroupARNs: []string{"tg-dns"}},
+		},
+	}, "123456789012")
+
+	tests := []struct {
+		name     string
+		dest     domain.RoutingTarget
+		wantARNs []string
+	}{
+		{"tcp on a shared port", domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}, []string{"tg-tcp"}},
+		{"udp on a shared port", domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "udp"}, []string{"tg-udp"}},
+		{"tcp on TCP_UDP", domain.RoutingTarget{IP: "10.0.1.100", Port: 53, Protocol: "tcp"}, []string{"tg-dns"}},
+		{"udp on TCP_UDP", domain.RoutingTarget{IP: "10.0.1.100", Port: 53, Protocol: "udp"}, []string{"tg-dns"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hops, err := nlb.GetNextHops(tt.dest, analyzerCtx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(hops) != len(tt.wantARNs) {
+				t.Fatalf("expected %d hops, got %d", len(tt.wantARNs), len(hops))
+			}
+			for i, want := range tt.wantARNs {
+				if tg, ok := hops[i].(*TargetGroup); !ok || tg.data.ARN != want {
+					t.Errorf("hop %d: expected %s, got %s", i, want, hops[i].GetID())
+				}
+			}
+		})
+	}
+
+	_, err := nlb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 53, Protocol: "icmp"}, analyzerCtx)
+	var blockErr *domain.BlockingError
+	if !errors.As(err, &blockErr) || !strings.Contains(blockErr.Reason, "no icmp listener on port 53") {
+		t.Errorf("expected no icmp listener to block, got %v", err)
+	}
+}
+
+func TestNLB_GetNextHops_ListenerWithoutTargetGroupBlocks(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", newMockAWSClient())
+
+	nlb := NewNLB(&domain.NLBData{
+		ARN: "nlb-1",
+		Listeners: []domain.ListenerData{
+			{ARN: "listener-tls", Port: 443, Protocol: "TLS"},
+		},
+	}, "123456789012")
+
+	_, err := nlb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 443}, newMockAnalyzerContext(accountCtx))
+	var blockErr *domain.BlockingError
+	if !errors.As(err, &blockErr) {
+		t.Fatalf("expected BlockingError, got %v", err)
+	}
+	if !strings.Contains(blockErr.Reason, "does not forward to a target group") {
+		t.Errorf("unexpected reason: %s", blockErr.Reason)
+	}
+}
+
+func TestALB_GetNextHops_NoListenersBlocks(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", newMockAWSClient())
+
+	alb := NewALB(&domain.ALBData{
+		ARN:             "alb-1",
+		TargetGroupARNs: []string{"tg-1"},
+		ListenersLoaded: true,
+	}, "123456789012")
+
+	_, err := alb.GetNextHops(domain.RoutingTarget{IP: "10.0.1.100", Port: 443, Protocol: "tcp"}, newMockAnalyzerContext(accountCtx))
+	var blockErr *domain.BlockingError
+	if !errors.As(err, &blockErr) {
+		t.Fatalf("expected BlockingError, got %v", err)
+	}
+	if blockErr.Reason != "no listeners" {
+		t.Errorf("unexpected reason: %s", blockErr.Reason)
+	}
+}
+
+func TestTargetGroup_Translate_RewritesToTargetPort(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     *domain.TargetGroupData
+		wantPort int
+	}{
+		{
+			name: "registered target port",
+			data: &domain.TargetGroupData{ARN: "tg-1", Port: 80, Targets: []domain.TargetData{
+				{ID: "10.0.1.100", Port: 8080, HealthStatus: "healthy"},
+			}},
+			wantPort: 8080,
+		},
+		{
+			name: "group port when target has none",
+			data: &domain.TargetGroupData{ARN: "tg-1", Port: 9000, Targets: []domain.TargetData{
+				{ID: "i-12345", HealthStatus: "healthy"},
+			}},
+			wantPort: 9000,
+		},
+		{
+			name: "lambda targets keep the listener port",
+			data: &domain.TargetGroupData{ARN: "tg-1", TargetType: "lambda", Targets: []domain.TargetData{
+				{ID: "arn:aws:lambda:us-east-1:123456789012:function:fn", HealthStatus: "healthy"},
+			}},
+			wantPort: 443,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := NewTargetGroup(tt.data, "123456789012")
+			got := tg.Translate(domain.RoutingTarget{IP: "10.0.0.50", Port: 443})
+			if got.Port != tt.wantPort {
+				t.Errorf("expected port %d, got %d", tt.wantPort, got.Port)
+			}
+		})
+	}
+
+	tg := NewTargetGroup(&domain.TargetGroupData{ARN: "tg-1", Port: 80, Targets: []domain.TargetData{
+		{ID: "10.0.1.100", Port: 8080, HealthStatus: "healthy"},
+	}}, "123456789012")
+	if got := tg.Translate(domain.RoutingTarget{Port: 50000, Reply: true}); got.Port != 50000 {
+		t.Errorf("expected replies to keep their port, got %d", got.Port)
+	}
+}
+
+func TestTargetGroup_GetNextHops_SplitsTargetsByPort(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("123456789012", newMockAWSClient())
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tg := NewTargetGroup(&domain.TargetGroupData{
+		ARN:        "tg-1",
+		TargetType: "ip",
+		Port:       80,
+		Targets: []domain.TargetData{
+			{ID: "10.0.1.100", Port: 8080, HealthStatus: "healthy"},
+			{ID: "10.0.1.101", Port: 9090, HealthStatus: "healthy"},
+			{ID: "10.0.1.102", Port: 8080, HealthStatus: "healthy"},
+		},
+	}, "123456789012")
+
+	hops, err := tg.GetNextHops(domain.RoutingTarget{IP: "10.0.0.50", Port: 443}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("expected one target group per port, got %d hops", len(hops))
+	}
+	if got := tg.Translate(domain.RoutingTarget{Port: 443}).Port; got != 443 {
+		t.Errorf("expected the unsplit group to leave the port alone, got %d", got)
+	}
+
+	wantTargets := map[int]int{8080: 2, 9090: 1}
+	for _, hop := range hops {
+		split := hop.(*TargetGroup)
+		port := split.Translate(domain.RoutingTarget{Port: 443}).Port
+		if split.GetStateKey() != fmt.Sprintf("%s:%d", tg.GetID(), port) {
+			t.Errorf("expected state key to carry port %d, got %s", port, split.GetStateKey())
+		}
+		targets, err := split.GetNextHops(domain.RoutingTarget{IP: "10.0.0.50", Port: port}, analyzerCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(targets) != wantTargets[port] {
+			t.Errorf("expected %d targets on port %d, got %d", wantTargets[port], port, len(targets))
+		}
+	}
+}
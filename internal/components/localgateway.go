@@ -9,12 +9,14 @@ import (
 type LocalGateway struct {
 	id        string
 	accountID string
+	region    string
 }
 
-func NewLocalGateway(id, accountID string) *LocalGateway {
+func NewLocalGateway(id, accountID, region string) *LocalGateway {
 	return &LocalGateway{
 		id:        id,
 		accountID: accountID,
+		region:    region,
 	}
 }
 
@@ -61,7 +63,7 @@ func (lgw *LocalGateway) GetVPCID() string {
 }
 
 func (lgw *LocalGateway) GetRegion() string {
-	return ""
+	return lgw.region
 }
 
 func (lgw *LocalGateway) GetSubnetID() string {
@@ -3,6 +3,7 @@ package components
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -12,6 +13,7 @@ type mockAWSClient struct {
 	subnets             map[string]*domain.SubnetData
 	nacls               map[string]*domain.NACLData
 	routeTables         map[string]*domain.RouteTableData
+	gatewayRouteTables  map[string]*domain.RouteTableData
 	vpcs                map[string]*domain.VPCData
 	igws                map[string]*domain.InternetGatewayData
 	eigws               map[string]*domain.EgressOnlyInternetGatewayData
@@ -25,9 +27,12 @@ type mockAWSClient struct {
 	lambdaFunctions     map[string]*domain.LambdaFunctionData
 	vgws                map[string]*domain.VirtualPrivateGatewayData
 	vpnConnections      map[string]*domain.VPNConnectionData
+	tgwConnects         map[string]*domain.TGWConnectAttachmentData
 	dxGateways          map[string]*domain.DirectConnectGatewayData
 	tgwPeerings         map[string]*domain.TGWPeeringAttachmentData
 	enisBySG            map[string][]domain.ENIData
+	enisByVPC           map[string][]domain.ENIData
+	apisByVPC           map[string][]domain.APIGatewayData
 	networkENIs         map[string]*domain.ENIData
 	prefixLists         map[string]*domain.ManagedPrefixListData
 	albs                map[string]*domain.ALBData
@@ -39,6 +44,7 @@ type mockAWSClient struct {
 	elasticacheClusters map[string]*domain.ElastiCacheClusterData
 	dxgwAttachments     map[string][]domain.TGWAttachmentData
 	networkFirewalls    map[string]*domain.NetworkFirewallData
+	eniLookupErr        error
 }
 
 func newMockAWSClient() *mockAWSClient {
@@ -47,6 +53,7 @@ func newMockAWSClient() *mockAWSClient {
 		subnets:             make(map[string]*domain.SubnetData),
 		nacls:               make(map[string]*domain.NACLData),
 		routeTables:         make(map[string]*domain.RouteTableData),
+		gatewayRouteTables:  make(map[string]*domain.RouteTableData),
 		vpcs:                make(map[string]*domain.VPCData),
 		igws:                make(map[string]*domain.InternetGatewayData),
 		eigws:               make(map[string]*domain.EgressOnlyInternetGatewayData),
@@ -60,9 +67,12 @@ func newMockAWSClient() *mockAWSClient {
 		lambdaFunctions:     make(map[string]*domain.LambdaFunctionData),
 		vgws:                make(map[string]*domain.VirtualPrivateGatewayData),
 		vpnConnections:      make(map[string]*domain.VPNConnectionData),
+		tgwConnects:         make(map[string]*domain.TGWConnectAttachmentData),
 		dxGateways:          make(map[string]*domain.DirectConnectGatewayData),
 		tgwPeerings:         make(map[string]*domain.TGWPeeringAttachmentData),
 		enisBySG:            make(map[string][]domain.ENIData),
+		enisByVPC:           make(map[string][]domain.ENIData),
+		apisByVPC:           make(map[string][]domain.APIGatewayData),
 		networkENIs:         make(map[string]*domain.ENIData),
 		prefixLists:         make(map[string]*domain.ManagedPrefixListData),
 		albs:                make(map[string]*domain.ALBData),
@@ -91,6 +101,18 @@ func (m *mockAWSClient) GetSubnet(ctx context.Context, subnetID string) (*domain
 	return nil, fmt.Errorf("subnet %s not found", subnetID)
 }
 
+func (m *mockAWSClient) GetSubnetByIP(ctx context.Context, ip, vpcID string) (*domain.SubnetData, error) {
+	for _, subnet := range m.subnets {
+		if subnet.VPCID != vpcID {
+			continue
+		}
+		if IPMatchesCIDR(ip, subnet.CIDRBlock) || IPMatchesCIDR(ip, subnet.IPv6CIDRBlock) {
+			return subnet, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockAWSClient) GetNACL(ctx context.Context, naclID string) (*domain.NACLData, error) {
 	if nacl, ok := m.nacls[naclID]; ok {
 		return nacl, nil
@@ -105,6 +127,10 @@ func (m *mockAWSClient) GetRouteTable(ctx context.Context, rtID string) (*domain
 	return nil, fmt.Errorf("route table %s not found", rtID)
 }
 
+func (m *mockAWSClient) GetGatewayRouteTable(ctx context.Context, gatewayID string) (*domain.RouteTableData, error) {
+	return m.gatewayRouteTables[gatewayID], nil
+}
+
 func (m *mockAWSClient) GetVPC(ctx context.Context, vpcID string) (*domain.VPCData, error) {
 	if vpc, ok := m.vpcs[vpcID]; ok {
 		return vpc, nil
@@ -119,6 +145,15 @@ func (m *mockAWSClient) GetInternetGateway(ctx context.Context, igwID string) (*
 	return nil, fmt.Errorf("internet gateway %s not found", igwID)
 }
 
+func (m *mockAWSClient) GetInternetGatewayByVPC(ctx context.Context, vpcID string) (*domain.InternetGatewayData, error) {
+	for _, igw := range m.igws {
+		if igw.VPCID == vpcID {
+			return igw, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockAWSClient) GetEgressOnlyInternetGateway(ctx context.Context, eigwID string) (*domain.EgressOnlyInternetGatewayData, error) {
 	if eigw, ok := m.eigws[eigwID]; ok {
 		return eigw, nil
@@ -148,8 +183,14 @@ func (m *mockAWSClient) GetNetworkInterface(ctx context.Context, eniID string) (
 }
 
 func (m *mockAWSClient) GetNetworkInterfaceByPrivateIP(ctx context.Context, ip, vpcID string) (*domain.ENIData, error) {
+	if m.eniLookupErr != nil {
+		return nil, m.eniLookupErr
+	}
 	for _, eni := range m.networkENIs {
-		if eni.PrivateIP == ip {
+		if vpcID != "" && eni.VPCID != vpcID {
+			continue
+		}
+		if eni.PrivateIP == ip || slices.Contains(eni.IPv6Addresses, ip) {
 			return eni, nil
 		}
 	}
@@ -270,13 +311,20 @@ func (m *mockAWSClient) GetDirectConnectGateway(ctx context.Context, dxgwID stri
 	return nil, fmt.Errorf("direct Connect Gateway %s not found", dxgwID)
 }
 
-func (m *mockAWSClient) GetTGWPeeringAttachment(ctx context.Context, attachmentID string) (*domain.TGWPeeringAttachmentData, error) {
+func (m *mockAWSClient) GetTGWPeeringAttachment(ctx context.Context, attachmentID, tgwID string) (*domain.TGWPeeringAttachmentData, error) {
 	if peering, ok := m.tgwPeerings[attachmentID]; ok {
 		return peering, nil
 	}
 	return nil, fmt.Errorf("TGW peering attachment %s not found", attachmentID)
 }
 
+func (m *mockAWSClient) GetTGWConnectAttachment(ctx context.Context, attachmentID string) (*domain.TGWConnectAttachmentData, error) {
+	if connect, ok := m.tgwConnects[attachmentID]; ok {
+		return connect, nil
+	}
+	return nil, fmt.Errorf("TGW connect attachment %s not found", attachmentID)
+}
+
 func (m *mockAWSClient) GetENIsBySecurityGroup(ctx context.Context, sgID string) ([]domain.ENIData, error) {
 	if enis, ok := m.enisBySG[sgID]; ok {
 		return enis, nil
@@ -284,6 +332,10 @@ func (m *mockAWSClient) GetENIsBySecurityGroup(ctx context.Context, sgID string)
 	return []domain.ENIData{}, nil
 }
 
+func (m *mockAWSClient) GetENIsByVPC(ctx context.Context, vpcID string) ([]domain.ENIData, error) {
+	return m.enisByVPC[vpcID], nil
+}
+
 func (m *mockAWSClient) GetManagedPrefixList(ctx context.Context, prefixListID string) (*domain.ManagedPrefixListData, error) {
 	if pl, ok := m.prefixLists[prefixListID]; ok {
 		return pl, nil
@@ -377,6 +429,10 @@ func (m *mockAWSClient) GetAPIGatewayByPrivateIP(ctx context.Context, ip, vpcID
 	return nil, nil
 }
 
+func (m *mockAWSClient) GetAPIGatewaysByVPC(ctx context.Context, vpcID string) ([]domain.APIGatewayData, error) {
+	return m.apisByVPC[vpcID], nil
+}
+
 func (m *mockAWSClient) GetEKSPodByIP(ctx context.Context, ip, vpcID string) (*domain.EKSPodData, error) {
 	if pod, ok := m.eksPods[ip]; ok {
 		return pod, nil
@@ -441,17 +497,26 @@ func (m *mockAccountContext) AssumeRole(accountID string) (domain.AWSCredentials
 	return domain.AWSCredentials{}, nil
 }
 
-func (m *mockAccountContext) GetClient(accountID string) (domain.AWSClient, error) {
+func (m *mockAccountContext) GetClient(accountID, region string) (domain.AWSClient, error) {
+	if client, ok := m.clients[accountID+"/"+region]; ok {
+		return client, nil
+	}
 	if client, ok := m.clients[accountID]; ok {
 		return client, nil
 	}
-	return nil, fmt.Errorf("no client for account %s", accountID)
+	return nil, fmt.Errorf("no client for account %s in region %q", accountID, region)
 }
 
 func (m *mockAccountContext) addClient(accountID string, client *mockAWSClient) {
 	m.clients[accountID] = client
 }
 
+// addRegionalClient registers a client that only answers for region, so a
+// lookup in any other region fails.
+func (m *mockAccountContext) addRegionalClient(accountID, region string, client *mockAWSClient) {
+	m.clients[accountID+"/"+region] = client
+}
+
 type mockAnalyzerContext struct {
 	ctx        context.Context
 	accountCtx *mockAccountContext
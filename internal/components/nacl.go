@@ -52,13 +52,13 @@ func (n *NACL) EvaluateOutbound(dest domain.RoutingTarget, analyzerCtx domain.An
 	sortedRules := SortNACLRulesByNumber(n.data.OutboundRules)
 
 	for _, rule := range sortedRules {
-		if n.ruleMatches(rule, dest) {
+		if n.ruleMatches(rule, dest.IP, dest) {
 			if rule.Action == "allow" {
 				return nil
 			}
 			return &domain.BlockingError{
 				ComponentID: n.GetID(),
-				Reason:      fmt.Sprintf("NACL outbound rule %d denies traffic to %s:%d/%s", rule.RuleNumber, dest.IP, dest.Port, dest.Protocol),
+				Reason:      fmt.Sprintf("NACL outbound rule %d denies traffic to %s:%s/%s", rule.RuleNumber, dest.IP, formatPorts(dest), dest.Protocol),
 			}
 		}
 	}
@@ -70,16 +70,20 @@ func (n *NACL) EvaluateOutbound(dest domain.RoutingTarget, analyzerCtx domain.An
 }
 
 func (n *NACL) EvaluateInbound(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) error {
+	sourceIP := inboundSourceIP(dest)
+	if sourceIP == "" {
+		return unknownSourceError(n.GetID())
+	}
 	sortedRules := SortNACLRulesByNumber(n.data.InboundRules)
 
 	for _, rule := range sortedRules {
-		if n.ruleMatches(rule, dest) {
+		if n.ruleMatches(rule, sourceIP, dest) {
 			if rule.Action == "allow" {
 				return nil
 			}
 			return &domain.BlockingError{
 				ComponentID: n.GetID(),
-				Reason:      fmt.Sprintf("NACL inbound rule %d denies traffic to %s:%d/%s", rule.RuleNumber, dest.IP, dest.Port, dest.Protocol),
+				Reason:      fmt.Sprintf("NACL inbound rule %d denies traffic from %s to port %s/%s", rule.RuleNumber, sourceIP, formatPorts(dest), dest.Protocol),
 			}
 		}
 	}
@@ -90,22 +94,29 @@ func (n *NACL) EvaluateInbound(dest domain.RoutingTarget, analyzerCtx domain.Ana
 	}
 }
 
-func (n *NACL) ruleMatches(rule domain.NACLRule, target domain.RoutingTarget) bool {
+func (n *NACL) ruleMatches(rule domain.NACLRule, ip string, target domain.RoutingTarget) bool {
 	if !protocolMatches(rule.Protocol, target.Protocol) {
 		return false
 	}
-	if !portInRange(target.Port, rule.FromPort, rule.ToPort) {
+	if !n.rulePortsMatch(rule, target) {
 		return false
 	}
-	if rule.CIDRBlock != "" && IPMatchesCIDR(target.IP, rule.CIDRBlock) {
+	if rule.CIDRBlock != "" && IPMatchesCIDR(ip, rule.CIDRBlock) {
 		return true
 	}
-	if rule.IPv6CIDRBlock != "" && IPMatchesCIDR(target.IP, rule.IPv6CIDRBlock) {
+	if rule.IPv6CIDRBlock != "" && IPMatchesCIDR(ip, rule.IPv6CIDRBlock) {
 		return true
 	}
 	return false
 }
 
+func (n *NACL) rulePortsMatch(rule domain.NACLRule, target domain.RoutingTarget) bool {
+	if rule.Action == "deny" {
+		return flowPortsOverlap(target, rule.FromPort, rule.ToPort)
+	}
+	return flowPortsInRange(target, rule.FromPort, rule.ToPort)
+}
+
 func (n *NACL) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -114,6 +125,10 @@ func (n *NACL) GetID() string {
 	return fmt.Sprintf("%s:%s", n.accountID, n.data.ID)
 }
 
+func (n *NACL) GetStateKey() string {
+	return n.GetID() + ">" + stateKey(n.next)
+}
+
 func (n *NACL) GetAccountID() string {
 	return n.accountID
 }
@@ -127,7 +142,7 @@ func (n *NACL) GetVPCID() string {
 }
 
 func (n *NACL) GetRegion() string {
-	return ""
+	return n.data.Region
 }
 
 func (n *NACL) GetSubnetID() string {
@@ -141,9 +156,14 @@ func (n *NACL) GetAvailabilityZone() string {
 func (n *NACL) EvaluateWithDetails(target domain.RoutingTarget, direction string) domain.EvaluationResult {
 	var rules []domain.NACLRule
 	ruleType := "outbound"
+	peerIP := target.IP
 	if direction == "inbound" {
 		rules = SortNACLRulesByNumber(n.data.InboundRules)
 		ruleType = "inbound"
+		peerIP = inboundSourceIP(target)
+		if peerIP == "" {
+			return domain.EvaluationResult{Reason: "source address unknown"}
+		}
 	} else {
 		rules = SortNACLRulesByNumber(n.data.OutboundRules)
 	}
@@ -168,7 +188,7 @@ func (n *NACL) EvaluateWithDetails(target domain.RoutingTarget, direction string
 		}
 
 		protocolMatch := protocolMatches(rule.Protocol, target.Protocol)
-		portMatch := portInRange(target.Port, rule.FromPort, rule.ToPort)
+		portMatch := n.rulePortsMatch(rule, target)
 
 		if !protocolMatch {
 			eval.Matched = false
@@ -179,17 +199,17 @@ func (n *NACL) EvaluateWithDetails(target domain.RoutingTarget, direction string
 
 		if !portMatch {
 			eval.Matched = false
-			eval.Reason = fmt.Sprintf("port %d not in range %d-%d", target.Port, rule.FromPort, rule.ToPort)
+			eval.Reason = fmt.Sprintf("port %s not in range %d-%d", formatPorts(target), rule.FromPort, rule.ToPort)
 			evaluations = append(evaluations, eval)
 			continue
 		}
 
 		cidrMatch := false
-		if rule.CIDRBlock != "" && IPMatchesCIDR(target.IP, rule.CIDRBlock) {
+		if rule.CIDRBlock != "" && IPMatchesCIDR(peerIP, rule.CIDRBlock) {
 			cidrMatch = true
 			eval.DestCIDR = rule.CIDRBlock
 		}
-		if !cidrMatch && rule.IPv6CIDRBlock != "" && IPMatchesCIDR(target.IP, rule.IPv6CIDRBlock) {
+		if !cidrMatch && rule.IPv6CIDRBlock != "" && IPMatchesCIDR(peerIP, rule.IPv6CIDRBlock) {
 			cidrMatch = true
 			eval.DestCIDR = rule.IPv6CIDRBlock
 		}
@@ -214,7 +234,7 @@ func (n *NACL) EvaluateWithDetails(target domain.RoutingTarget, direction string
 		}
 
 		eval.Matched = false
-		eval.Reason = fmt.Sprintf("IP %s not in CIDR %s", target.IP, eval.DestCIDR)
+		eval.Reason = fmt.Sprintf("IP %s not in CIDR %s", peerIP, eval.DestCIDR)
 		evaluations = append(evaluations, eval)
 	}
 
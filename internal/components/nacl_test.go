@@ -678,3 +678,92 @@ func TestNACL_GetNextHops_MixedIPv4AndIPv6Rules(t *testing.T) {
 		})
 	}
 }
+
+func TestNACL_GetNextHops_FlowRangePartiallyDenied(t *testing.T) {
+	nacl := NewNACL(&domain.NACLData{
+		ID:    "acl-123",
+		VPCID: "vpc-abc",
+		OutboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "tcp", FromPort: 8080, ToPort: 8080, CIDRBlock: "0.0.0.0/0", Action: "deny"},
+			{RuleNumber: 200, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"},
+		},
+	}, "111122223333")
+
+	dest := domain.RoutingTarget{IP: "10.0.1.1", Port: 8000, PortTo: 8100, Protocol: "tcp"}
+	_, err := nacl.GetNextHops(dest, nil)
+	if err == nil {
+		t.Fatal("expected range containing a denied port to be blocked")
+	}
+	if !strings.Contains(err.Error(), "8000-8100") {
+		t.Errorf("expected port range in reason, got %v", err)
+	}
+
+	dest = domain.RoutingTarget{IP: "10.0.1.1", Port: 9000, PortTo: 9100, Protocol: "tcp"}
+	if _, err := nacl.GetNextHops(dest, nil); err != nil {
+		t.Errorf("expected allowed, got error: %v", err)
+	}
+}
+
+func TestNACL_EvaluateInbound_MatchesSourceIP(t *testing.T) {
+	nacl := NewNACL(&domain.NACLData{
+		ID:    "acl-123",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "-1", CIDRBlock: "192.168.0.0/16", Action: "deny"},
+			{RuleNumber: 200, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"},
+		},
+	}, "111122223333")
+
+	dest := domain.RoutingTarget{SourceIP: "192.168.4.4", IP: "10.0.1.20", Port: 443, Protocol: "tcp", Direction: "inbound"}
+	_, err := nacl.GetNextHops(dest, nil)
+	if err == nil {
+		t.Fatal("expected traffic from 192.168.4.4 to be denied")
+	}
+	if !strings.Contains(err.Error(), "rule 100") {
+		t.Errorf("expected rule 100 in reason, got %v", err)
+	}
+
+	dest.SourceIP = "10.0.5.5"
+	if _, err := nacl.GetNextHops(dest, nil); err != nil {
+		t.Errorf("expected allowed, got %v", err)
+	}
+}
+
+func TestNACL_EvaluateInbound_UnknownSourceIsUndecided(t *testing.T) {
+	nacl := NewNACL(&domain.NACLData{
+		ID:    "acl-123",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "-1", CIDRBlock: "10.0.1.0/24", Action: "allow"},
+		},
+	}, "111122223333")
+
+	dest := domain.RoutingTarget{IP: "10.0.1.20", Port: 443, Protocol: "tcp", Direction: "inbound"}
+	_, err := nacl.GetNextHops(dest, nil)
+	if err == nil {
+		t.Fatal("expected an error when the source is unknown, not a match on the destination address")
+	}
+	if domain.IsBlocking(err) {
+		t.Errorf("expected an unknown source to be undecided, got blocking error %v", err)
+	}
+}
+
+func TestNACL_GetNextHops_ReplyNeedsEphemeralPorts(t *testing.T) {
+	nacl := NewNACL(&domain.NACLData{
+		ID:    "acl-123",
+		VPCID: "vpc-abc",
+		OutboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "tcp", FromPort: 32768, ToPort: 60999, CIDRBlock: "0.0.0.0/0", Action: "allow"},
+		},
+	}, "111122223333")
+
+	linux := domain.RoutingTarget{IP: "10.0.1.10", Port: 32768, PortTo: 60999, Protocol: "tcp", Direction: "outbound", Reply: true}
+	if _, err := nacl.GetNextHops(linux, nil); err != nil {
+		t.Errorf("expected Linux ephemeral reply to be allowed, got %v", err)
+	}
+
+	anyClient := domain.RoutingTarget{IP: "10.0.1.10", Port: 1024, PortTo: 65535, Protocol: "tcp", Direction: "outbound", Reply: true}
+	if _, err := nacl.GetNextHops(anyClient, nil); err == nil {
+		t.Error("expected reply to 1024-65535 to be blocked by a Linux-only ephemeral rule")
+	}
+}
@@ -26,7 +26,14 @@ func (nat *NATGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 		}
 	}
 
-	if !isExternalIP(dest.IP) {
+	if dest.Reply {
+		// Replies arrive at the gateway's own address and are translated back
+		// to the address that opened the connection, which the gateway's
+		// subnet reaches over its local route.
+		return nat.subnetHops(analyzerCtx)
+	}
+
+	if !nat.isPrivate() && !isExternalIP(dest.IP) {
 		return nil, &domain.BlockingError{
 			ComponentID: nat.GetID(),
 			Reason:      "NAT gateway can only route to external (public) IP addresses",
@@ -38,7 +45,11 @@ func (nat *NATGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 			Reason:      "NAT gateway expects private source IP for outbound traffic",
 		}
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(nat.accountID)
+	return nat.subnetHops(analyzerCtx)
+}
+
+func (nat *NATGateway) subnetHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(nat.accountID, nat.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +70,35 @@ func (nat *NATGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 	return []domain.Component{NewSubnet(subnetData, nat.accountID)}, nil
 }
 
+// Translate replaces the source of outbound traffic with the gateway's public
+// address, which is the address the destination sees. A private NAT gateway
+// uses its private address instead.
+func (nat *NATGateway) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	if target.Reply {
+		return target
+	}
+	if nat.isPrivate() {
+		if nat.data.PrivateIP != "" {
+			target.SourceIP = nat.data.PrivateIP
+			target.SourceIsPrivate = true
+		}
+		return target
+	}
+	if nat.data.PublicIP == "" {
+		return target
+	}
+	target.SourceIP = nat.data.PublicIP
+	target.SourceIsPrivate = false
+	return target
+}
+
+// isPrivate reports whether the gateway is a private NAT gateway, which
+// translates traffic to other private networks, such as overlapping CIDRs
+// reached through a transit gateway, rather than to the internet.
+func (nat *NATGateway) isPrivate() bool {
+	return nat.data.ConnectivityType == "private"
+}
+
 func (nat *NATGateway) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -80,7 +120,7 @@ func (nat *NATGateway) GetVPCID() string {
 }
 
 func (nat *NATGateway) GetRegion() string {
-	return ""
+	return nat.data.Region
 }
 
 func (nat *NATGateway) GetSubnetID() string {
@@ -22,7 +22,10 @@ func NewNetworkFirewall(data *domain.NetworkFirewallData, accountID string) *Net
 }
 
 func (nf *NetworkFirewall) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	statelessResult := nf.evaluateStatelessRules(dest)
+	statelessResult, err := nf.evaluateStatelessRules(dest)
+	if err != nil {
+		return nil, err
+	}
 
 	switch statelessResult.action {
 	case "drop":
@@ -33,7 +36,13 @@ func (nf *NetworkFirewall) GetNextHops(dest domain.RoutingTarget, analyzerCtx do
 	case "pass":
 		return nf.resolveNextHop(dest, analyzerCtx)
 	case "forward_to_stateful":
-		statefulResult := nf.evaluateStatefulRules(dest)
+		if dest.Reply {
+			return nf.resolveNextHop(dest, analyzerCtx)
+		}
+		statefulResult, err := nf.evaluateStatefulRules(dest)
+		if err != nil {
+			return nil, err
+		}
 		if !statefulResult.allowed {
 			return nil, &domain.BlockingError{
 				ComponentID: nf.GetID(),
@@ -59,7 +68,7 @@ type statelessEvalResult struct {
 	reason string
 }
 
-func (nf *NetworkFirewall) evaluateStatelessRules(dest domain.RoutingTarget) statelessEvalResult {
+func (nf *NetworkFirewall) evaluateStatelessRules(dest domain.RoutingTarget) (statelessEvalResult, error) {
 	groups := make([]domain.StatelessRuleGroup, len(nf.data.StatelessRuleGroups))
 	copy(groups, nf.data.StatelessRuleGroups)
 	sort.Slice(groups, func(i, j int) bool {
@@ -74,12 +83,16 @@ func (nf *NetworkFirewall) evaluateStatelessRules(dest domain.RoutingTarget) sta
 		})
 
 		for _, rule := range rules {
-			if nf.matchesStatelessRule(rule, dest) {
+			matched, err := nf.matchesStatelessRule(rule, dest)
+			if err != nil {
+				return statelessEvalResult{}, err
+			}
+			if matched {
 				action := nf.mapStatelessAction(rule.Actions)
 				return statelessEvalResult{
 					action: action,
 					reason: fmt.Sprintf("matched stateless rule priority %d", rule.Priority),
-				}
+				}, nil
 			}
 		}
 	}
@@ -87,41 +100,55 @@ func (nf *NetworkFirewall) evaluateStatelessRules(dest domain.RoutingTarget) sta
 	return statelessEvalResult{
 		action: "forward_to_stateful",
 		reason: "no stateless rule matched",
-	}
+	}, nil
 }
 
-func (nf *NetworkFirewall) matchesStatelessRule(rule domain.StatelessRule, dest domain.RoutingTarget) bool {
+func (nf *NetworkFirewall) matchesStatelessRule(rule domain.StatelessRule, dest domain.RoutingTarget) (bool, error) {
 	if !nf.matchesProtocols(rule.Match.Protocols, dest.Protocol) {
-		return false
+		return false, nil
+	}
+
+	if len(rule.Match.Sources) > 0 && dest.SourceIP != "" {
+		matched, err := nf.matchesAnyAddress(rule.Match.Sources, dest.SourceIP, nf.data.Variables)
+		if err != nil || !matched {
+			return false, err
+		}
 	}
 
 	if len(rule.Match.Destinations) > 0 {
+		matched, err := nf.matchesAnyAddress(rule.Match.Destinations, dest.IP, nf.data.Variables)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	if len(rule.Match.SourcePorts) > 0 && dest.SourcePort != 0 {
 		matched := false
-		for _, cidr := range rule.Match.Destinations {
-			if nf.matchesAddress(cidr, dest.IP) {
+		for _, pr := range rule.Match.SourcePorts {
+			if pr.Contains(dest.SourcePort) {
 				matched = true
 				break
 			}
 		}
 		if !matched {
-			return false
+			return false, nil
 		}
 	}
 
 	if len(rule.Match.DestPorts) > 0 {
 		matched := false
 		for _, pr := range rule.Match.DestPorts {
-			if pr.Contains(dest.Port) {
+			if pr.ContainsRange(dest.PortRange()) {
 				matched = true
 				break
 			}
 		}
 		if !matched {
-			return false
+			return false, nil
 		}
 	}
 
-	return true
+	return true, nil
 }
 
 func (nf *NetworkFirewall) matchesProtocols(protocols []int, trafficProtocol string) bool {
@@ -138,16 +165,102 @@ func (nf *NetworkFirewall) matchesProtocols(protocols []int, trafficProtocol str
 	return false
 }
 
-func (nf *NetworkFirewall) matchesAddress(addressDef string, ip string) bool {
-	if addressDef == "ANY" || addressDef == "" {
-		return true
+func (nf *NetworkFirewall) matchesAnyAddress(addressDefs []string, ip string, variables map[string][]string) (bool, error) {
+	for _, addressDef := range addressDefs {
+		matched, err := nf.matchesAddress(addressDef, ip, variables)
+		if err != nil || matched {
+			return matched, err
+		}
 	}
+	return false, nil
+}
 
-	if strings.Contains(addressDef, "/") {
-		return IPMatchesCIDR(ip, addressDef)
+// matchesAddress reports whether ip is in addressDef, which is written the way
+// Suricata writes addresses: ANY, an address, a CIDR, an IP set variable such
+// as $HOME_NET, any of these negated with !, or a bracketed list of them.
+// EXTERNAL_NET is everything outside HOME_NET unless it is defined. A variable
+// that is defined nowhere leaves the rule undecided, which is returned as an
+// error rather than guessed.
+func (nf *NetworkFirewall) matchesAddress(addressDef string, ip string, variables map[string][]string) (bool, error) {
+	addressDef = strings.TrimSpace(addressDef)
+
+	switch {
+	case addressDef == "" || strings.EqualFold(addressDef, "any"):
+		return true, nil
+
+	case strings.HasPrefix(addressDef, "!"):
+		matched, err := nf.matchesAddress(addressDef[1:], ip, variables)
+		return !matched, err
+
+	case strings.HasPrefix(addressDef, "[") && strings.HasSuffix(addressDef, "]"):
+		var included, excluded []string
+		for _, item := range splitAddressList(addressDef[1 : len(addressDef)-1]) {
+			if strings.HasPrefix(item, "!") {
+				excluded = append(excluded, item[1:])
+			} else {
+				included = append(included, item)
+			}
+		}
+		if len(included) > 0 {
+			matched, err := nf.matchesAnyAddress(included, ip, variables)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		matched, err := nf.matchesAnyAddress(excluded, ip, variables)
+		return !matched, err
+
+	case strings.HasPrefix(addressDef, "$"):
+		name := addressDef[1:]
+		if cidrs, ok := variables[name]; ok {
+			return nf.matchesAnyAddress(cidrs, ip, nil)
+		}
+		if name == "EXTERNAL_NET" {
+			if _, ok := variables["HOME_NET"]; ok {
+				return nf.matchesAddress("!$HOME_NET", ip, variables)
+			}
+		}
+		return false, fmt.Errorf("network firewall %s: rule variable %s is not defined", nf.data.ID, addressDef)
+
+	case strings.Contains(addressDef, "/"):
+		return IPMatchesCIDR(ip, addressDef), nil
+	}
+
+	return addressDef == ip, nil
+}
+
+// splitAddressList splits a bracketed address list on the commas outside any
+// nested list.
+func splitAddressList(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
 	}
+	return append(items, strings.TrimSpace(list[start:]))
+}
 
-	return addressDef == ip
+// groupVariables returns the IP set variables rules in group see: the group's
+// own, overridden by the policy's.
+func (nf *NetworkFirewall) groupVariables(group domain.StatefulRuleGroup) map[string][]string {
+	variables := make(map[string][]string, len(group.Variables)+len(nf.data.Variables))
+	for name, cidrs := range group.Variables {
+		variables[name] = cidrs
+	}
+	for name, cidrs := range nf.data.Variables {
+		variables[name] = cidrs
+	}
+	return variables
 }
 
 func (nf *NetworkFirewall) mapStatelessAction(actions []string) string {
@@ -185,7 +298,7 @@ type statefulEvalResult struct {
 	reason  string
 }
 
-func (nf *NetworkFirewall) evaluateStatefulRules(dest domain.RoutingTarget) statefulEvalResult {
+func (nf *NetworkFirewall) evaluateStatefulRules(dest domain.RoutingTarget) (statefulEvalResult, error) {
 	groups := make([]domain.StatefulRuleGroup, len(nf.data.StatefulRuleGroups))
 	copy(groups, nf.data.StatefulRuleGroups)
 	sort.Slice(groups, func(i, j int) bool {
@@ -193,13 +306,18 @@ func (nf *NetworkFirewall) evaluateStatefulRules(dest domain.RoutingTarget) stat
 	})
 
 	for _, group := range groups {
+		variables := nf.groupVariables(group)
 		for _, rule := range group.Rules {
-			if nf.matchesStatefulRule(rule, dest) {
+			matched, err := nf.matchesStatefulRule(rule, dest, variables)
+			if err != nil {
+				return statefulEvalResult{}, err
+			}
+			if matched {
 				action := nf.mapStatefulAction(rule.Action)
 				return statefulEvalResult{
 					allowed: action == "pass",
 					reason:  fmt.Sprintf("matched stateful rule action %s", rule.Action),
-				}
+				}, nil
 			}
 		}
 	}
@@ -208,34 +326,50 @@ func (nf *NetworkFirewall) evaluateStatefulRules(dest domain.RoutingTarget) stat
 	return statefulEvalResult{
 		allowed: defaultAction != "drop",
 		reason:  fmt.Sprintf("default stateful action: %s", defaultAction),
-	}
+	}, nil
 }
 
-func (nf *NetworkFirewall) matchesStatefulRule(rule domain.StatefulRule, dest domain.RoutingTarget) bool {
+func (nf *NetworkFirewall) matchesStatefulRule(rule domain.StatefulRule, dest domain.RoutingTarget, variables map[string][]string) (bool, error) {
 	if rule.Direction != "" && rule.Direction != dest.Direction {
-		return false
+		return false, nil
 	}
 
 	if rule.Protocol != "any" && rule.Protocol != "" {
 		if !protocolMatches(rule.Protocol, dest.Protocol) {
-			return false
+			return false, nil
+		}
+	}
+
+	if rule.Source != "any" && rule.Source != "" && dest.SourceIP != "" {
+		matched, err := nf.matchesAddress(rule.Source, dest.SourceIP, variables)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	if rule.SourcePort != "any" && rule.SourcePort != "" && dest.SourcePort != 0 {
+		port, err := strconv.Atoi(rule.SourcePort)
+		if err == nil && port != dest.SourcePort {
+			return false, nil
 		}
 	}
 
 	if rule.Destination != "any" && rule.Destination != "" {
-		if !nf.matchesAddress(rule.Destination, dest.IP) {
-			return false
+		matched, err := nf.matchesAddress(rule.Destination, dest.IP, variables)
+		if err != nil || !matched {
+			return false, err
 		}
 	}
 
 	if rule.DestPort != "any" && rule.DestPort != "" {
 		port, err := strconv.Atoi(rule.DestPort)
-		if err == nil && port != dest.Port {
-			return false
+		low, high := dest.PortRange()
+		if err == nil && (port != low || port != high) {
+			return false, nil
 		}
 	}
 
-	return true
+	return true, nil
 }
 
 func (nf *NetworkFirewall) mapStatefulAction(action string) string {
@@ -271,7 +405,7 @@ func (nf *NetworkFirewall) getStatefulDefaultAction() string {
 }
 
 func (nf *NetworkFirewall) resolveNextHop(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(nf.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(nf.accountID, nf.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +460,7 @@ func (nf *NetworkFirewall) GetVPCID() string {
 }
 
 func (nf *NetworkFirewall) GetRegion() string {
-	return ""
+	return nf.data.Region
 }
 
 func (nf *NetworkFirewall) GetSubnetID() string {
@@ -357,7 +491,11 @@ func (nf *NetworkFirewall) EvaluateWithDetails(target domain.RoutingTarget, dire
 				eval.PortTo = rule.Match.DestPorts[0].To
 			}
 
-			if nf.matchesStatelessRule(rule, target) {
+			matched, err := nf.matchesStatelessRule(rule, target)
+			if err != nil {
+				return nf.undecided(eval, evaluations, err)
+			}
+			if matched {
 				eval.Matched = true
 				eval.Reason = "matched stateless rule"
 				evaluations = append(evaluations, eval)
@@ -384,7 +522,16 @@ func (nf *NetworkFirewall) EvaluateWithDetails(target domain.RoutingTarget, dire
 		}
 	}
 
+	if target.Reply {
+		return domain.EvaluationResult{
+			Allowed:     true,
+			Reason:      "reply to tracked connection",
+			Evaluations: evaluations,
+		}
+	}
+
 	for _, group := range nf.data.StatefulRuleGroups {
+		variables := nf.groupVariables(group)
 		for i, rule := range group.Rules {
 			eval := domain.RuleEvaluation{
 				RuleID:   fmt.Sprintf("stateful-group-%d-rule-%d", group.Priority, i),
@@ -394,7 +541,11 @@ func (nf *NetworkFirewall) EvaluateWithDetails(target domain.RoutingTarget, dire
 				Action:   nf.mapStatefulAction(rule.Action),
 			}
 
-			if nf.matchesStatefulRule(rule, target) {
+			matched, err := nf.matchesStatefulRule(rule, target, variables)
+			if err != nil {
+				return nf.undecided(eval, evaluations, err)
+			}
+			if matched {
 				eval.Matched = true
 				eval.Reason = "matched stateful rule"
 				evaluations = append(evaluations, eval)
@@ -421,24 +572,36 @@ func (nf *NetworkFirewall) EvaluateWithDetails(target domain.RoutingTarget, dire
 	}
 }
 
+// undecided ends an evaluation at a rule that could not be matched, without
+// claiming the traffic is allowed or denied.
+func (nf *NetworkFirewall) undecided(eval domain.RuleEvaluation, evaluations []domain.RuleEvaluation, err error) domain.EvaluationResult {
+	eval.Reason = "undecided: " + err.Error()
+	return domain.EvaluationResult{
+		Reason:      fmt.Sprintf("cannot evaluate %s: %v", eval.RuleID, err),
+		Evaluations: append(evaluations, eval),
+	}
+}
+
 type NetworkFirewallEndpoint struct {
 	firewallID string
 	endpointID string
 	subnetID   string
 	accountID  string
+	region     string
 }
 
-func NewNetworkFirewallEndpoint(firewallID, endpointID, subnetID, accountID string) *NetworkFirewallEndpoint {
+func NewNetworkFirewallEndpoint(firewallID, endpointID, subnetID, accountID, region string) *NetworkFirewallEndpoint {
 	return &NetworkFirewallEndpoint{
 		firewallID: firewallID,
 		endpointID: endpointID,
 		subnetID:   subnetID,
 		accountID:  accountID,
+		region:     region,
 	}
 }
 
 func (nfe *NetworkFirewallEndpoint) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(nfe.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(nfe.accountID, nfe.region)
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +636,7 @@ func (nfe *NetworkFirewallEndpoint) GetVPCID() string {
 }
 
 func (nfe *NetworkFirewallEndpoint) GetRegion() string {
-	return ""
+	return nfe.region
 }
 
 func (nfe *NetworkFirewallEndpoint) GetSubnetID() string {
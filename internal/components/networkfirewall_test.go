@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -461,7 +462,7 @@ func TestNetworkFirewall_GetComponentType(t *testing.T) {
 }
 
 func TestNetworkFirewallEndpoint_GetNextHops(t *testing.T) {
-	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333")
+	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333", "")
 
 	accountCtx := newMockAccountContext()
 	client := newMockAWSClient()
@@ -492,7 +493,7 @@ func TestNetworkFirewallEndpoint_GetNextHops(t *testing.T) {
 }
 
 func TestNetworkFirewallEndpoint_GetID(t *testing.T) {
-	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333")
+	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333", "")
 
 	id := nfe.GetID()
 	expected := "111122223333:vpce-123"
@@ -503,7 +504,7 @@ func TestNetworkFirewallEndpoint_GetID(t *testing.T) {
 }
 
 func TestNetworkFirewallEndpoint_GetComponentType(t *testing.T) {
-	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333")
+	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333", "")
 
 	ct := nfe.GetComponentType()
 
@@ -513,7 +514,7 @@ func TestNetworkFirewallEndpoint_GetComponentType(t *testing.T) {
 }
 
 func TestNetworkFirewallEndpoint_GetSubnetID(t *testing.T) {
-	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333")
+	nfe := NewNetworkFirewallEndpoint("nfw-123", "vpce-123", "subnet-abc", "111122223333", "")
 
 	subnetID := nfe.GetSubnetID()
 
@@ -521,3 +522,195 @@ func TestNetworkFirewallEndpoint_GetSubnetID(t *testing.T) {
 		t.Errorf("GetSubnetID() = %s, want subnet-abc", subnetID)
 	}
 }
+
+func TestNetworkFirewall_GetNextHops_StatelessMatchesSource(t *testing.T) {
+	nf := NewNetworkFirewall(&domain.NetworkFirewallData{
+		ID:    "nfw-123",
+		VPCID: "vpc-abc",
+		StatelessRuleGroups: []domain.StatelessRuleGroup{
+			{
+				Priority: 1,
+				Rules: []domain.StatelessRule{
+					{
+						Priority: 1,
+						Actions:  []string{"aws:drop"},
+						Match: domain.StatelessMatch{
+							Sources:     []string{"10.9.0.0/16"},
+							SourcePorts: []domain.PortRangeSpec{{From: 1024, To: 65535}},
+						},
+					},
+				},
+			},
+		},
+	}, "111122223333")
+
+	blocked := domain.RoutingTarget{SourceIP: "10.9.1.1", SourcePort: 40000, IP: "10.0.1.50", Port: 443, Protocol: "tcp"}
+	if _, err := nf.GetNextHops(blocked, nil); err == nil {
+		t.Error("expected traffic from 10.9.0.0/16 to be dropped")
+	}
+
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.vpcs["vpc-abc"] = &domain.VPCData{ID: "vpc-abc", MainRouteTableID: "rtb-main"}
+	client.routeTables["rtb-main"] = &domain.RouteTableData{ID: "rtb-main", VPCID: "vpc-abc"}
+	accountCtx.addClient("111122223333", client)
+
+	allowed := domain.RoutingTarget{SourceIP: "10.8.1.1", SourcePort: 40000, IP: "10.0.1.50", Port: 443, Protocol: "tcp"}
+	if _, err := nf.GetNextHops(allowed, newMockAnalyzerContext(accountCtx)); err != nil {
+		t.Errorf("expected traffic from 10.8.1.1 to pass, got %v", err)
+	}
+}
+
+func TestNetworkFirewall_GetNextHops_StatefulAllowsReply(t *testing.T) {
+	nf := NewNetworkFirewall(&domain.NetworkFirewallData{
+		ID:    "nfw-123",
+		VPCID: "vpc-abc",
+		DefaultActions: domain.FirewallDefaultActions{
+			StatefulDefaultActions: []string{"aws:drop_established"},
+		},
+	}, "111122223333")
+
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.vpcs["vpc-abc"] = &domain.VPCData{ID: "vpc-abc", MainRouteTableID: "rtb-main"}
+	client.routeTables["rtb-main"] = &domain.RouteTableData{ID: "rtb-main", VPCID: "vpc-abc"}
+	accountCtx.addClient("111122223333", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	request := domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "10.0.2.20", Port: 443, Protocol: "tcp"}
+	if _, err := nf.GetNextHops(request, analyzerCtx); err == nil {
+		t.Error("expected new connection to hit the stateful default drop")
+	}
+
+	reply := domain.RoutingTarget{SourceIP: "10.0.2.20", SourcePort: 443, IP: "10.0.1.10", Port: 1024, PortTo: 65535, Protocol: "tcp", Reply: true}
+	if _, err := nf.GetNextHops(reply, analyzerCtx); err != nil {
+		t.Errorf("expected reply to pass the stateful engine, got %v", err)
+	}
+}
+
+func TestNetworkFirewall_StatefulRuleVariables(t *testing.T) {
+	newFirewall := func(groupVariables map[string][]string) *NetworkFirewall {
+		return NewNetworkFirewall(&domain.NetworkFirewallData{
+			ID:        "nfw-123",
+			VPCID:     "vpc-abc",
+			Variables: map[string][]string{"HOME_NET": {"10.0.0.0/16"}},
+			StatefulRuleGroups: []domain.StatefulRuleGroup{
+				{
+					Priority:  1,
+					Variables: groupVariables,
+					Rules: []domain.StatefulRule{
+						{Action: "DROP", Protocol: "TCP", Source: "$HOME_NET", Destination: "$EXTERNAL_NET", DestPort: "any"},
+						{Action: "DROP", Protocol: "TCP", Source: "any", Destination: "$BLOCKED_NETS", DestPort: "any"},
+					},
+				},
+			},
+		}, "111122223333")
+	}
+
+	tests := []struct {
+		name    string
+		vars    map[string][]string
+		flow    domain.RoutingTarget
+		allowed bool
+	}{
+		{
+			name:    "home to external is dropped",
+			vars:    map[string][]string{"BLOCKED_NETS": {"192.0.2.0/24"}},
+			flow:    domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "203.0.113.7", Port: 443, Protocol: "tcp"},
+			allowed: false,
+		},
+		{
+			name:    "home to home is not external",
+			vars:    map[string][]string{"BLOCKED_NETS": {"192.0.2.0/24"}},
+			flow:    domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "10.0.2.20", Port: 443, Protocol: "tcp"},
+			allowed: true,
+		},
+		{
+			name:    "external source does not match HOME_NET",
+			vars:    map[string][]string{"BLOCKED_NETS": {"192.0.2.0/24"}},
+			flow:    domain.RoutingTarget{SourceIP: "198.51.100.1", IP: "10.0.2.20", Port: 443, Protocol: "tcp"},
+			allowed: true,
+		},
+		{
+			name:    "group variable is resolved",
+			vars:    map[string][]string{"BLOCKED_NETS": {"10.0.2.0/24"}},
+			flow:    domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "10.0.2.20", Port: 443, Protocol: "tcp"},
+			allowed: false,
+		},
+		{
+			name:    "policy HOME_NET overrides the group's",
+			vars:    map[string][]string{"HOME_NET": {"0.0.0.0/0"}, "BLOCKED_NETS": {"192.0.2.0/24"}},
+			flow:    domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "203.0.113.7", Port: 443, Protocol: "tcp"},
+			allowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := newFirewall(tt.vars).evaluateStatefulRules(tt.flow)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.allowed != tt.allowed {
+				t.Errorf("expected allowed=%v, got %v (%s)", tt.allowed, result.allowed, result.reason)
+			}
+		})
+	}
+}
+
+func TestNetworkFirewall_UndefinedVariableIsUndecided(t *testing.T) {
+	nf := NewNetworkFirewall(&domain.NetworkFirewallData{
+		ID:    "nfw-123",
+		VPCID: "vpc-abc",
+		StatefulRuleGroups: []domain.StatefulRuleGroup{
+			{
+				Priority: 1,
+				Rules: []domain.StatefulRule{
+					{Action: "PASS", Protocol: "TCP", Source: "$PARTNER_NETS", Destination: "any", DestPort: "any"},
+				},
+			},
+		},
+	}, "111122223333")
+
+	flow := domain.RoutingTarget{SourceIP: "10.0.1.10", IP: "203.0.113.7", Port: 443, Protocol: "tcp"}
+	_, err := nf.GetNextHops(flow, nil)
+	if err == nil || domain.IsBlocking(err) {
+		t.Fatalf("expected an undecided, non-blocking error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "$PARTNER_NETS") {
+		t.Errorf("expected the error to name the variable, got %v", err)
+	}
+
+	result := nf.EvaluateWithDetails(flow, "outbound")
+	if result.Allowed || !strings.Contains(result.Reason, "cannot evaluate") {
+		t.Errorf("expected an undecided evaluation, got %+v", result)
+	}
+}
+
+func TestNetworkFirewall_MatchesAddressLists(t *testing.T) {
+	nf := NewNetworkFirewall(&domain.NetworkFirewallData{ID: "nfw-123"}, "111122223333")
+	variables := map[string][]string{"HOME_NET": {"10.0.0.0/16"}}
+
+	tests := []struct {
+		def  string
+		ip   string
+		want bool
+	}{
+		{"ANY", "192.0.2.1", true},
+		{"!$HOME_NET", "10.0.1.1", false},
+		{"!$HOME_NET", "192.0.2.1", true},
+		{"[$HOME_NET,!10.0.5.0/24]", "10.0.1.1", true},
+		{"[$HOME_NET,!10.0.5.0/24]", "10.0.5.1", false},
+		{"[192.0.2.0/24, [198.51.100.0/24]]", "198.51.100.9", true},
+		{"10.0.1.1", "10.0.1.1", true},
+	}
+	for _, tt := range tests {
+		got, err := nf.matchesAddress(tt.def, tt.ip, variables)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.def, err)
+		}
+		if got != tt.want {
+			t.Errorf("matchesAddress(%q, %s) = %v, want %v", tt.def, tt.ip, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -9,7 +10,14 @@ import (
 type NetworkInterface struct {
 	id        string
 	accountID string
-	privateIP string
+	region    string
+	vpcID     string
+	// privateIP and ipv6Addresses are learned on the first fetch when the ENI
+	// was created from an ID alone; mu guards them since branches may share
+	// the component.
+	mu            sync.RWMutex
+	privateIP     string
+	ipv6Addresses []string
 }
 
 func NewNetworkInterface(id, accountID string) *NetworkInterface {
@@ -19,8 +27,19 @@ func NewNetworkInterface(id, accountID string) *NetworkInterface {
 	}
 }
 
+func NewNetworkInterfaceFromData(data *domain.ENIData, accountID string) *NetworkInterface {
+	return &NetworkInterface{
+		id:            data.ID,
+		accountID:     accountID,
+		region:        data.Region,
+		vpcID:         data.VPCID,
+		privateIP:     data.PrivateIP,
+		ipv6Addresses: data.IPv6Addresses,
+	}
+}
+
 func (eni *NetworkInterface) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(eni.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(eni.accountID, eni.region)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +50,12 @@ func (eni *NetworkInterface) GetNextHops(dest domain.RoutingTarget, analyzerCtx
 	if err != nil {
 		return nil, err
 	}
-	eni.privateIP = eniData.PrivateIP
+	eni.mu.Lock()
+	if eni.privateIP == "" {
+		eni.privateIP = eniData.PrivateIP
+		eni.ipv6Addresses = eniData.IPv6Addresses
+	}
+	eni.mu.Unlock()
 
 	subnetData, err := client.GetSubnet(ctx, eniData.SubnetID)
 	if err != nil {
@@ -39,25 +63,43 @@ func (eni *NetworkInterface) GetNextHops(dest domain.RoutingTarget, analyzerCtx
 	}
 	subnet := NewSubnet(subnetData, eni.accountID)
 
-	var terminal domain.Component = subnet
-	for i := len(eniData.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, eniData.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		terminal = NewSecurityGroupWithNext(sgData, eni.accountID, terminal)
+	terminal, err := securityGroupFilter(ctx, client, eniData.SecurityGroups, eni.accountID, subnet)
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{terminal}, nil
 }
 
+func (eni *NetworkInterface) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(eni.accountID, eni.region)
+	if err != nil {
+		return nil, err
+	}
+
+	eniData, err := client.GetNetworkInterface(analyzerCtx.Context(), eni.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.Component{NewENIIngress(eniData, eni.accountID)}, nil
+}
+
 func (eni *NetworkInterface) GetRoutingTarget() domain.RoutingTarget {
+	eni.mu.RLock()
+	defer eni.mu.RUnlock()
 	if eni.privateIP == "" {
 		return domain.RoutingTarget{}
 	}
 	return domain.RoutingTarget{IP: eni.privateIP}
 }
 
+func (eni *NetworkInterface) GetIPv6Addresses() []string {
+	eni.mu.RLock()
+	defer eni.mu.RUnlock()
+	return eni.ipv6Addresses
+}
+
 func (eni *NetworkInterface) GetID() string {
 	return fmt.Sprintf("%s:%s", eni.accountID, eni.id)
 }
@@ -71,11 +113,11 @@ func (eni *NetworkInterface) GetComponentType() string {
 }
 
 func (eni *NetworkInterface) GetVPCID() string {
-	return ""
+	return eni.vpcID
 }
 
 func (eni *NetworkInterface) GetRegion() string {
-	return ""
+	return eni.region
 }
 
 func (eni *NetworkInterface) GetSubnetID() string {
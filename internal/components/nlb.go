@@ -19,7 +19,7 @@ func NewNLB(data *domain.NLBData, accountID string) *NLB {
 }
 
 func (nlb *NLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(nlb.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(nlb.accountID, nlb.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -35,12 +35,21 @@ func (nlb *NLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analyz
 		sgDatas = append(sgDatas, sgData)
 	}
 
+	tgARNs, err := listenerTargetGroups(nlb.GetID(), nlb.data.Listeners, nlb.data.ListenersLoaded, nlb.data.TargetGroupARNs, dest)
+	if err != nil {
+		return nil, err
+	}
+
 	var targets []domain.Component
-	for _, tgARN := range nlb.data.TargetGroupARNs {
+	for _, tgARN := range tgARNs {
 		tgData, err := client.GetTargetGroup(ctx, tgARN)
 		if err != nil {
 			return nil, err
 		}
+		if !tgData.PreserveClientIP && len(nlb.data.FrontendIPs) > 0 {
+			targets = append(targets, nlb.proxiedTargetGroups(tgData)...)
+			continue
+		}
 		targets = append(targets, NewTargetGroup(tgData, nlb.accountID))
 	}
 
@@ -57,16 +66,56 @@ func (nlb *NLB) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.Analyz
 
 	var components []domain.Component
 	for _, target := range targets {
-		var next domain.Component = target
-		for i := len(sgDatas) - 1; i >= 0; i-- {
-			next = NewSecurityGroupWithNext(sgDatas[i], nlb.accountID, next)
-		}
-		components = append(components, next)
+		components = append(components, attachedSecurityGroups(sgDatas, nlb.accountID, target))
 	}
 
 	return components, nil
 }
 
+// proxiedTargetGroups splits a group whose targets see the load balancer's own
+// address into one group per node address they receive traffic from. A target
+// hears from the node in its own Availability Zone, or from every node when
+// cross-zone load balancing is on or its zone is unknown, so its security
+// groups are checked against each of them.
+func (nlb *NLB) proxiedTargetGroups(tgData *domain.TargetGroupData) []domain.Component {
+	targetsByIP := make(map[string][]domain.TargetData)
+	for _, t := range tgData.Targets {
+		for _, ip := range nlb.nodeIPsFor(t) {
+			targetsByIP[ip] = append(targetsByIP[ip], t)
+		}
+	}
+
+	var groups []domain.Component
+	for _, ip := range nlb.data.FrontendIPs {
+		targets, ok := targetsByIP[ip]
+		if !ok {
+			continue
+		}
+		narrowed := *tgData
+		narrowed.Targets = targets
+		tg := NewTargetGroup(&narrowed, nlb.accountID)
+		tg.proxyIP = ip
+		groups = append(groups, tg)
+	}
+	if len(groups) == 0 {
+		narrowed := *tgData
+		narrowed.Targets = nil
+		groups = append(groups, NewTargetGroup(&narrowed, nlb.accountID))
+	}
+	return groups
+}
+
+// nodeIPsFor returns the addresses of the load balancer nodes that send to t.
+func (nlb *NLB) nodeIPsFor(t domain.TargetData) []string {
+	if nlb.data.CrossZone || len(nlb.data.FrontendIPsByAZ) == 0 || t.AvailabilityZone == "" || t.AvailabilityZone == "all" {
+		return nlb.data.FrontendIPs
+	}
+	if ip, ok := nlb.data.FrontendIPsByAZ[t.AvailabilityZone]; ok {
+		return []string{ip}
+	}
+	return nil
+}
+
 func (nlb *NLB) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -88,7 +137,7 @@ func (nlb *NLB) GetVPCID() string {
 }
 
 func (nlb *NLB) GetRegion() string {
-	return ""
+	return nlb.data.Region
 }
 
 func (nlb *NLB) GetSubnetID() string {
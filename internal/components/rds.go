@@ -19,7 +19,7 @@ func NewRDSInstance(data *domain.RDSInstanceData, accountID string) *RDSInstance
 }
 
 func (r *RDSInstance) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(r.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(r.accountID, r.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -38,19 +38,22 @@ func (r *RDSInstance) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 		return nil, err
 	}
 
-	var next domain.Component = NewSubnet(subnetData, r.accountID)
-
-	for i := len(r.data.SecurityGroups) - 1; i >= 0; i-- {
-		sgData, err := client.GetSecurityGroup(ctx, r.data.SecurityGroups[i])
-		if err != nil {
-			return nil, err
-		}
-		next = NewSecurityGroupWithNext(sgData, r.accountID, next)
+	next, err := securityGroupFilter(ctx, client, r.data.SecurityGroups, r.accountID, NewSubnet(subnetData, r.accountID))
+	if err != nil {
+		return nil, err
 	}
 
 	return []domain.Component{next}, nil
 }
 
+func (r *RDSInstance) GetIngressHops(analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	return ingressForIP(analyzerCtx, r.accountID, r.data.VPCID, &domain.ENIData{
+		PrivateIP:      r.data.PrivateIP,
+		SecurityGroups: r.data.SecurityGroups,
+		Region:         r.data.Region,
+	}, r.data.SubnetIDs)
+}
+
 func (r *RDSInstance) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{
 		IP:       r.data.PrivateIP,
@@ -59,6 +62,10 @@ func (r *RDSInstance) GetRoutingTarget() domain.RoutingTarget {
 	}
 }
 
+func (r *RDSInstance) GetIPv6Addresses() []string {
+	return r.data.IPv6Addresses
+}
+
 func (r *RDSInstance) GetID() string {
 	return fmt.Sprintf("%s:%s", r.accountID, r.data.ID)
 }
@@ -72,11 +79,11 @@ func (r *RDSInstance) GetComponentType() string {
 }
 
 func (r *RDSInstance) GetVPCID() string {
-	return ""
+	return r.data.VPCID
 }
 
 func (r *RDSInstance) GetRegion() string {
-	return ""
+	return r.data.Region
 }
 
 func (r *RDSInstance) GetSubnetID() string {
@@ -20,30 +20,29 @@ func NewRouteTable(data *domain.RouteTableData, accountID string) *RouteTable {
 }
 
 func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	var matchedRoute *domain.Route
-	longestPrefix := -1
-
-	for i, route := range rt.data.Routes {
-		matches, prefixLen := rt.routeMatches(route, dest.IP, analyzerCtx)
-		if matches && prefixLen > longestPrefix {
-			matchedRoute = &rt.data.Routes[i]
-			longestPrefix = prefixLen
-		}
+	matchedRoute, err := rt.matchRoute(dest.IP, analyzerCtx)
+	if err != nil {
+		return nil, err
 	}
-
 	if matchedRoute == nil {
 		return nil, &domain.BlockingError{
 			ComponentID: rt.GetID(),
 			Reason:      fmt.Sprintf("no route to %s", dest.IP),
 		}
 	}
+	if matchedRoute.State == "blackhole" {
+		return nil, &domain.BlockingError{
+			ComponentID: rt.GetID(),
+			Reason:      fmt.Sprintf("route to %s is a blackhole: %s %s no longer exists", dest.IP, matchedRoute.TargetType, matchedRoute.TargetID),
+		}
+	}
 
 	ctx := analyzerCtx.Context()
 	accountCtx := analyzerCtx.GetAccountContext()
 
 	if matchedRoute.TargetType == "local" {
 		if accountCtx != nil {
-			client, err := accountCtx.GetClient(rt.accountID)
+			client, err := accountCtx.GetClient(rt.accountID, rt.data.Region)
 			if err != nil {
 				return nil, err
 			}
@@ -51,7 +50,7 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 			if err != nil {
 				return nil, err
 			}
-			if vpc.CIDRBlock != "" && !IPMatchesCIDR(dest.IP, vpc.CIDRBlock) && (vpc.IPv6CIDRBlock == "" || !IPMatchesCIDR(dest.IP, vpc.IPv6CIDRBlock)) {
+			if vpc.CIDRBlock != "" && !vpcContainsIP(vpc, dest.IP) {
 				return nil, &domain.BlockingError{
 					ComponentID: rt.GetID(),
 					Reason:      fmt.Sprintf("local route but destination %s not in VPC %s CIDR", dest.IP, vpc.CIDRBlock),
@@ -60,7 +59,11 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 
 			if rp, ok := accountCtx.(domain.ResolverProvider); ok {
 				if resolver := rp.GetResolver(); resolver != nil {
-					if comp, err := resolver.ResolveByIP(ctx, rt.accountID, rt.data.VPCID, dest.IP); err == nil && comp != nil {
+					comp, err := resolver.ResolveByIP(ctx, rt.accountID, rt.data.Region, rt.data.VPCID, dest.IP)
+					if err != nil {
+						return nil, err
+					}
+					if comp != nil {
 						return []domain.Component{comp}, nil
 					}
 				}
@@ -69,7 +72,7 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 		return []domain.Component{NewIPTarget(&domain.IPTargetData{IP: dest.IP, Port: dest.Port}, rt.accountID)}, nil
 	}
 
-	client, err := accountCtx.GetClient(rt.accountID)
+	client, err := accountCtx.GetClient(rt.accountID, rt.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +85,13 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 		}
 		return []domain.Component{NewInternetGateway(igwData, rt.accountID)}, nil
 
+	case "egress-only-internet-gateway":
+		eigwData, err := client.GetEgressOnlyInternetGateway(ctx, matchedRoute.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Component{NewEgressOnlyInternetGateway(eigwData, rt.accountID)}, nil
+
 	case "nat-gateway":
 		natData, err := client.GetNATGateway(ctx, matchedRoute.TargetID)
 		if err != nil {
@@ -114,7 +124,7 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 						break
 					}
 				}
-				return []domain.Component{NewNetworkFirewallEndpoint(firewallData.ID, matchedRoute.TargetID, subnetID, rt.accountID)}, nil
+				return []domain.Component{NewNetworkFirewallEndpoint(firewallData.ID, matchedRoute.TargetID, subnetID, rt.accountID, rt.data.Region)}, nil
 			}
 		}
 		return []domain.Component{NewVPCEndpoint(endpointData, rt.accountID)}, nil
@@ -134,13 +144,14 @@ func (rt *RouteTable) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 		return []domain.Component{NewVirtualPrivateGateway(vgwData, rt.accountID)}, nil
 
 	case "network-interface":
-		return []domain.Component{NewNetworkInterface(matchedRoute.TargetID, rt.accountID)}, nil
+		eni := &domain.ENIData{ID: matchedRoute.TargetID, Region: rt.data.Region}
+		return []domain.Component{NewNetworkInterfaceFromData(eni, rt.accountID)}, nil
 
 	case "local-gateway":
-		return []domain.Component{NewLocalGateway(matchedRoute.TargetID, rt.accountID)}, nil
+		return []domain.Component{NewLocalGateway(matchedRoute.TargetID, rt.accountID, rt.data.Region)}, nil
 
 	case "carrier-gateway":
-		return []domain.Component{NewCarrierGateway(matchedRoute.TargetID, rt.accountID)}, nil
+		return []domain.Component{NewCarrierGateway(matchedRoute.TargetID, rt.accountID, rt.data.Region)}, nil
 
 	default:
 		return nil, &domain.BlockingError{
@@ -162,6 +173,10 @@ func (rt *RouteTable) GetAccountID() string {
 	return rt.accountID
 }
 
+func (rt *RouteTable) IsSourceAgnostic() bool {
+	return true
+}
+
 func (rt *RouteTable) GetComponentType() string {
 	return "RouteTable"
 }
@@ -171,7 +186,7 @@ func (rt *RouteTable) GetVPCID() string {
 }
 
 func (rt *RouteTable) GetRegion() string {
-	return ""
+	return rt.data.Region
 }
 
 func (rt *RouteTable) GetSubnetID() string {
@@ -182,42 +197,97 @@ func (rt *RouteTable) GetAvailabilityZone() string {
 	return ""
 }
 
-func (rt *RouteTable) routeMatches(route domain.Route, ip string, analyzerCtx domain.AnalyzerContext) (bool, int) {
+// matchRoute returns the most specific route for ip, or nil if none matches.
+// Routes to the same prefix are ranked by routePriority. A blackhole route is
+// matched like any other, since AWS drops the traffic rather than falling back
+// to a less specific route. A prefix list that cannot be read is an error, as
+// any of its entries could be the most specific match, and so is a tie between
+// routes when one of them has an unknown propagation source.
+func (rt *RouteTable) matchRoute(ip string, analyzerCtx domain.AnalyzerContext) (*domain.Route, error) {
+	var matchedRoute *domain.Route
+	longestPrefix := -1
+	tied := 0
+	var unranked error
+
+	for i, route := range rt.data.Routes {
+		matches, prefixLen, err := rt.routeMatches(route, ip, analyzerCtx)
+		if err != nil {
+			return nil, err
+		}
+		if !matches || prefixLen < longestPrefix {
+			continue
+		}
+		if prefixLen > longestPrefix {
+			tied, unranked = 0, nil
+		}
+		tied++
+		if unranked == nil {
+			unranked = route.PropagationErr
+		}
+		if prefixLen > longestPrefix || routePriority(route) < routePriority(*matchedRoute) {
+			matchedRoute = &rt.data.Routes[i]
+			longestPrefix = prefixLen
+		}
+	}
+	if tied > 1 && unranked != nil {
+		return nil, fmt.Errorf("cannot rank routes to %s: %w", ip, unranked)
+	}
+	return matchedRoute, nil
+}
+
+// routePriority ranks routes to the same prefix, lowest first, in the order
+// AWS prefers them: the local route, static routes, then routes propagated from
+// Direct Connect, from static VPN routes and from BGP over VPN.
+func routePriority(route domain.Route) int {
+	switch route.Origin {
+	case "local":
+		return 0
+	case "propagated":
+		switch route.PropagatedFrom {
+		case "direct-connect":
+			return 2
+		case "vpn-static":
+			return 3
+		default:
+			return 4
+		}
+	default:
+		return 1
+	}
+}
+
+func (rt *RouteTable) routeMatches(route domain.Route, ip string, analyzerCtx domain.AnalyzerContext) (bool, int, error) {
 	if route.DestinationCIDR != "" {
 		if IPMatchesCIDR(ip, route.DestinationCIDR) {
-			return true, route.PrefixLength
+			return true, route.PrefixLength, nil
 		}
 	}
 
 	if route.DestinationIPv6CIDR != "" {
 		if IPMatchesCIDR(ip, route.DestinationIPv6CIDR) {
 			prefixLen := getPrefixLength(route.DestinationIPv6CIDR)
-			return true, prefixLen
+			return true, prefixLen, nil
 		}
 	}
 
 	if route.DestinationPrefixListID != "" {
-		ok, plen, err := rt.matchesPrefixList(ip, route.DestinationPrefixListID, analyzerCtx)
-		if err != nil {
-			return false, -1
-		}
-		return ok, plen
+		return rt.matchesPrefixList(ip, route.DestinationPrefixListID, analyzerCtx)
 	}
 
-	return false, -1
+	return false, -1, nil
 }
 
 func (rt *RouteTable) matchesPrefixList(ip, plID string, analyzerCtx domain.AnalyzerContext) (bool, int, error) {
 	if analyzerCtx == nil {
 		return false, -1, nil
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(rt.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(rt.accountID, rt.data.Region)
 	if err != nil {
 		return false, -1, err
 	}
 	pl, err := client.GetManagedPrefixList(analyzerCtx.Context(), plID)
 	if err != nil {
-		return false, -1, err
+		return false, -1, fmt.Errorf("prefix list %s: %w", plID, err)
 	}
 
 	longestPrefix := -1
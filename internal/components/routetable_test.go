@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -144,6 +145,71 @@ func TestRouteTable_GetNextHops_InternetGateway(t *testing.T) {
 	}
 }
 
+func TestRouteTable_GetNextHops_RegionalGatewaysKeepRegion(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", newMockAWSClient())
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rt := NewRouteTable(&domain.RouteTableData{
+		ID:     "rtb-123",
+		VPCID:  "vpc-123",
+		Region: "us-west-2",
+		Routes: []domain.Route{
+			{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "local-gateway", TargetID: "lgw-123"},
+			{DestinationCIDR: "0.0.0.0/0", PrefixLength: 0, TargetType: "carrier-gateway", TargetID: "cagw-123"},
+		},
+	}, "111111111111")
+
+	for _, ip := range []string{"192.168.1.10", "8.8.8.8"} {
+		hops, err := rt.GetNextHops(domain.RoutingTarget{IP: ip, Port: 443, Protocol: "tcp"}, analyzerCtx)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", ip, err)
+		}
+		if len(hops) != 1 {
+			t.Fatalf("%s: expected 1 hop, got %d", ip, len(hops))
+		}
+		gateway, ok := hops[0].(domain.MetadataProvider)
+		if !ok {
+			t.Fatalf("%s: expected %T to report its region", ip, hops[0])
+		}
+		if region := gateway.GetRegion(); region != "us-west-2" {
+			t.Errorf("%s: expected %T in us-west-2, got %q", ip, hops[0], region)
+		}
+	}
+}
+
+func TestRouteTable_GetNextHops_EgressOnlyInternetGateway(t *testing.T) {
+	client := newMockAWSClient()
+	client.eigws["eigw-123"] = &domain.EgressOnlyInternetGatewayData{ID: "eigw-123", VPCID: "vpc-123"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rt := NewRouteTable(&domain.RouteTableData{
+		ID:    "rtb-123",
+		VPCID: "vpc-123",
+		Routes: []domain.Route{
+			{DestinationIPv6CIDR: "::/0", TargetType: "egress-only-internet-gateway", TargetID: "eigw-123"},
+		},
+	}, "111111111111")
+
+	dest := domain.RoutingTarget{IP: "2600:1f18:ffff::1", Port: 443, Protocol: "tcp"}
+	hops, err := rt.GetNextHops(dest, analyzerCtx)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+
+	if _, ok := hops[0].(*EgressOnlyInternetGateway); !ok {
+		t.Errorf("expected EgressOnlyInternetGateway, got %T", hops[0])
+	}
+}
+
 func TestRouteTable_GetNextHops_NATGateway(t *testing.T) {
 	client := newMockAWSClient()
 	client.natGateways["nat-123"] = &domain.NATGatewayData{ID: "nat-123", SubnetID: "subnet-123"}
@@ -683,6 +749,89 @@ func TestRouteTable_GetNextHops_VGW_LongestPrefixMatch(t *testing.T) {
 	}
 }
 
+func TestRouteTable_GetNextHops_Blackhole(t *testing.T) {
+	client := newMockAWSClient()
+	client.igws["igw-123"] = &domain.InternetGatewayData{ID: "igw-123", VPCID: "vpc-123"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rt := NewRouteTable(&domain.RouteTableData{
+		ID:    "rtb-123",
+		VPCID: "vpc-123",
+		Routes: []domain.Route{
+			{DestinationCIDR: "0.0.0.0/0", PrefixLength: 0, TargetType: "internet-gateway", TargetID: "igw-123", State: "active"},
+			{DestinationCIDR: "203.0.113.0/24", PrefixLength: 24, TargetType: "network-interface", TargetID: "eni-deleted", State: "blackhole"},
+		},
+	}, "111111111111")
+
+	dest := domain.RoutingTarget{IP: "203.0.113.10", Port: 443, Protocol: "tcp"}
+	_, err := rt.GetNextHops(dest, analyzerCtx)
+
+	var blockErr *domain.BlockingError
+	if !errors.As(err, &blockErr) {
+		t.Fatalf("expected BlockingError, got %v", err)
+	}
+	if !strings.Contains(blockErr.Reason, "blackhole") || !strings.Contains(blockErr.Reason, "eni-deleted") {
+		t.Errorf("expected reason to name the blackholed target, got %q", blockErr.Reason)
+	}
+}
+
+func TestRouteTable_MatchRoute_EqualPrefixPriority(t *testing.T) {
+	static := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "transit-gateway", TargetID: "tgw-static", Origin: "static"}
+	dx := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "vpn-gateway", TargetID: "vgw-dx", Origin: "propagated", PropagatedFrom: "direct-connect"}
+	vpnStatic := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "vpn-gateway", TargetID: "vgw-vpn-static", Origin: "propagated", PropagatedFrom: "vpn-static"}
+	vpnBGP := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "vpn-gateway", TargetID: "vgw-vpn-bgp", Origin: "propagated", PropagatedFrom: "vpn-bgp"}
+
+	tests := []struct {
+		name   string
+		routes []domain.Route
+		want   string
+	}{
+		{"static beats propagated", []domain.Route{vpnBGP, dx, static}, "tgw-static"},
+		{"direct connect beats vpn", []domain.Route{vpnBGP, vpnStatic, dx}, "vgw-dx"},
+		{"static vpn beats bgp vpn", []domain.Route{vpnBGP, vpnStatic}, "vgw-vpn-static"},
+		{"longer prefix beats priority", []domain.Route{static, {DestinationCIDR: "192.168.1.0/24", PrefixLength: 24, TargetType: "vpn-gateway", TargetID: "vgw-specific", Origin: "propagated", PropagatedFrom: "vpn-bgp"}}, "vgw-specific"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewRouteTable(&domain.RouteTableData{ID: "rtb-123", VPCID: "vpc-123", Routes: tt.routes}, "111111111111")
+			route, err := rt.matchRoute("192.168.1.10", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if route == nil {
+				t.Fatal("expected a route to match")
+			}
+			if route.TargetID != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, route.TargetID)
+			}
+		})
+	}
+}
+
+func TestRouteTable_MatchRoute_UnknownPropagationSource(t *testing.T) {
+	throttled := &domain.TransientError{Err: errors.New("throttled")}
+	static := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "transit-gateway", TargetID: "tgw-static", Origin: "static"}
+	unknown := domain.Route{DestinationCIDR: "192.168.0.0/16", PrefixLength: 16, TargetType: "vpn-gateway", TargetID: "vgw-unknown", Origin: "propagated", PropagationErr: throttled}
+
+	rt := NewRouteTable(&domain.RouteTableData{ID: "rtb-123", VPCID: "vpc-123", Routes: []domain.Route{static, unknown}}, "111111111111")
+	if _, err := rt.matchRoute("192.168.1.10", nil); !errors.Is(err, throttled) {
+		t.Errorf("expected a tie with an unranked route to be undecided, got %v", err)
+	}
+
+	rt = NewRouteTable(&domain.RouteTableData{ID: "rtb-123", VPCID: "vpc-123", Routes: []domain.Route{unknown}}, "111111111111")
+	route, err := rt.matchRoute("192.168.1.10", nil)
+	if err != nil {
+		t.Fatalf("expected a sole route to match without ranking, got %v", err)
+	}
+	if route == nil || route.TargetID != "vgw-unknown" {
+		t.Errorf("expected vgw-unknown, got %+v", route)
+	}
+}
+
 func TestRouteTable_GetNextHops_PrefixList_Allowed(t *testing.T) {
 	client := newMockAWSClient()
 	client.igws["igw-123"] = &domain.InternetGatewayData{ID: "igw-123", VPCID: "vpc-123"}
@@ -836,6 +985,9 @@ func TestRouteTable_GetNextHops_PrefixList_NotFound(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for prefix list not found")
 	}
+	if domain.IsBlocking(err) {
+		t.Errorf("expected an unreadable prefix list to leave the route undecided, got block: %v", err)
+	}
 }
 
 func TestRouteTable_GetNextHops_PrefixList_MultipleEntries(t *testing.T) {
@@ -934,11 +1086,39 @@ func TestRouteTable_GetNextHops_MixedCIDRAndPrefixList(t *testing.T) {
 	}
 }
 
+func TestRouteTable_GetNextHops_IPv6_LocalRouteSecondaryCIDR(t *testing.T) {
+	client := newMockAWSClient()
+	client.vpcs["vpc-123"] = &domain.VPCData{
+		ID:             "vpc-123",
+		CIDRBlock:      "10.0.0.0/16",
+		IPv6CIDRBlocks: []string{"2001:db8::/56", "2001:db8:0:100::/56"},
+	}
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rt := NewRouteTable(&domain.RouteTableData{
+		ID:    "rtb-123",
+		VPCID: "vpc-123",
+		Routes: []domain.Route{
+			{DestinationIPv6CIDR: "2001:db8:0:100::/56", TargetType: "local", TargetID: "local"},
+		},
+	}, "111111111111")
+
+	hops, err := rt.GetNextHops(domain.RoutingTarget{IP: "2001:db8:0:101::5", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+}
+
 func TestRouteTable_GetNextHops_IPv6_LocalRoute(t *testing.T) {
 	client := newMockAWSClient()
 	client.vpcs["vpc-123"] = &domain.VPCData{
-		ID:            "vpc-123",
-		IPv6CIDRBlock: "2001:db8::/32",
+		ID:             "vpc-123",
+		IPv6CIDRBlocks: []string{"2001:db8::/32"},
 	}
 	accountCtx := newMockAccountContext()
 	accountCtx.addClient("111111111111", client)
@@ -1112,3 +1292,40 @@ func TestRouteTable_GetNextHops_IPv6_LongestPrefixMatch(t *testing.T) {
 		t.Errorf("expected igw-specific (longer prefix), got %s", igw.data.ID)
 	}
 }
+
+func TestRouteTable_GetNextHops_StaysInRouteTableRegion(t *testing.T) {
+	client := newMockAWSClient()
+	client.natGateways["nat-eu"] = &domain.NATGatewayData{ID: "nat-eu", SubnetID: "subnet-eu", Region: "eu-west-1"}
+	accountCtx := newMockAccountContext()
+	accountCtx.addRegionalClient("111111111111", "eu-west-1", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rt := NewRouteTable(&domain.RouteTableData{
+		ID:     "rtb-eu",
+		VPCID:  "vpc-eu",
+		Region: "eu-west-1",
+		Routes: []domain.Route{
+			{DestinationCIDR: "0.0.0.0/0", PrefixLength: 0, TargetType: "nat-gateway", TargetID: "nat-eu"},
+		},
+	}, "111111111111")
+
+	hops, err := rt.GetNextHops(domain.RoutingTarget{IP: "8.8.8.8", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	nat, ok := hops[0].(*NATGateway)
+	if !ok {
+		t.Fatalf("expected NATGateway, got %T", hops[0])
+	}
+	if nat.GetRegion() != "eu-west-1" {
+		t.Errorf("expected NAT gateway in eu-west-1, got %q", nat.GetRegion())
+	}
+
+	hop := domain.HopFromComponent(nat, domain.HopLineage{}, domain.HopActionRouted, "")
+	if hop.Region != "eu-west-1" {
+		t.Errorf("expected hop region eu-west-1, got %q", hop.Region)
+	}
+}
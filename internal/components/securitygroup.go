@@ -1,7 +1,11 @@
 package components
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -28,13 +32,16 @@ func NewSecurityGroupWithNext(data *domain.SecurityGroupData, accountID string,
 }
 
 func (sg *SecurityGroup) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	if dest.Direction == "inbound" {
-		if err := sg.EvaluateInbound(dest, analyzerCtx); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := sg.EvaluateOutbound(dest, analyzerCtx); err != nil {
-			return nil, err
+	// Security groups track connections, so replies skip rule evaluation.
+	if !dest.Reply {
+		if dest.Direction == "inbound" {
+			if err := sg.EvaluateInbound(dest, analyzerCtx); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := sg.EvaluateOutbound(dest, analyzerCtx); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -49,107 +56,135 @@ func (sg *SecurityGroup) IsFilter() bool {
 }
 
 func (sg *SecurityGroup) EvaluateOutbound(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) error {
+	var lookupErr error
 	for _, rule := range sg.data.OutboundRules {
-		if sg.ruleAllows(rule, dest, analyzerCtx) {
+		allowed, err := sg.ruleAllows(rule, dest.IP, dest, analyzerCtx)
+		if allowed {
 			return nil
 		}
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+		}
+	}
+	if lookupErr != nil {
+		return lookupErr
 	}
 	return &domain.BlockingError{
 		ComponentID: sg.GetID(),
-		Reason:      fmt.Sprintf("no outbound rule allows %s:%d/%s", dest.IP, dest.Port, dest.Protocol),
+		Reason:      fmt.Sprintf("no outbound rule allows %s:%s/%s", dest.IP, formatPorts(dest), dest.Protocol),
 	}
 }
 
 func (sg *SecurityGroup) EvaluateInbound(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) error {
+	sourceIP := inboundSourceIP(dest)
+	if sourceIP == "" {
+		return unknownSourceError(sg.GetID())
+	}
+	var lookupErr error
 	for _, rule := range sg.data.InboundRules {
-		if sg.ruleAllows(rule, dest, analyzerCtx) {
+		allowed, err := sg.ruleAllows(rule, sourceIP, dest, analyzerCtx)
+		if allowed {
 			return nil
 		}
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+		}
+	}
+	if lookupErr != nil {
+		return lookupErr
 	}
 	return &domain.BlockingError{
 		ComponentID: sg.GetID(),
-		Reason:      fmt.Sprintf("no inbound rule allows %s:%d/%s", dest.IP, dest.Port, dest.Protocol),
+		Reason:      fmt.Sprintf("no inbound rule allows %s to port %s/%s", sourceIP, formatPorts(dest), dest.Protocol),
 	}
 }
 
-func (sg *SecurityGroup) ruleAllows(rule domain.SecurityGroupRule, dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) bool {
+// ruleAllows reports whether rule admits ip. A failed prefix list or
+// referenced group lookup is returned only when nothing else in the rule
+// matched, since the rule's verdict then depends on the data that could
+// not be read.
+func (sg *SecurityGroup) ruleAllows(rule domain.SecurityGroupRule, ip string, dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) (bool, error) {
 	if !protocolMatches(rule.Protocol, dest.Protocol) {
-		return false
+		return false, nil
 	}
-	if !portInRange(dest.Port, rule.FromPort, rule.ToPort) {
-		return false
+	if !flowPortsInRange(dest, rule.FromPort, rule.ToPort) {
+		return false, nil
 	}
 
 	for _, cidr := range rule.CIDRBlocks {
-		if IPMatchesCIDR(dest.IP, cidr) {
-			return true
+		if IPMatchesCIDR(ip, cidr) {
+			return true, nil
 		}
 	}
 
 	for _, cidr := range rule.IPv6CIDRBlocks {
-		if IPMatchesCIDR(dest.IP, cidr) {
-			return true
+		if IPMatchesCIDR(ip, cidr) {
+			return true, nil
 		}
 	}
 
+	var lookupErr error
 	for _, refSGID := range rule.ReferencedSecurityGroups {
-		if sg.ipBelongsToSecurityGroup(dest.IP, refSGID, analyzerCtx) {
-			return true
+		member, err := sg.ipBelongsToSecurityGroup(ip, refSGID, analyzerCtx)
+		if member {
+			return true, nil
+		}
+		if err != nil && lookupErr == nil {
+			lookupErr = err
 		}
 	}
 
 	for _, plID := range rule.PrefixListIDs {
-		if sg.ipMatchesPrefixList(dest.IP, plID, analyzerCtx) {
-			return true
+		listed, err := sg.ipMatchesPrefixList(ip, plID, analyzerCtx)
+		if listed {
+			return true, nil
+		}
+		if err != nil && lookupErr == nil {
+			lookupErr = err
 		}
 	}
 
-	return false
+	return false, lookupErr
 }
 
-func (sg *SecurityGroup) ipMatchesPrefixList(ip, plID string, analyzerCtx domain.AnalyzerContext) bool {
+func (sg *SecurityGroup) ipMatchesPrefixList(ip, plID string, analyzerCtx domain.AnalyzerContext) (bool, error) {
 	if analyzerCtx == nil {
-		return false
+		return false, nil
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(sg.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(sg.accountID, sg.data.Region)
 	if err != nil {
-		return false
+		return false, err
 	}
 	pl, err := client.GetManagedPrefixList(analyzerCtx.Context(), plID)
 	if err != nil {
-		return false
+		return false, fmt.Errorf("prefix list %s: %w", plID, err)
 	}
 	for _, entry := range pl.Entries {
 		if IPMatchesCIDR(ip, entry.CIDR) {
-			return true
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
-func (sg *SecurityGroup) ipBelongsToSecurityGroup(ip, sgID string, analyzerCtx domain.AnalyzerContext) bool {
+func (sg *SecurityGroup) ipBelongsToSecurityGroup(ip, sgID string, analyzerCtx domain.AnalyzerContext) (bool, error) {
 	if analyzerCtx == nil {
-		return false
+		return false, nil
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(sg.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(sg.accountID, sg.data.Region)
 	if err != nil {
-		return false
+		return false, err
 	}
 	enis, err := client.GetENIsBySecurityGroup(analyzerCtx.Context(), sgID)
 	if err != nil {
-		return false
+		return false, fmt.Errorf("members of %s: %w", sgID, err)
 	}
 	for _, eni := range enis {
-		if eni.PrivateIP == ip {
-			return true
-		}
-		for _, privateIP := range eni.PrivateIPs {
-			if privateIP == ip {
-				return true
-			}
+		if slices.Contains(interfaceAddresses(&eni), ip) {
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
 func (sg *SecurityGroup) GetRoutingTarget() domain.RoutingTarget {
@@ -160,6 +195,10 @@ func (sg *SecurityGroup) GetID() string {
 	return fmt.Sprintf("%s:%s", sg.accountID, sg.data.ID)
 }
 
+func (sg *SecurityGroup) GetStateKey() string {
+	return sg.GetID() + ">" + stateKey(sg.next)
+}
+
 func (sg *SecurityGroup) GetAccountID() string {
 	return sg.accountID
 }
@@ -173,7 +212,7 @@ func (sg *SecurityGroup) GetVPCID() string {
 }
 
 func (sg *SecurityGroup) GetRegion() string {
-	return ""
+	return sg.data.Region
 }
 
 func (sg *SecurityGroup) GetSubnetID() string {
@@ -185,11 +224,23 @@ func (sg *SecurityGroup) GetAvailabilityZone() string {
 }
 
 func (sg *SecurityGroup) EvaluateWithDetails(target domain.RoutingTarget, direction string) domain.EvaluationResult {
+	if target.Reply {
+		return domain.EvaluationResult{
+			Allowed: true,
+			Reason:  "reply to tracked connection",
+		}
+	}
+
 	var rules []domain.SecurityGroupRule
 	ruleType := "outbound"
+	peerIP := target.IP
 	if direction == "inbound" {
 		rules = sg.data.InboundRules
 		ruleType = "inbound"
+		peerIP = inboundSourceIP(target)
+		if peerIP == "" {
+			return domain.EvaluationResult{Reason: "source address unknown"}
+		}
 	} else {
 		rules = sg.data.OutboundRules
 	}
@@ -212,7 +263,7 @@ func (sg *SecurityGroup) EvaluateWithDetails(target domain.RoutingTarget, direct
 		}
 
 		protocolMatch := protocolMatches(rule.Protocol, target.Protocol)
-		portMatch := portInRange(target.Port, rule.FromPort, rule.ToPort)
+		portMatch := flowPortsInRange(target, rule.FromPort, rule.ToPort)
 
 		if !protocolMatch {
 			eval.Matched = false
@@ -223,14 +274,14 @@ func (sg *SecurityGroup) EvaluateWithDetails(target domain.RoutingTarget, direct
 
 		if !portMatch {
 			eval.Matched = false
-			eval.Reason = fmt.Sprintf("port %d not in range %d-%d", target.Port, rule.FromPort, rule.ToPort)
+			eval.Reason = fmt.Sprintf("port %s not in range %d-%d", formatPorts(target), rule.FromPort, rule.ToPort)
 			evaluations = append(evaluations, eval)
 			continue
 		}
 
 		cidrMatch := false
 		for _, cidr := range rule.CIDRBlocks {
-			if IPMatchesCIDR(target.IP, cidr) {
+			if IPMatchesCIDR(peerIP, cidr) {
 				cidrMatch = true
 				eval.DestCIDR = cidr
 				break
@@ -238,7 +289,7 @@ func (sg *SecurityGroup) EvaluateWithDetails(target domain.RoutingTarget, direct
 		}
 		if !cidrMatch {
 			for _, cidr := range rule.IPv6CIDRBlocks {
-				if IPMatchesCIDR(target.IP, cidr) {
+				if IPMatchesCIDR(peerIP, cidr) {
 					cidrMatch = true
 					eval.DestCIDR = cidr
 					break
@@ -258,13 +309,181 @@ func (sg *SecurityGroup) EvaluateWithDetails(target domain.RoutingTarget, direct
 		}
 
 		eval.Matched = false
-		eval.Reason = fmt.Sprintf("IP %s not in any CIDR", target.IP)
+		eval.Reason = fmt.Sprintf("IP %s not in any CIDR", peerIP)
 		evaluations = append(evaluations, eval)
 	}
 
 	return domain.EvaluationResult{
 		Allowed:     false,
-		Reason:      fmt.Sprintf("no %s rule allows %s:%d/%s", ruleType, target.IP, target.Port, target.Protocol),
+		Reason:      fmt.Sprintf("no %s rule allows %s:%s/%s", ruleType, peerIP, formatPorts(target), target.Protocol),
+		Evaluations: evaluations,
+	}
+}
+
+// SecurityGroupSet is the security groups attached to one interface, checked
+// as a single filter. AWS combines the rules of every attached group, so a flow
+// gets through when any of them allows it.
+type SecurityGroupSet struct {
+	groups    []*SecurityGroup
+	accountID string
+	next      domain.Component
+}
+
+// attachedSecurityGroups returns the filter the groups in datas apply to an
+// interface's traffic before it continues to next: next itself when there are
+// no groups, the group when there is one, and their combined rules otherwise.
+func attachedSecurityGroups(datas []*domain.SecurityGroupData, accountID string, next domain.Component) domain.Component {
+	switch len(datas) {
+	case 0:
+		return next
+	case 1:
+		return NewSecurityGroupWithNext(datas[0], accountID, next)
+	}
+	set := &SecurityGroupSet{accountID: accountID, next: next}
+	for _, data := range datas {
+		set.groups = append(set.groups, NewSecurityGroup(data, accountID))
+	}
+	return set
+}
+
+// securityGroupFilter looks up the groups in sgIDs and returns the filter they
+// apply ahead of next, as attachedSecurityGroups does.
+func securityGroupFilter(ctx context.Context, client domain.AWSClient, sgIDs []string, accountID string, next domain.Component) (domain.Component, error) {
+	var datas []*domain.SecurityGroupData
+	for _, sgID := range sgIDs {
+		sgData, err := client.GetSecurityGroup(ctx, sgID)
+		if err != nil {
+			return nil, err
+		}
+		datas = append(datas, sgData)
+	}
+	return attachedSecurityGroups(datas, accountID, next), nil
+}
+
+func (s *SecurityGroupSet) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	// Security groups track connections, so replies skip rule evaluation.
+	if !dest.Reply {
+		var err error
+		if dest.Direction == "inbound" {
+			err = s.EvaluateInbound(dest, analyzerCtx)
+		} else {
+			err = s.EvaluateOutbound(dest, analyzerCtx)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.next != nil {
+		return []domain.Component{s.next}, nil
+	}
+	return []domain.Component{}, nil
+}
+
+func (s *SecurityGroupSet) EvaluateInbound(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) error {
+	return s.evaluate(func(sg *SecurityGroup) error {
+		return sg.EvaluateInbound(dest, analyzerCtx)
+	})
+}
+
+func (s *SecurityGroupSet) EvaluateOutbound(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) error {
+	return s.evaluate(func(sg *SecurityGroup) error {
+		return sg.EvaluateOutbound(dest, analyzerCtx)
+	})
+}
+
+// evaluate allows the flow when any group does. Otherwise a group that could
+// not be evaluated leaves the flow undecided, and it is blocked only when
+// every group rules it out.
+func (s *SecurityGroupSet) evaluate(evaluate func(*SecurityGroup) error) error {
+	var undecided error
+	var reasons []string
+	for _, sg := range s.groups {
+		err := evaluate(sg)
+		if err == nil {
+			return nil
+		}
+		var blockErr *domain.BlockingError
+		if errors.As(err, &blockErr) {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", sg.data.ID, blockErr.Reason))
+		} else if undecided == nil {
+			undecided = err
+		}
+	}
+	if undecided != nil {
+		return undecided
+	}
+	return &domain.BlockingError{
+		ComponentID: s.GetID(),
+		Reason:      strings.Join(reasons, "; "),
+	}
+}
+
+func (s *SecurityGroupSet) IsFilter() bool {
+	return true
+}
+
+func (s *SecurityGroupSet) EvaluateWithDetails(target domain.RoutingTarget, direction string) domain.EvaluationResult {
+	var evaluations []domain.RuleEvaluation
+	var reasons []string
+	for _, sg := range s.groups {
+		result := sg.EvaluateWithDetails(target, direction)
+		for _, eval := range result.Evaluations {
+			eval.RuleID = sg.data.ID + ":" + eval.RuleID
+			evaluations = append(evaluations, eval)
+		}
+		if result.Allowed {
+			return domain.EvaluationResult{
+				Allowed:     true,
+				Reason:      fmt.Sprintf("%s %s", sg.data.ID, result.Reason),
+				Evaluations: evaluations,
+			}
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", sg.data.ID, result.Reason))
+	}
+	return domain.EvaluationResult{
+		Allowed:     false,
+		Reason:      strings.Join(reasons, "; "),
 		Evaluations: evaluations,
 	}
 }
+
+func (s *SecurityGroupSet) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (s *SecurityGroupSet) GetID() string {
+	ids := make([]string, len(s.groups))
+	for i, sg := range s.groups {
+		ids[i] = sg.data.ID
+	}
+	return fmt.Sprintf("%s:%s", s.accountID, strings.Join(ids, "+"))
+}
+
+func (s *SecurityGroupSet) GetStateKey() string {
+	return s.GetID() + ">" + stateKey(s.next)
+}
+
+func (s *SecurityGroupSet) GetAccountID() string {
+	return s.accountID
+}
+
+func (s *SecurityGroupSet) GetComponentType() string {
+	return "SecurityGroup"
+}
+
+func (s *SecurityGroupSet) GetVPCID() string {
+	return s.groups[0].data.VPCID
+}
+
+func (s *SecurityGroupSet) GetRegion() string {
+	return s.groups[0].data.Region
+}
+
+func (s *SecurityGroupSet) GetSubnetID() string {
+	return ""
+}
+
+func (s *SecurityGroupSet) GetAvailabilityZone() string {
+	return ""
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -491,6 +492,36 @@ func TestSecurityGroup_GetNextHops_ReferencedSG_Allowed(t *testing.T) {
 	}
 }
 
+func TestSecurityGroup_GetNextHops_ReferencedSG_IPv6Member(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, ReferencedSecurityGroups: []string{"sg-app"}},
+		},
+	}, "111122223333")
+
+	mockClient := newMockAWSClient()
+	mockClient.enisBySG["sg-app"] = []domain.ENIData{
+		{ID: "eni-app", PrivateIP: "10.0.1.50", IPv6Addresses: []string{"2600:1f18:abcd::50"}},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111122223333", mockClient)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	member := domain.RoutingTarget{SourceIP: "2600:1f18:abcd::50", IP: "2600:1f18:abcd::100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	if _, err := sg.GetNextHops(member, analyzerCtx); err != nil {
+		t.Errorf("expected the member's IPv6 address to be admitted, got %v", err)
+	}
+
+	stranger := member
+	stranger.SourceIP = "2600:1f18:abcd::99"
+	if _, err := sg.GetNextHops(stranger, analyzerCtx); !domain.IsBlocking(err) {
+		t.Errorf("expected an IPv6 address outside the group to be blocked, got %v", err)
+	}
+}
+
 func TestSecurityGroup_GetNextHops_ReferencedSG_Blocked(t *testing.T) {
 	rule := domain.SecurityGroupRule{
 		Protocol:                 "tcp",
@@ -893,3 +924,132 @@ func TestSecurityGroup_GetNextHops_MixedIPv4AndIPv6(t *testing.T) {
 		})
 	}
 }
+
+func TestSecurityGroup_GetNextHops_FlowPortRange(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-123",
+		VPCID: "vpc-abc",
+		OutboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 8000, ToPort: 9000, CIDRBlocks: []string{"0.0.0.0/0"}},
+		},
+	}, "111122223333")
+
+	tests := []struct {
+		name    string
+		from    int
+		to      int
+		allowed bool
+	}{
+		{"range inside rule", 8080, 8090, true},
+		{"range matches rule", 8000, 9000, true},
+		{"range exceeds rule", 8500, 9500, false},
+		{"range below rule", 7000, 7999, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := domain.RoutingTarget{IP: "10.0.1.1", Port: tt.from, PortTo: tt.to, Protocol: "tcp"}
+			_, err := sg.GetNextHops(dest, nil)
+			if tt.allowed && err != nil {
+				t.Errorf("expected allowed, got error: %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Error("expected blocked, got allowed")
+			}
+		})
+	}
+}
+
+func TestSecurityGroup_GetNextHops_PortZeroIsNotWildcard(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-123",
+		VPCID: "vpc-abc",
+		OutboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 443, ToPort: 443, CIDRBlocks: []string{"0.0.0.0/0"}},
+		},
+	}, "111122223333")
+
+	flow := domain.FlowSpec{Protocol: "tcp", FromPort: 5432}
+	dest := flow.Apply(domain.RoutingTarget{IP: "10.0.1.1", Port: 0, Protocol: "tcp"})
+
+	if _, err := sg.GetNextHops(dest, nil); err == nil {
+		t.Error("expected 5432/tcp to be blocked by a 443-only rule")
+	}
+}
+
+func TestSecurityGroup_EvaluateInbound_MatchesSourceIP(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.1.0/24"}},
+		},
+	}, "111122223333")
+
+	allowed := domain.RoutingTarget{SourceIP: "10.0.1.15", IP: "10.0.9.20", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	if _, err := sg.GetNextHops(allowed, nil); err != nil {
+		t.Errorf("expected source in 10.0.1.0/24 to be allowed, got %v", err)
+	}
+
+	denied := domain.RoutingTarget{SourceIP: "10.0.2.15", IP: "10.0.1.20", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	_, err := sg.GetNextHops(denied, nil)
+	if err == nil {
+		t.Fatal("expected source outside 10.0.1.0/24 to be blocked even though destination IP matches")
+	}
+	if !strings.Contains(err.Error(), "10.0.2.15") {
+		t.Errorf("expected source IP in reason, got %v", err)
+	}
+}
+
+func TestSecurityGroup_EvaluateInbound_UnknownSourceIsUndecided(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.1.0/24"}},
+		},
+	}, "111122223333")
+
+	dest := domain.RoutingTarget{IP: "10.0.1.20", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	_, err := sg.GetNextHops(dest, nil)
+	if err == nil {
+		t.Fatal("expected an error when the source is unknown, not a match on the destination address")
+	}
+	if domain.IsBlocking(err) {
+		t.Errorf("expected an unknown source to be undecided, got blocking error %v", err)
+	}
+}
+
+func TestSecurityGroup_EvaluateWithDetails_InboundUsesSourceIP(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-abc",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.1.0/24"}},
+		},
+	}, "111122223333")
+
+	target := domain.RoutingTarget{SourceIP: "10.0.1.15", IP: "10.0.9.20", Port: 5432, Protocol: "tcp"}
+	result := sg.EvaluateWithDetails(target, "inbound")
+
+	if !result.Allowed {
+		t.Errorf("expected allowed, got %s", result.Reason)
+	}
+}
+
+func TestSecurityGroup_GetNextHops_ReplyIsTracked(t *testing.T) {
+	sg := NewSecurityGroup(&domain.SecurityGroupData{
+		ID:    "sg-db",
+		VPCID: "vpc-abc",
+	}, "111122223333")
+
+	reply := domain.RoutingTarget{SourceIP: "10.0.2.20", SourcePort: 5432, IP: "10.0.1.10", Port: 1024, PortTo: 65535, Protocol: "tcp", Direction: "outbound", Reply: true}
+	if _, err := sg.GetNextHops(reply, nil); err != nil {
+		t.Errorf("expected reply to be allowed by connection tracking, got %v", err)
+	}
+
+	reply.Reply = false
+	if _, err := sg.GetNextHops(reply, nil); err == nil {
+		t.Error("expected a new connection to be blocked without rules")
+	}
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eleven-am/argus/internal/domain"
@@ -82,21 +83,18 @@ func TestEC2Instance_GetNextHops(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if len(hops) != 1 {
-		t.Fatalf("expected 1 hop (chained SG -> SG -> Subnet), got %d", len(hops))
+		t.Fatalf("expected 1 hop (combined SGs -> Subnet), got %d", len(hops))
 	}
 
-	sg1, ok := hops[0].(*SecurityGroup)
+	set, ok := hops[0].(*SecurityGroupSet)
 	if !ok {
-		t.Fatalf("expected SecurityGroup, got %T", hops[0])
+		t.Fatalf("expected SecurityGroupSet, got %T", hops[0])
 	}
-
-	sg2, ok := sg1.next.(*SecurityGroup)
-	if !ok {
-		t.Fatalf("expected second SecurityGroup, got %T", sg1.next)
+	if len(set.groups) != 2 || set.groups[0].data.ID != "sg-123" || set.groups[1].data.ID != "sg-456" {
+		t.Errorf("expected sg-123 and sg-456 in the set, got %s", set.GetID())
 	}
-
-	if _, ok := sg2.next.(*Subnet); !ok {
-		t.Errorf("expected Subnet as final component, got %T", sg2.next)
+	if _, ok := set.next.(*Subnet); !ok {
+		t.Errorf("expected Subnet as final component, got %T", set.next)
 	}
 }
 
@@ -304,6 +302,38 @@ func TestLambdaFunction_GetRoutingTarget(t *testing.T) {
 	}
 }
 
+func TestLambdaFunction_GetIPv6Addresses(t *testing.T) {
+	lambda := NewLambdaFunction(&domain.LambdaFunctionData{
+		Name:        "my-function",
+		VPCID:       "vpc-1",
+		SubnetCIDRs: []string{"10.0.1.0/24"},
+		DualStack:   true,
+	}, "111111111111")
+
+	if addresses := lambda.GetIPv6Addresses(); len(addresses) != 0 {
+		t.Errorf("expected no IPv6 address without interface data, got %v", addresses)
+	}
+
+	lambda.data.ENIIPs = []string{"10.0.1.57", "2600:1f18:0:1::57"}
+	addresses := lambda.GetIPv6Addresses()
+	if len(addresses) != 1 || addresses[0] != "2600:1f18:0:1::57" {
+		t.Errorf("expected the interface's IPv6 address, got %v", addresses)
+	}
+	if ip := lambda.GetRoutingTarget().IP; ip != "10.0.1.57" {
+		t.Errorf("expected the interface's IPv4 address as routing target, got %s", ip)
+	}
+}
+
+func TestEKSPod_GetIPv6Addresses(t *testing.T) {
+	if addresses := NewEKSPod(&domain.EKSPodData{PodIP: "10.0.1.20"}, "111111111111").GetIPv6Addresses(); len(addresses) != 0 {
+		t.Errorf("expected no IPv6 address in an IPv4 cluster, got %v", addresses)
+	}
+	addresses := NewEKSPod(&domain.EKSPodData{PodIP: "2600:1f18::20"}, "111111111111").GetIPv6Addresses()
+	if len(addresses) != 1 || addresses[0] != "2600:1f18::20" {
+		t.Errorf("expected the pod's IPv6 address, got %v", addresses)
+	}
+}
+
 func TestLambdaFunction_GetNextHops_VPCAttached(t *testing.T) {
 	client := newMockAWSClient()
 	client.securityGroups["sg-lambda"] = &domain.SecurityGroupData{
@@ -473,3 +503,260 @@ func TestLambdaFunction_GetNextHops_ClientError(t *testing.T) {
 		t.Fatal("expected error when client not found")
 	}
 }
+
+func TestENIIngress_AnyAttachedGroupAllows(t *testing.T) {
+	client := newMockAWSClient()
+	client.securityGroups["sg-ssh"] = &domain.SecurityGroupData{
+		ID:           "sg-ssh",
+		VPCID:        "vpc-1",
+		InboundRules: []domain.SecurityGroupRule{{Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRBlocks: []string{"10.0.0.0/8"}}},
+	}
+	client.securityGroups["sg-db"] = &domain.SecurityGroupData{
+		ID:           "sg-db",
+		VPCID:        "vpc-1",
+		InboundRules: []domain.SecurityGroupRule{{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.0.0/8"}}},
+	}
+	client.subnets["subnet-1"] = &domain.SubnetData{ID: "subnet-1", VPCID: "vpc-1", CIDRBlock: "10.0.1.0/24", NaclID: "nacl-1"}
+	client.nacls["nacl-1"] = &domain.NACLData{
+		ID:           "nacl-1",
+		VPCID:        "vpc-1",
+		InboundRules: []domain.NACLRule{{RuleNumber: 100, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"}},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	ingress := NewENIIngress(&domain.ENIData{
+		ID:             "eni-1",
+		PrivateIP:      "10.0.1.20",
+		SecurityGroups: []string{"sg-ssh", "sg-db"},
+		SubnetID:       "subnet-1",
+	}, "111111111111")
+
+	tests := []struct {
+		port    int
+		allowed bool
+	}{
+		{22, true},
+		{5432, true},
+		{3306, false},
+	}
+	for _, tt := range tests {
+		flow := domain.RoutingTarget{SourceIP: "10.0.2.50", IP: "10.0.1.20", Port: tt.port, Protocol: "tcp", Direction: "inbound"}
+		naclHops, err := ingress.GetNextHops(flow, analyzerCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sgHops, err := naclHops[0].GetNextHops(flow, analyzerCtx)
+		if err != nil {
+			t.Fatalf("expected NACL to allow, got %v", err)
+		}
+		if len(sgHops) != 1 {
+			t.Fatalf("expected the groups as a single filter hop, got %d", len(sgHops))
+		}
+		_, err = sgHops[0].GetNextHops(flow, analyzerCtx)
+		if tt.allowed && err != nil {
+			t.Errorf("port %d: expected one group allowing it to be enough, got %v", tt.port, err)
+		}
+		var blockErr *domain.BlockingError
+		if !tt.allowed && !errors.As(err, &blockErr) {
+			t.Errorf("port %d: expected no group to allow it, got %v", tt.port, err)
+		}
+	}
+}
+
+func TestENIIngress_SkipsNACLOfEnteredSubnet(t *testing.T) {
+	client := newMockAWSClient()
+	client.securityGroups["sg-db"] = &domain.SecurityGroupData{
+		ID:           "sg-db",
+		VPCID:        "vpc-1",
+		InboundRules: []domain.SecurityGroupRule{{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.0.0/8"}}},
+	}
+	subnet := &domain.SubnetData{ID: "subnet-1", VPCID: "vpc-1", CIDRBlock: "10.0.1.0/24", NaclID: "nacl-1"}
+	client.subnets["subnet-1"] = subnet
+	client.nacls["nacl-1"] = &domain.NACLData{
+		ID:           "nacl-1",
+		VPCID:        "vpc-1",
+		InboundRules: []domain.NACLRule{{RuleNumber: 100, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"}},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	ingress := NewENIIngress(&domain.ENIData{
+		ID:             "eni-1",
+		PrivateIP:      "10.0.1.20",
+		SecurityGroups: []string{"sg-db"},
+		SubnetID:       "subnet-1",
+	}, "111111111111")
+
+	flow := domain.RoutingTarget{SourceIP: "172.16.0.5", IP: "10.0.1.20", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	hops, err := ingress.GetNextHops(flow, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := hops[0].(*NACL); !ok {
+		t.Fatalf("expected the subnet NACL for a flow from inside the VPC, got %T", hops[0])
+	}
+
+	entered := NewSubnetIngress(subnet, "111111111111").Translate(flow)
+	hops, err = ingress.GetNextHops(entered, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected one hop, got %d", len(hops))
+	}
+	if _, ok := hops[0].(*SecurityGroup); !ok {
+		t.Errorf("expected the NACL admitted at the subnet boundary to be skipped, got %T", hops[0])
+	}
+}
+
+func TestRDSInstance_GetIngressHops_ClosedSecurityGroup(t *testing.T) {
+	client := newMockAWSClient()
+	client.securityGroups["sg-rds"] = &domain.SecurityGroupData{
+		ID:    "sg-rds",
+		VPCID: "vpc-1",
+		InboundRules: []domain.SecurityGroupRule{
+			{Protocol: "tcp", FromPort: 5432, ToPort: 5432, CIDRBlocks: []string{"10.0.9.0/24"}},
+		},
+	}
+	client.subnets["subnet-1"] = &domain.SubnetData{ID: "subnet-1", VPCID: "vpc-1", CIDRBlock: "10.0.1.0/24", NaclID: "nacl-1"}
+	client.subnets["subnet-2"] = &domain.SubnetData{ID: "subnet-2", VPCID: "vpc-1", CIDRBlock: "10.0.2.0/24", NaclID: "nacl-2"}
+	client.nacls["nacl-2"] = &domain.NACLData{
+		ID:    "nacl-2",
+		VPCID: "vpc-1",
+		InboundRules: []domain.NACLRule{
+			{RuleNumber: 100, Protocol: "-1", CIDRBlock: "0.0.0.0/0", Action: "allow"},
+		},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	rds := NewRDSInstance(&domain.RDSInstanceData{
+		ID:             "mydb",
+		PrivateIP:      "10.0.2.100",
+		Port:           5432,
+		SecurityGroups: []string{"sg-rds"},
+		SubnetIDs:      []string{"subnet-1", "subnet-2"},
+	}, "111111111111")
+
+	hops, err := rds.GetIngressHops(analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetComponentType() != "NetworkInterface" {
+		t.Fatalf("expected a single NetworkInterface hop, got %v", hops)
+	}
+	if eni, ok := hops[0].(*ENIIngress); !ok || eni.GetSubnetID() != "subnet-2" {
+		t.Errorf("expected ENI in subnet-2, got %v", hops[0])
+	}
+
+	flow := domain.RoutingTarget{SourceIP: "10.0.1.50", IP: "10.0.2.100", Port: 5432, Protocol: "tcp", Direction: "inbound"}
+	naclHops, err := hops[0].GetNextHops(flow, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sgHops, err := naclHops[0].GetNextHops(flow, analyzerCtx)
+	if err != nil {
+		t.Fatalf("expected NACL to allow, got %v", err)
+	}
+	if _, err := sgHops[0].GetNextHops(flow, analyzerCtx); err == nil {
+		t.Error("expected security group to block 10.0.1.50")
+	}
+}
+
+func TestEC2Instance_GetIngressHops_UsesLookedUpENI(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-1"] = &domain.ENIData{
+		ID:             "eni-1",
+		PrivateIP:      "10.0.1.50",
+		SubnetID:       "subnet-1",
+		VPCID:          "vpc-1",
+		SecurityGroups: []string{"sg-eni"},
+	}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	ec2 := NewEC2Instance(&domain.EC2InstanceData{ID: "i-1", PrivateIP: "10.0.1.50", SubnetID: "subnet-1", VPCID: "vpc-1"}, "111111111111")
+	hops, err := ec2.GetIngressHops(analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 || hops[0].GetID() != "111111111111:eni-1" {
+		t.Fatalf("expected ingress via eni-1, got %v", hops)
+	}
+	if vpcID := hops[0].(*ENIIngress).GetVPCID(); vpcID != "vpc-1" {
+		t.Errorf("expected the ingress hop in vpc-1, got %q", vpcID)
+	}
+}
+
+func TestEC2Instance_GetIngressHops_LookupErrorIsReturned(t *testing.T) {
+	client := newMockAWSClient()
+	client.eniLookupErr = errors.New("AccessDenied")
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	data := &domain.EC2InstanceData{ID: "i-1", PrivateIP: "10.0.1.50", SubnetID: "subnet-1", VPCID: "vpc-1"}
+	hops, err := NewEC2Instance(data, "111111111111").GetIngressHops(analyzerCtx)
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected the lookup error, got hops %v and error %v", hops, err)
+	}
+}
+
+func TestIngressForIP_LeavesFallbackUntouched(t *testing.T) {
+	client := newMockAWSClient()
+	client.subnets["subnet-2"] = &domain.SubnetData{ID: "subnet-2", VPCID: "vpc-1", CIDRBlock: "10.0.2.0/24"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	fallback := &domain.ENIData{PrivateIP: "10.0.2.100"}
+	hops, err := ingressForIP(analyzerCtx, "111111111111", "vpc-1", fallback, []string{"subnet-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hops[0].(*ENIIngress).GetSubnetID() != "subnet-2" {
+		t.Errorf("expected ENI in subnet-2, got %s", hops[0].(*ENIIngress).GetSubnetID())
+	}
+	if fallback.SubnetID != "" {
+		t.Errorf("expected the fallback to be left alone, got subnet %s", fallback.SubnetID)
+	}
+}
+
+func TestGetIngressHops_ScopesLookupToResourceVPC(t *testing.T) {
+	client := newMockAWSClient()
+	client.networkENIs["eni-other"] = &domain.ENIData{ID: "eni-other", PrivateIP: "10.0.1.50", SubnetID: "subnet-other", VPCID: "vpc-other"}
+	client.networkENIs["eni-1"] = &domain.ENIData{ID: "eni-1", PrivateIP: "10.0.1.50", SubnetID: "subnet-1", VPCID: "vpc-1"}
+	client.subnets["subnet-1"] = &domain.SubnetData{ID: "subnet-1", VPCID: "vpc-1", CIDRBlock: "10.0.1.0/24"}
+
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	components := map[string]domain.Component{
+		"ec2 with vpc":        NewEC2Instance(&domain.EC2InstanceData{ID: "i-1", PrivateIP: "10.0.1.50", SubnetID: "subnet-1", VPCID: "vpc-1"}, "111111111111"),
+		"rds from subnet":     NewRDSInstance(&domain.RDSInstanceData{ID: "mydb", PrivateIP: "10.0.1.50", SubnetIDs: []string{"subnet-1"}}, "111111111111"),
+		"eks pod from subnet": NewEKSPod(&domain.EKSPodData{PodIP: "10.0.1.50", SubnetID: "subnet-1"}, "111111111111"),
+	}
+	for name, component := range components {
+		t.Run(name, func(t *testing.T) {
+			hops, err := component.(domain.IngressProvider).GetIngressHops(analyzerCtx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(hops) != 1 || hops[0].GetID() != "111111111111:eni-1" {
+				t.Errorf("expected ingress via eni-1 in the resource's VPC, got %v", hops)
+			}
+		})
+	}
+}
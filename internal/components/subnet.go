@@ -19,7 +19,7 @@ func NewSubnet(data *domain.SubnetData, accountID string) *Subnet {
 }
 
 func (s *Subnet) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(s.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(s.accountID, s.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +53,10 @@ func (s *Subnet) GetAccountID() string {
 	return s.accountID
 }
 
+func (s *Subnet) IsSourceAgnostic() bool {
+	return true
+}
+
 func (s *Subnet) GetComponentType() string {
 	return "Subnet"
 }
@@ -62,7 +66,7 @@ func (s *Subnet) GetVPCID() string {
 }
 
 func (s *Subnet) GetRegion() string {
-	return ""
+	return s.data.Region
 }
 
 func (s *Subnet) GetSubnetID() string {
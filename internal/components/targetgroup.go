@@ -2,6 +2,8 @@ package components
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/eleven-am/argus/internal/domain"
 )
@@ -9,6 +11,13 @@ import (
 type TargetGroup struct {
 	data      *domain.TargetGroupData
 	accountID string
+	// proxyIP is the source address targets see when the load balancer in
+	// front of the group does not preserve the client's.
+	proxyIP string
+	// port narrows the group to the targets receiving traffic on it. A group
+	// whose targets listen on different ports is split into one per port, so
+	// each target is checked on its own port.
+	port int
 }
 
 func NewTargetGroup(data *domain.TargetGroupData, accountID string) *TargetGroup {
@@ -19,12 +28,7 @@ func NewTargetGroup(data *domain.TargetGroupData, accountID string) *TargetGroup
 }
 
 func (tg *TargetGroup) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	var reachableTargets []domain.TargetData
-	for _, t := range tg.data.Targets {
-		if isTargetReachable(t.HealthStatus) {
-			reachableTargets = append(reachableTargets, t)
-		}
-	}
+	reachableTargets := tg.reachableTargets()
 
 	if len(reachableTargets) == 0 {
 		return nil, &domain.BlockingError{
@@ -33,7 +37,17 @@ func (tg *TargetGroup) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 		}
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(tg.accountID)
+	if ports := tg.deliveryPorts(); len(ports) > 1 {
+		var split []domain.Component
+		for _, port := range ports {
+			onPort := *tg
+			onPort.port = port
+			split = append(split, &onPort)
+		}
+		return split, nil
+	}
+
+	client, err := analyzerCtx.GetAccountContext().GetClient(tg.accountID, tg.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +102,126 @@ func (tg *TargetGroup) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 	return components, nil
 }
 
+// Translate rewrites the flow as the load balancer sends it on to targets: to
+// the port the targets receive traffic on, and from the load balancer's own
+// address when the group does not preserve client IPs. Targets' security
+// groups are then checked against what they actually see.
+func (tg *TargetGroup) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	if target.Reply {
+		return target
+	}
+	if ports := tg.deliveryPorts(); len(ports) == 1 && ports[0] > 0 {
+		target.Port = ports[0]
+		target.PortTo = 0
+	}
+	if tg.proxyIP != "" {
+		target.SourceIP = tg.proxyIP
+		target.SourceIsPrivate = !isExternalIP(tg.proxyIP)
+	}
+	return target
+}
+
+// reachableTargets returns the healthy targets, narrowed to those on the
+// group's port once it was split by port.
+func (tg *TargetGroup) reachableTargets() []domain.TargetData {
+	var reachable []domain.TargetData
+	for _, t := range tg.data.Targets {
+		if !isTargetReachable(t.HealthStatus) {
+			continue
+		}
+		if tg.port != 0 && tg.targetPort(t) != tg.port {
+			continue
+		}
+		reachable = append(reachable, t)
+	}
+	return reachable
+}
+
+// deliveryPorts returns the distinct ports the reachable targets receive
+// traffic on, in target order.
+func (tg *TargetGroup) deliveryPorts() []int {
+	var ports []int
+	for _, t := range tg.reachableTargets() {
+		if port := tg.targetPort(t); !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// targetPort returns the port t was registered on, or the group's port when it
+// was registered without one.
+func (tg *TargetGroup) targetPort(t domain.TargetData) int {
+	if t.Port != 0 {
+		return t.Port
+	}
+	return tg.data.Port
+}
+
+// listenerTargetGroups returns the target groups a load balancer forwards dest
+// to: those of its listeners on the flow's destination port and protocol. A
+// load balancer whose listeners were never fetched, or a flow without a port,
+// uses every target group; one with no listeners at all blocks.
+func listenerTargetGroups(componentID string, listeners []domain.ListenerData, loaded bool, all []string, dest domain.RoutingTarget) ([]string, error) {
+	if loaded && len(listeners) == 0 {
+		return nil, &domain.BlockingError{ComponentID: componentID, Reason: "no listeners"}
+	}
+	if len(listeners) == 0 || dest.Port == 0 {
+		return all, nil
+	}
+
+	low, high := dest.PortRange()
+	matched := false
+	var arns []string
+	for _, listener := range listeners {
+		if listener.Port < low || listener.Port > high || !listenerCarries(listener.Protocol, dest.Protocol) {
+			continue
+		}
+		matched = true
+		for _, arn := range listener.TargetGroupARNs {
+			if !slices.Contains(arns, arn) {
+				arns = append(arns, arn)
+			}
+		}
+	}
+
+	if !matched {
+		listener := "listener"
+		if protocol := normalizeProtocol(dest.Protocol); protocol != "all" {
+			listener = protocol + " listener"
+		}
+		return nil, &domain.BlockingError{
+			ComponentID: componentID,
+			Reason:      fmt.Sprintf("no %s on port %s", listener, formatPorts(dest)),
+		}
+	}
+	if len(arns) == 0 {
+		return nil, &domain.BlockingError{
+			ComponentID: componentID,
+			Reason:      fmt.Sprintf("listener on port %s does not forward to a target group", formatPorts(dest)),
+		}
+	}
+	return arns, nil
+}
+
+// listenerCarries reports whether a listener on protocol accepts flows of the
+// IP protocol destProtocol. UDP listeners take UDP, TCP_UDP listeners either,
+// and the others, TCP and the protocols over it, take TCP.
+func listenerCarries(protocol, destProtocol string) bool {
+	dest := normalizeProtocol(destProtocol)
+	if dest == "all" {
+		return true
+	}
+	switch strings.ToUpper(protocol) {
+	case "UDP":
+		return dest == "udp"
+	case "TCP_UDP":
+		return dest == "tcp" || dest == "udp"
+	default:
+		return dest == "tcp"
+	}
+}
+
 func isTargetReachable(healthStatus string) bool {
 	switch healthStatus {
 	case "healthy":
@@ -113,6 +247,17 @@ func (tg *TargetGroup) GetID() string {
 	return fmt.Sprintf("%s:%s", tg.accountID, tg.data.ARN)
 }
 
+func (tg *TargetGroup) GetStateKey() string {
+	key := tg.GetID()
+	if tg.proxyIP != "" {
+		key += "@" + tg.proxyIP
+	}
+	if tg.port == 0 {
+		return key
+	}
+	return fmt.Sprintf("%s:%d", key, tg.port)
+}
+
 func (tg *TargetGroup) GetAccountID() string {
 	return tg.accountID
 }
@@ -126,7 +271,7 @@ func (tg *TargetGroup) GetVPCID() string {
 }
 
 func (tg *TargetGroup) GetRegion() string {
-	return ""
+	return tg.data.Region
 }
 
 func (tg *TargetGroup) GetSubnetID() string {
@@ -0,0 +1,88 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/eleven-am/argus/internal/domain"
+)
+
+// TGWConnectAttachment is a transit gateway Connect attachment, which carries
+// traffic over GRE tunnels to appliances such as SD-WAN routers. Like a VPN
+// connection, it ends the path on the AWS side; the appliance takes the
+// traffic on into its own network.
+type TGWConnectAttachment struct {
+	data      *domain.TGWConnectAttachmentData
+	accountID string
+}
+
+func NewTGWConnectAttachment(data *domain.TGWConnectAttachmentData, accountID string) *TGWConnectAttachment {
+	return &TGWConnectAttachment{
+		data:      data,
+		accountID: accountID,
+	}
+}
+
+func (tca *TGWConnectAttachment) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	if tca.data.State != "" && tca.data.State != "available" {
+		return nil, &domain.BlockingError{
+			ComponentID: tca.GetID(),
+			Reason:      fmt.Sprintf("TGW Connect attachment state is %s, not available", tca.data.State),
+		}
+	}
+
+	if !tca.hasEstablishedPeer() {
+		return nil, &domain.BlockingError{
+			ComponentID: tca.GetID(),
+			Reason:      "no Connect peer has an established BGP session",
+		}
+	}
+
+	return []domain.Component{NewIPTarget(&domain.IPTargetData{IP: dest.IP, Port: dest.Port}, tca.accountID)}, nil
+}
+
+// hasEstablishedPeer reports whether an available Connect peer has a BGP
+// session up, without which the transit gateway has no tunnel to send over.
+func (tca *TGWConnectAttachment) hasEstablishedPeer() bool {
+	for _, peer := range tca.data.Peers {
+		if peer.State == "available" && peer.BGPUp {
+			return true
+		}
+	}
+	return false
+}
+
+func (tca *TGWConnectAttachment) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (tca *TGWConnectAttachment) GetID() string {
+	return fmt.Sprintf("%s:%s", tca.accountID, tca.data.ID)
+}
+
+func (tca *TGWConnectAttachment) GetAccountID() string {
+	return tca.accountID
+}
+
+func (tca *TGWConnectAttachment) IsTerminal() bool {
+	return true
+}
+
+func (tca *TGWConnectAttachment) GetComponentType() string {
+	return "TGWConnectAttachment"
+}
+
+func (tca *TGWConnectAttachment) GetVPCID() string {
+	return ""
+}
+
+func (tca *TGWConnectAttachment) GetRegion() string {
+	return tca.data.Region
+}
+
+func (tca *TGWConnectAttachment) GetSubnetID() string {
+	return ""
+}
+
+func (tca *TGWConnectAttachment) GetAvailabilityZone() string {
+	return ""
+}
@@ -26,7 +26,7 @@ func (tga *TransitGatewayAttachment) GetNextHops(dest domain.RoutingTarget, anal
 		}
 	}
 
-	tgwClient, err := analyzerCtx.GetAccountContext().GetClient(tga.data.TGWAccountID)
+	tgwClient, err := analyzerCtx.GetAccountContext().GetClient(tga.data.TGWAccountID, tga.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +52,10 @@ func (tga *TransitGatewayAttachment) GetAccountID() string {
 	return tga.accountID
 }
 
+func (tga *TransitGatewayAttachment) IsSourceAgnostic() bool {
+	return true
+}
+
 func (tga *TransitGatewayAttachment) GetComponentType() string {
 	return "TransitGatewayAttachment"
 }
@@ -61,7 +65,7 @@ func (tga *TransitGatewayAttachment) GetVPCID() string {
 }
 
 func (tga *TransitGatewayAttachment) GetRegion() string {
-	return ""
+	return tga.data.Region
 }
 
 func (tga *TransitGatewayAttachment) GetSubnetID() string {
@@ -89,27 +93,52 @@ func NewTransitGateway(data *domain.TransitGatewayData, accountID, ingressAttach
 	}
 }
 
+// GetNextHops returns the attachments of the most specific route to dest in
+// the route tables the ingress attachment may use. A route with several
+// available attachments is an ECMP route, such as a VPN with multiple tunnels,
+// and each attachment is returned as a parallel next hop: all-paths analysis
+// reports every one of them, while a first-path analysis stops at the first
+// that gets through. An attachment that cannot be resolved only drops its
+// own path; the route fails only when none of its attachments resolve.
 func (tgw *TransitGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
 	allowedRTIDs := tgw.getAllowedRouteTables()
 
 	prefixCache := make(map[string]int)
-	matchedRoute, matchedAttachment := tgw.findBestRoute(dest, allowedRTIDs, analyzerCtx, prefixCache)
+	matchedRoute, matchedAttachments, err := tgw.findBestRoute(dest, allowedRTIDs, analyzerCtx, prefixCache)
+	if err != nil {
+		return nil, err
+	}
 
-	if matchedRoute == nil || matchedAttachment == nil {
+	if matchedRoute == nil {
 		return nil, &domain.BlockingError{
 			ComponentID: tgw.GetID(),
 			Reason:      fmt.Sprintf("no transit gateway route to %s for attachment %s", dest.IP, tgw.ingressAttachmentID),
 		}
 	}
 
-	if matchedAttachment.State != "" && matchedAttachment.State != "available" {
+	if matchedRoute.State == "blackhole" {
 		return nil, &domain.BlockingError{
 			ComponentID: tgw.GetID(),
-			Reason:      fmt.Sprintf("target attachment %s state is %s, not available", matchedAttachment.ID, matchedAttachment.State),
+			Reason:      fmt.Sprintf("transit gateway route %s to %s is a blackhole", routeDestination(matchedRoute), dest.IP),
 		}
 	}
 
-	return tgw.dispatchToAttachment(matchedAttachment, analyzerCtx)
+	var hops []domain.Component
+	var firstErr error
+	for i := range matchedAttachments {
+		dispatched, err := tgw.dispatchToAttachment(&matchedAttachments[i], analyzerCtx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		hops = append(hops, dispatched...)
+	}
+	if len(hops) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return hops, nil
 }
 
 func (tgw *TransitGateway) getAllowedRouteTables() map[string]bool {
@@ -138,9 +167,15 @@ func (tgw *TransitGateway) getAllowedRouteTables() map[string]bool {
 	return allowed
 }
 
-func (tgw *TransitGateway) findBestRoute(dest domain.RoutingTarget, allowedRTIDs map[string]bool, analyzerCtx domain.AnalyzerContext, prefixCache map[string]int) (*domain.TGWRoute, *domain.TGWRouteAttachment) {
+// findBestRoute returns the most specific active or blackhole route to dest
+// and the available attachments it forwards to. A blackhole route is returned
+// with no attachments, since it drops the traffic rather than letting a less
+// specific route carry it. An active route without an available attachment is
+// skipped. A prefix list that cannot be read is an error, since it could hold
+// the most specific match.
+func (tgw *TransitGateway) findBestRoute(dest domain.RoutingTarget, allowedRTIDs map[string]bool, analyzerCtx domain.AnalyzerContext, prefixCache map[string]int) (*domain.TGWRoute, []domain.TGWRouteAttachment, error) {
 	var bestRoute *domain.TGWRoute
-	var bestAttachment *domain.TGWRouteAttachment
+	var bestAttachments []domain.TGWRouteAttachment
 	longestPrefix := -1
 
 	for i := range tgw.data.RouteTables {
@@ -153,7 +188,7 @@ func (tgw *TransitGateway) findBestRoute(dest domain.RoutingTarget, allowedRTIDs
 		for j := range rt.Routes {
 			route := &rt.Routes[j]
 
-			if route.State != "active" {
+			if route.State != "active" && route.State != "blackhole" {
 				continue
 			}
 
@@ -161,42 +196,50 @@ func (tgw *TransitGateway) findBestRoute(dest domain.RoutingTarget, allowedRTIDs
 			if route.DestinationCIDR != "" && IPMatchesCIDR(dest.IP, route.DestinationCIDR) {
 				matchPrefix = route.PrefixLength
 			} else if route.DestinationPrefixListID != "" {
-				matchPrefix = tgw.matchPrefixList(dest.IP, route.DestinationPrefixListID, analyzerCtx, prefixCache)
+				var err error
+				matchPrefix, err = tgw.matchPrefixList(dest.IP, route.DestinationPrefixListID, analyzerCtx, prefixCache)
+				if err != nil {
+					return nil, nil, err
+				}
 			}
 
 			if matchPrefix <= longestPrefix || matchPrefix < 0 {
 				continue
 			}
 
-			attachment := tgw.selectActiveAttachment(route)
-			if attachment == nil {
-				continue
+			var attachments []domain.TGWRouteAttachment
+			if route.State == "active" {
+				attachments = tgw.availableAttachments(route)
+				if len(attachments) == 0 {
+					continue
+				}
 			}
 
 			bestRoute = route
-			bestAttachment = attachment
+			bestAttachments = attachments
 			longestPrefix = matchPrefix
 		}
 	}
 
-	return bestRoute, bestAttachment
+	return bestRoute, bestAttachments, nil
 }
 
-func (tgw *TransitGateway) matchPrefixList(ip, plID string, analyzerCtx domain.AnalyzerContext, prefixCache map[string]int) int {
+// matchPrefixList returns the longest entry of plID containing ip, or -1 when
+// none does. Only successful lookups are cached.
+func (tgw *TransitGateway) matchPrefixList(ip, plID string, analyzerCtx domain.AnalyzerContext, prefixCache map[string]int) (int, error) {
 	if val, ok := prefixCache[plID+":"+ip]; ok {
-		return val
+		return val, nil
 	}
 	if analyzerCtx == nil || analyzerCtx.GetAccountContext() == nil {
-		return -1
+		return -1, nil
 	}
-	client, err := analyzerCtx.GetAccountContext().GetClient(tgw.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(tgw.accountID, tgw.data.Region)
 	if err != nil {
-		return -1
+		return -1, err
 	}
 	pl, err := client.GetManagedPrefixList(analyzerCtx.Context(), plID)
 	if err != nil {
-		prefixCache[plID+":"+ip] = -1
-		return -1
+		return -1, fmt.Errorf("prefix list %s: %w", plID, err)
 	}
 
 	longest := -1
@@ -209,21 +252,28 @@ func (tgw *TransitGateway) matchPrefixList(ip, plID string, analyzerCtx domain.A
 		}
 	}
 	prefixCache[plID+":"+ip] = longest
-	return longest
+	return longest, nil
 }
 
-func (tgw *TransitGateway) selectActiveAttachment(route *domain.TGWRoute) *domain.TGWRouteAttachment {
-	for i := range route.Attachments {
-		att := &route.Attachments[i]
+func (tgw *TransitGateway) availableAttachments(route *domain.TGWRoute) []domain.TGWRouteAttachment {
+	var available []domain.TGWRouteAttachment
+	for _, att := range route.Attachments {
 		if att.State == "" || att.State == "available" {
-			return att
+			available = append(available, att)
 		}
 	}
-	return nil
+	return available
+}
+
+func routeDestination(route *domain.TGWRoute) string {
+	if route.DestinationCIDR != "" {
+		return route.DestinationCIDR
+	}
+	return route.DestinationPrefixListID
 }
 
 func (tgw *TransitGateway) dispatchToAttachment(att *domain.TGWRouteAttachment, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	targetClient, err := analyzerCtx.GetAccountContext().GetClient(att.OwnerID)
+	targetClient, err := analyzerCtx.GetAccountContext().GetClient(att.OwnerID, tgw.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +289,7 @@ func (tgw *TransitGateway) dispatchToAttachment(att *domain.TGWRouteAttachment,
 		return []domain.Component{NewTransitGatewayVPCAttachmentInbound(attachmentData, att.OwnerID)}, nil
 
 	case "peering":
-		peeringData, err := targetClient.GetTGWPeeringAttachment(ctx, att.ID)
+		peeringData, err := targetClient.GetTGWPeeringAttachment(ctx, att.ID, tgw.data.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -257,7 +307,14 @@ func (tgw *TransitGateway) dispatchToAttachment(att *domain.TGWRouteAttachment,
 		if err != nil {
 			return nil, err
 		}
-		return []domain.Component{NewDirectConnectGateway(dxgwData, att.OwnerID)}, nil
+		return []domain.Component{NewOutboundDirectConnectGateway(dxgwData, tgw.data.ID, att.OwnerID)}, nil
+
+	case "connect":
+		connectData, err := targetClient.GetTGWConnectAttachment(ctx, att.ID)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Component{NewTGWConnectAttachment(connectData, att.OwnerID)}, nil
 
 	default:
 		return nil, &domain.BlockingError{
@@ -275,10 +332,18 @@ func (tgw *TransitGateway) GetID() string {
 	return fmt.Sprintf("%s:%s", tgw.accountID, tgw.data.ID)
 }
 
+func (tgw *TransitGateway) GetStateKey() string {
+	return tgw.GetID() + "<" + tgw.ingressAttachmentID
+}
+
 func (tgw *TransitGateway) GetAccountID() string {
 	return tgw.accountID
 }
 
+func (tgw *TransitGateway) IsSourceAgnostic() bool {
+	return true
+}
+
 func (tgw *TransitGateway) GetComponentType() string {
 	return "TransitGateway"
 }
@@ -288,7 +353,7 @@ func (tgw *TransitGateway) GetVPCID() string {
 }
 
 func (tgw *TransitGateway) GetRegion() string {
-	return ""
+	return tgw.data.Region
 }
 
 func (tgw *TransitGateway) GetSubnetID() string {
@@ -319,38 +384,32 @@ func (tga *TransitGatewayVPCAttachmentInbound) GetNextHops(dest domain.RoutingTa
 		}
 	}
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(tga.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(tga.accountID, tga.data.Region)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx := analyzerCtx.Context()
+	return vpcIngress(analyzerCtx, client, tga.accountID, tga.data.VPCID, dest.IP)
+}
 
-	for _, subnetID := range tga.data.SubnetIDs {
-		subnetData, err := client.GetSubnet(ctx, subnetID)
-		if err != nil {
-			continue
-		}
-		if IPMatchesCIDR(dest.IP, subnetData.CIDRBlock) {
-			rtData, err := client.GetRouteTable(ctx, subnetData.RouteTableID)
-			if err != nil {
-				return nil, err
-			}
-			return []domain.Component{NewRouteTable(rtData, tga.accountID)}, nil
-		}
+// Advise warns when traffic enters a VPC that is not its destination, such as
+// an inspection VPC, through an attachment without appliance mode. The transit
+// gateway then picks the availability zone of each direction independently,
+// so replies can reach a different appliance than the request and be dropped
+// by a stateful firewall.
+func (tga *TransitGatewayVPCAttachmentInbound) Advise(target domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) string {
+	if tga.data.ApplianceModeSupport {
+		return ""
 	}
-
-	vpcData, err := client.GetVPC(ctx, tga.data.VPCID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(tga.accountID, tga.data.Region)
 	if err != nil {
-		return nil, err
+		return ""
 	}
-
-	rtData, err := client.GetRouteTable(ctx, vpcData.MainRouteTableID)
-	if err != nil {
-		return nil, err
+	vpc, err := client.GetVPC(analyzerCtx.Context(), tga.data.VPCID)
+	if err != nil || vpcContainsIP(vpc, target.IP) {
+		return ""
 	}
-
-	return []domain.Component{NewRouteTable(rtData, tga.accountID)}, nil
+	return fmt.Sprintf("appliance mode is disabled on %s: return traffic through %s may take an asymmetric path", tga.data.ID, tga.data.VPCID)
 }
 
 func (tga *TransitGatewayVPCAttachmentInbound) GetRoutingTarget() domain.RoutingTarget {
@@ -365,6 +424,10 @@ func (tga *TransitGatewayVPCAttachmentInbound) GetAccountID() string {
 	return tga.accountID
 }
 
+func (tga *TransitGatewayVPCAttachmentInbound) IsSourceAgnostic() bool {
+	return true
+}
+
 func (tga *TransitGatewayVPCAttachmentInbound) GetComponentType() string {
 	return "TransitGatewayVPCAttachmentInbound"
 }
@@ -374,7 +437,7 @@ func (tga *TransitGatewayVPCAttachmentInbound) GetVPCID() string {
 }
 
 func (tga *TransitGatewayVPCAttachmentInbound) GetRegion() string {
-	return ""
+	return tga.data.Region
 }
 
 func (tga *TransitGatewayVPCAttachmentInbound) GetSubnetID() string {
@@ -401,7 +464,11 @@ func NewTGWPeeringAttachment(data *domain.TGWPeeringAttachmentData, accountID st
 }
 
 func (tpa *TGWPeeringAttachment) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	peerClient, err := analyzerCtx.GetAccountContext().GetClient(tpa.data.PeerAccountID)
+	peerRegion := tpa.data.PeerRegion
+	if peerRegion == "" {
+		peerRegion = tpa.data.Region
+	}
+	peerClient, err := analyzerCtx.GetAccountContext().GetClient(tpa.data.PeerAccountID, peerRegion)
 	if err != nil {
 		return nil, err
 	}
@@ -427,6 +494,10 @@ func (tpa *TGWPeeringAttachment) GetAccountID() string {
 	return tpa.accountID
 }
 
+func (tpa *TGWPeeringAttachment) IsSourceAgnostic() bool {
+	return true
+}
+
 func (tpa *TGWPeeringAttachment) GetComponentType() string {
 	return "TGWPeeringAttachment"
 }
@@ -436,7 +507,7 @@ func (tpa *TGWPeeringAttachment) GetVPCID() string {
 }
 
 func (tpa *TGWPeeringAttachment) GetRegion() string {
-	return ""
+	return tpa.data.Region
 }
 
 func (tpa *TGWPeeringAttachment) GetSubnetID() string {
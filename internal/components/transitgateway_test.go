@@ -179,7 +179,7 @@ func TestTransitGateway_AttachmentAwareRouting(t *testing.T) {
 			wantErrContains: "no transit gateway route",
 		},
 		{
-			name: "ignores blackhole route state",
+			name: "blocks on blackhole route state",
 			tgwData: &domain.TransitGatewayData{
 				ID:      "tgw-123",
 				OwnerID: "111111111111",
@@ -209,7 +209,7 @@ func TestTransitGateway_AttachmentAwareRouting(t *testing.T) {
 				ctx.addClient("111111111111", client)
 			},
 			wantErr:         true,
-			wantErrContains: "no transit gateway route",
+			wantErrContains: "is a blackhole",
 		},
 		{
 			name: "skips unavailable attachment picks available one",
@@ -737,6 +737,300 @@ func TestTGWPeeringAttachment_ThreadsAttachmentID(t *testing.T) {
 	}
 }
 
+func TestTransitGateway_BlackholeBeatsLessSpecificRoute(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.tgwAttachments["tgw-attach-target"] = &domain.TGWAttachmentData{ID: "tgw-attach-target", VPCID: "vpc-target", State: "available"}
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tgw := NewTransitGateway(&domain.TransitGatewayData{
+		ID:      "tgw-123",
+		OwnerID: "111111111111",
+		RouteTables: []domain.TGWRouteTableData{
+			{
+				ID: "tgw-rtb-1",
+				Routes: []domain.TGWRoute{
+					{
+						DestinationCIDR: "10.0.0.0/8",
+						PrefixLength:    8,
+						State:           "active",
+						Attachments: []domain.TGWRouteAttachment{
+							{ID: "tgw-attach-target", Type: "vpc", OwnerID: "111111111111", State: "available"},
+						},
+					},
+					{DestinationCIDR: "10.1.0.0/16", PrefixLength: 16, State: "blackhole"},
+				},
+			},
+		},
+	}, "111111111111", "")
+
+	_, err := tgw.GetNextHops(domain.RoutingTarget{IP: "10.1.2.3", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err == nil || !stringContains(err.Error(), "10.1.0.0/16") || !stringContains(err.Error(), "blackhole") {
+		t.Fatalf("expected the more specific blackhole route to block, got %v", err)
+	}
+
+	hops, err := tgw.GetNextHops(domain.RoutingTarget{IP: "10.2.2.3", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error outside the blackhole: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+}
+
+func TestTransitGateway_UnreadablePrefixListIsUndecided(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	accountCtx.addClient("111111111111", newMockAWSClient())
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tgw := NewTransitGateway(&domain.TransitGatewayData{
+		ID:      "tgw-123",
+		OwnerID: "111111111111",
+		RouteTables: []domain.TGWRouteTableData{
+			{
+				ID: "tgw-rtb-1",
+				Routes: []domain.TGWRoute{
+					{
+						DestinationPrefixListID: "pl-missing",
+						State:                   "active",
+						Attachments: []domain.TGWRouteAttachment{
+							{ID: "tgw-attach-target", Type: "vpc", OwnerID: "111111111111", State: "available"},
+						},
+					},
+				},
+			},
+		},
+	}, "111111111111", "")
+
+	_, err := tgw.GetNextHops(domain.RoutingTarget{IP: "10.1.2.3", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err == nil {
+		t.Fatal("expected an error when the prefix list cannot be read")
+	}
+	if domain.IsBlocking(err) {
+		t.Errorf("expected the route to be undecided, got block: %v", err)
+	}
+}
+
+func TestTransitGateway_ECMPAttachmentsAreParallelHops(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.vpnConnections["vpn-1"] = &domain.VPNConnectionData{ID: "vpn-1", State: "available", HasUpTunnel: true}
+	client.vpnConnections["vpn-2"] = &domain.VPNConnectionData{ID: "vpn-2", State: "available", HasUpTunnel: true}
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tgw := NewTransitGateway(&domain.TransitGatewayData{
+		ID:      "tgw-123",
+		OwnerID: "111111111111",
+		RouteTables: []domain.TGWRouteTableData{
+			{
+				ID: "tgw-rtb-1",
+				Routes: []domain.TGWRoute{
+					{
+						DestinationCIDR: "192.168.0.0/16",
+						PrefixLength:    16,
+						State:           "active",
+						Attachments: []domain.TGWRouteAttachment{
+							{ID: "tgw-attach-vpn-1", Type: "vpn", ResourceID: "vpn-1", OwnerID: "111111111111", State: "available"},
+							{ID: "tgw-attach-vpn-2", Type: "vpn", ResourceID: "vpn-2", OwnerID: "111111111111", State: "available"},
+							{ID: "tgw-attach-vpn-3", Type: "vpn", ResourceID: "vpn-3", OwnerID: "111111111111", State: "deleting"},
+						},
+					},
+				},
+			},
+		},
+	}, "111111111111", "")
+
+	hops, err := tgw.GetNextHops(domain.RoutingTarget{IP: "192.168.1.10", Port: 443, Protocol: "tcp"}, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("expected one hop per available ECMP attachment, got %d", len(hops))
+	}
+	for i, want := range []string{"vpn-1", "vpn-2"} {
+		vpn, ok := hops[i].(*VPNConnection)
+		if !ok {
+			t.Fatalf("hop %d: expected VPNConnection, got %T", i, hops[i])
+		}
+		if vpn.data.ID != want {
+			t.Errorf("hop %d: expected %s, got %s", i, want, vpn.data.ID)
+		}
+	}
+}
+
+func TestTransitGateway_ECMPKeepsResolvableAttachments(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.vpnConnections["vpn-1"] = &domain.VPNConnectionData{ID: "vpn-1", State: "available", HasUpTunnel: true}
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	route := domain.TGWRoute{
+		DestinationCIDR: "192.168.0.0/16",
+		PrefixLength:    16,
+		State:           "active",
+		Attachments: []domain.TGWRouteAttachment{
+			{ID: "tgw-attach-vpn-missing", Type: "vpn", ResourceID: "vpn-missing", OwnerID: "111111111111", State: "available"},
+			{ID: "tgw-attach-vpn-1", Type: "vpn", ResourceID: "vpn-1", OwnerID: "111111111111", State: "available"},
+		},
+	}
+	tgw := NewTransitGateway(&domain.TransitGatewayData{
+		ID:          "tgw-123",
+		OwnerID:     "111111111111",
+		RouteTables: []domain.TGWRouteTableData{{ID: "tgw-rtb-1", Routes: []domain.TGWRoute{route}}},
+	}, "111111111111", "")
+
+	dest := domain.RoutingTarget{IP: "192.168.1.10", Port: 443, Protocol: "tcp"}
+	hops, err := tgw.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected the resolvable attachment to remain, got %d hops", len(hops))
+	}
+	if vpn, ok := hops[0].(*VPNConnection); !ok || vpn.data.ID != "vpn-1" {
+		t.Errorf("expected vpn-1, got %v", hops[0])
+	}
+
+	route.Attachments = route.Attachments[:1]
+	tgw = NewTransitGateway(&domain.TransitGatewayData{
+		ID:          "tgw-123",
+		OwnerID:     "111111111111",
+		RouteTables: []domain.TGWRouteTableData{{ID: "tgw-rtb-1", Routes: []domain.TGWRoute{route}}},
+	}, "111111111111", "")
+	if _, err := tgw.GetNextHops(dest, analyzerCtx); err == nil {
+		t.Fatal("expected an error when no attachment resolves")
+	}
+}
+
+func TestTransitGatewayVPCAttachmentInbound_AdvisesApplianceMode(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.vpcs["vpc-inspection"] = &domain.VPCData{ID: "vpc-inspection", CIDRBlock: "100.64.0.0/16"}
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tests := []struct {
+		name          string
+		applianceMode bool
+		destIP        string
+		wantAdvisory  bool
+	}{
+		{"inspection VPC without appliance mode", false, "10.0.1.5", true},
+		{"inspection VPC with appliance mode", true, "10.0.1.5", false},
+		{"destination VPC", false, "100.64.1.5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tga := NewTransitGatewayVPCAttachmentInbound(&domain.TGWAttachmentData{
+				ID:                   "tgw-attach-inspection",
+				VPCID:                "vpc-inspection",
+				State:                "available",
+				ApplianceModeSupport: tt.applianceMode,
+			}, "111111111111")
+
+			advisory := tga.Advise(domain.RoutingTarget{IP: tt.destIP, Port: 443, Protocol: "tcp"}, analyzerCtx)
+			if (advisory != "") != tt.wantAdvisory {
+				t.Errorf("advisory = %q, want advisory: %v", advisory, tt.wantAdvisory)
+			}
+		})
+	}
+}
+
+func TestTransitGateway_RoutesToConnectAttachment(t *testing.T) {
+	accountCtx := newMockAccountContext()
+	client := newMockAWSClient()
+	client.tgwConnects["tgw-attach-connect"] = &domain.TGWConnectAttachmentData{
+		ID:                    "tgw-attach-connect",
+		TransitGatewayID:      "tgw-123",
+		TransportAttachmentID: "tgw-attach-transport",
+		State:                 "available",
+		Peers:                 []domain.TGWConnectPeerData{{ID: "tgw-connect-peer-1", State: "available", PeerAddress: "10.50.0.10", BGPUp: true}},
+	}
+	accountCtx.addClient("111111111111", client)
+	analyzerCtx := newMockAnalyzerContext(accountCtx)
+
+	tgw := NewTransitGateway(&domain.TransitGatewayData{
+		ID:      "tgw-123",
+		OwnerID: "111111111111",
+		RouteTables: []domain.TGWRouteTableData{
+			{
+				ID: "tgw-rtb-1",
+				Routes: []domain.TGWRoute{
+					{
+						DestinationCIDR: "172.16.0.0/12",
+						PrefixLength:    12,
+						State:           "active",
+						Attachments: []domain.TGWRouteAttachment{
+							{ID: "tgw-attach-connect", Type: "connect", ResourceID: "tgw-attach-transport", OwnerID: "111111111111", State: "available"},
+						},
+					},
+				},
+			},
+		},
+	}, "111111111111", "")
+
+	dest := domain.RoutingTarget{IP: "172.16.5.5", Port: 443, Protocol: "tcp"}
+	hops, err := tgw.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hops) != 1 {
+		t.Fatalf("expected 1 hop, got %d", len(hops))
+	}
+	connect, ok := hops[0].(*TGWConnectAttachment)
+	if !ok {
+		t.Fatalf("expected TGWConnectAttachment, got %T", hops[0])
+	}
+
+	hops, err = connect.GetNextHops(dest, analyzerCtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := hops[0].(*IPTarget); !ok {
+		t.Errorf("expected the Connect attachment to hand off to IPTarget, got %T", hops[0])
+	}
+}
+
+func TestTGWConnectAttachment_GetNextHops_Blocking(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *domain.TGWConnectAttachmentData
+		wantErr string
+	}{
+		{
+			name:    "attachment not available",
+			data:    &domain.TGWConnectAttachmentData{ID: "tgw-attach-connect", State: "pending"},
+			wantErr: "state is pending",
+		},
+		{
+			name: "BGP down on every peer",
+			data: &domain.TGWConnectAttachmentData{
+				ID:    "tgw-attach-connect",
+				State: "available",
+				Peers: []domain.TGWConnectPeerData{
+					{ID: "tgw-connect-peer-1", State: "available"},
+					{ID: "tgw-connect-peer-2", State: "deleting", BGPUp: true},
+				},
+			},
+			wantErr: "BGP session",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connect := NewTGWConnectAttachment(tt.data, "111111111111")
+			_, err := connect.GetNextHops(domain.RoutingTarget{IP: "172.16.5.5", Port: 443, Protocol: "tcp"}, nil)
+			if err == nil || !stringContains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func stringContains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
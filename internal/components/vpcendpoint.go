@@ -48,7 +48,7 @@ func (ve *VPCEndpoint) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 				Reason:      "analyzer context is required to resolve interface endpoint security groups",
 			}
 		}
-		client, err := analyzerCtx.GetAccountContext().GetClient(ve.accountID)
+		client, err := analyzerCtx.GetAccountContext().GetClient(ve.accountID, ve.data.Region)
 		if err != nil {
 			return nil, err
 		}
@@ -77,17 +77,8 @@ func (ve *VPCEndpoint) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain
 			}
 			subnet := NewSubnet(subnetData, ve.accountID)
 
-			var terminal domain.Component = subnet
-			for i := len(ve.data.SecurityGroups) - 1; i >= 0; i-- {
-				sgData, err := client.GetSecurityGroup(ctx, ve.data.SecurityGroups[i])
-				if err != nil {
-					terminal = nil
-					break
-				}
-				terminal = NewSecurityGroupWithNext(sgData, ve.accountID, terminal)
-			}
-
-			if terminal != nil {
+			terminal, err := securityGroupFilter(ctx, client, ve.data.SecurityGroups, ve.accountID, subnet)
+			if err == nil {
 				components = append(components, terminal)
 			}
 		}
@@ -130,7 +121,7 @@ func (ve *VPCEndpoint) GetVPCID() string {
 }
 
 func (ve *VPCEndpoint) GetRegion() string {
-	return ""
+	return ve.data.Region
 }
 
 func (ve *VPCEndpoint) GetSubnetID() string {
@@ -27,35 +27,86 @@ func (vp *VPCPeering) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.
 			Reason:      fmt.Sprintf("vpc peering connection state is %s, not active", vp.data.Status),
 		}
 	}
+	if dest.ViaPeering != "" {
+		return nil, &domain.BlockingError{
+			ComponentID: vp.GetID(),
+			Reason:      fmt.Sprintf("traffic that entered through VPC peering connection %s cannot be routed on through %s: peering is not transitive", dest.ViaPeering, vp.data.ID),
+		}
+	}
 
 	var targetVPCID string
 	var targetAccountID string
+	var targetRegion string
 
 	if vp.sourceVPCID == vp.data.RequesterVPC {
 		targetVPCID = vp.data.AccepterVPC
 		targetAccountID = vp.data.AccepterOwner
+		targetRegion = vp.data.AccepterRegion
 	} else {
 		targetVPCID = vp.data.RequesterVPC
 		targetAccountID = vp.data.RequesterOwner
+		targetRegion = vp.data.RequesterRegion
 	}
+	// An inter-region peering connection is looked up in either region, and
+	// the other VPC is only found with a client for its own region.
+	if targetRegion == "" {
+		targetRegion = vp.data.Region
+	}
+
+	accountCtx := analyzerCtx.GetAccountContext()
+	ctx := analyzerCtx.Context()
 
-	client, err := analyzerCtx.GetAccountContext().GetClient(targetAccountID)
+	client, err := accountCtx.GetClient(targetAccountID, targetRegion)
 	if err != nil {
 		return nil, err
 	}
-
-	ctx := analyzerCtx.Context()
-	vpcData, err := client.GetVPC(ctx, targetVPCID)
+	targetVPC, err := client.GetVPC(ctx, targetVPCID)
 	if err != nil {
 		return nil, err
 	}
+	if targetVPC.CIDRBlock != "" && !vpcContainsIP(targetVPC, dest.IP) {
+		return nil, &domain.BlockingError{
+			ComponentID: vp.GetID(),
+			Reason:      fmt.Sprintf("%s is outside peer VPC %s: peering only delivers to addresses in the peer VPC and is not transitive", dest.IP, targetVPCID),
+		}
+	}
 
-	rtData, err := client.GetRouteTable(ctx, vpcData.MainRouteTableID)
+	sourceClient, err := accountCtx.GetClient(vp.accountID, vp.data.Region)
+	if err != nil {
+		return nil, err
+	}
+	sourceVPC, err := sourceClient.GetVPC(ctx, vp.sourceVPCID)
 	if err != nil {
 		return nil, err
 	}
+	if local, peer := overlappingCIDRs(sourceVPC, targetVPC); local != "" {
+		return nil, &domain.BlockingError{
+			ComponentID: vp.GetID(),
+			Reason:      fmt.Sprintf("VPC %s (%s) overlaps peer VPC %s (%s): routes over a peering connection between overlapping CIDRs are invalid", vp.sourceVPCID, local, targetVPCID, peer),
+		}
+	}
 
-	return []domain.Component{NewRouteTable(rtData, targetAccountID)}, nil
+	return vpcIngress(analyzerCtx, client, targetAccountID, targetVPCID, dest.IP)
+}
+
+// Translate marks the flow as having crossed this connection, so it is not
+// routed out of the peer VPC again.
+func (vp *VPCPeering) Translate(target domain.RoutingTarget) domain.RoutingTarget {
+	target.ViaPeering = vp.data.ID
+	return target
+}
+
+// overlappingCIDRs returns the first CIDR of a that overlaps one of b, and
+// the CIDR of b it overlaps, or empty strings when none do.
+func overlappingCIDRs(a, b *domain.VPCData) (string, string) {
+	for _, cidrA := range vpcCIDRBlocks(a) {
+		for _, cidrB := range vpcCIDRBlocks(b) {
+			if CIDROverlaps(cidrA, cidrB) {
+				return cidrA, cidrB
+			}
+		}
+	}
+	return "", ""
 }
 
 func (vp *VPCPeering) GetRoutingTarget() domain.RoutingTarget {
@@ -66,10 +117,18 @@ func (vp *VPCPeering) GetID() string {
 	return fmt.Sprintf("%s:%s", vp.accountID, vp.data.ID)
 }
 
+func (vp *VPCPeering) GetStateKey() string {
+	return vp.GetID() + "<" + vp.sourceVPCID
+}
+
 func (vp *VPCPeering) GetAccountID() string {
 	return vp.accountID
 }
 
+func (vp *VPCPeering) IsSourceAgnostic() bool {
+	return true
+}
+
 func (vp *VPCPeering) GetComponentType() string {
 	return "VPCPeering"
 }
@@ -79,7 +138,7 @@ func (vp *VPCPeering) GetVPCID() string {
 }
 
 func (vp *VPCPeering) GetRegion() string {
-	return ""
+	return vp.data.Region
 }
 
 func (vp *VPCPeering) GetSubnetID() string {
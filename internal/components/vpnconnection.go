@@ -40,9 +40,43 @@ func (vpn *VPNConnection) GetNextHops(dest domain.RoutingTarget, analyzerCtx dom
 		}
 	}
 
+	if err := vpn.checkOnPremRoute(dest); err != nil {
+		return nil, err
+	}
+
 	return []domain.Component{NewIPTarget(&domain.IPTargetData{IP: dest.IP, Port: dest.Port}, vpn.accountID)}, nil
 }
 
+// checkOnPremRoute makes sure the connection knows where to send dest on the
+// customer side. A static connection on a virtual private gateway needs a
+// static route covering dest; a transit gateway keeps its static VPN routes in
+// its own route tables, which already matched. A dynamic connection needs to
+// have learned routes from the customer gateway over BGP.
+func (vpn *VPNConnection) checkOnPremRoute(dest domain.RoutingTarget) error {
+	if vpn.data.StaticRoutesOnly {
+		if vpn.data.VGWID == "" {
+			return nil
+		}
+		for _, cidr := range vpn.data.StaticRoutes {
+			if IPMatchesCIDR(dest.IP, cidr) {
+				return nil
+			}
+		}
+		return &domain.BlockingError{
+			ComponentID: vpn.GetID(),
+			Reason:      fmt.Sprintf("no static VPN route to %s", dest.IP),
+		}
+	}
+
+	if vpn.data.AcceptedRouteCount == 0 {
+		return &domain.BlockingError{
+			ComponentID: vpn.GetID(),
+			Reason:      "no routes learned from the customer gateway over BGP",
+		}
+	}
+	return nil
+}
+
 func (vpn *VPNConnection) GetRoutingTarget() domain.RoutingTarget {
 	return domain.RoutingTarget{}
 }
@@ -68,7 +102,7 @@ func (vpn *VPNConnection) GetVPCID() string {
 }
 
 func (vpn *VPNConnection) GetRegion() string {
-	return ""
+	return vpn.data.Region
 }
 
 func (vpn *VPNConnection) GetSubnetID() string {
@@ -19,7 +19,7 @@ func NewVirtualPrivateGateway(data *domain.VirtualPrivateGatewayData, accountID
 }
 
 func (vgw *VirtualPrivateGateway) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
-	client, err := analyzerCtx.GetAccountContext().GetClient(vgw.accountID)
+	client, err := analyzerCtx.GetAccountContext().GetClient(vgw.accountID, vgw.data.Region)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (vgw *VirtualPrivateGateway) GetVPCID() string {
 }
 
 func (vgw *VirtualPrivateGateway) GetRegion() string {
-	return ""
+	return vgw.data.Region
 }
 
 func (vgw *VirtualPrivateGateway) GetSubnetID() string {
@@ -76,3 +76,151 @@ func (vgw *VirtualPrivateGateway) GetSubnetID() string {
 func (vgw *VirtualPrivateGateway) GetAvailabilityZone() string {
 	return ""
 }
+
+// VirtualPrivateGatewayIngress is a virtual private gateway delivering traffic
+// from its VPN connections into its VPC. When a route table is edge-associated
+// with the gateway and routes the destination to an appliance, traffic is
+// handed to that route table instead of the destination's subnet.
+type VirtualPrivateGatewayIngress struct {
+	data      *domain.VirtualPrivateGatewayData
+	accountID string
+}
+
+func NewVirtualPrivateGatewayIngress(data *domain.VirtualPrivateGatewayData, accountID string) *VirtualPrivateGatewayIngress {
+	return &VirtualPrivateGatewayIngress{
+		data:      data,
+		accountID: accountID,
+	}
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	if vgw.data.VPCID == "" {
+		return nil, &domain.BlockingError{
+			ComponentID: vgw.GetID(),
+			Reason:      "virtual private gateway is not attached to a VPC",
+		}
+	}
+
+	client, err := analyzerCtx.GetAccountContext().GetClient(vgw.accountID, vgw.data.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ingressTable, err := gatewayIngressTable(analyzerCtx, client, vgw.data.ID, vgw.accountID, dest.IP)
+	if err != nil || ingressTable != nil {
+		return ingressTable, err
+	}
+
+	return vpcIngress(analyzerCtx, client, vgw.accountID, vgw.data.VPCID, dest.IP)
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{}
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetID() string {
+	return fmt.Sprintf("%s:%s", vgw.accountID, vgw.data.ID)
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetAccountID() string {
+	return vgw.accountID
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetComponentType() string {
+	return "VirtualPrivateGateway"
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetVPCID() string {
+	return vgw.data.VPCID
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetRegion() string {
+	return vgw.data.Region
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetSubnetID() string {
+	return ""
+}
+
+func (vgw *VirtualPrivateGatewayIngress) GetAvailabilityZone() string {
+	return ""
+}
+
+// VPNOnPrem is an on-premises address whose traffic reaches AWS over a
+// Site-to-Site VPN connection and enters the VPC through its virtual private
+// gateway.
+type VPNOnPrem struct {
+	data      *domain.VPNOnPremData
+	accountID string
+}
+
+func NewVPNOnPrem(data *domain.VPNOnPremData, accountID string) *VPNOnPrem {
+	return &VPNOnPrem{
+		data:      data,
+		accountID: accountID,
+	}
+}
+
+func (v *VPNOnPrem) GetNextHops(dest domain.RoutingTarget, analyzerCtx domain.AnalyzerContext) ([]domain.Component, error) {
+	client, err := analyzerCtx.GetAccountContext().GetClient(v.accountID, v.data.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := analyzerCtx.Context()
+
+	vgwData, err := client.GetVirtualPrivateGateway(ctx, v.data.VGWID)
+	if err != nil {
+		return nil, err
+	}
+
+	vpnConns, err := client.GetVPNConnectionsByVGW(ctx, v.data.VGWID)
+	if err != nil {
+		return nil, err
+	}
+	for _, vpn := range vpnConns {
+		if (vpn.State == "" || vpn.State == "available") && vpn.HasUpTunnel {
+			return []domain.Component{NewVirtualPrivateGatewayIngress(vgwData, v.accountID)}, nil
+		}
+	}
+
+	return nil, &domain.BlockingError{
+		ComponentID: v.GetID(),
+		Reason:      fmt.Sprintf("no available VPN connection with a tunnel up on %s", v.data.VGWID),
+	}
+}
+
+func (v *VPNOnPrem) GetRoutingTarget() domain.RoutingTarget {
+	return domain.RoutingTarget{
+		IP:              v.data.SourceIP,
+		SourceIsPrivate: true,
+	}
+}
+
+func (v *VPNOnPrem) GetID() string {
+	return fmt.Sprintf("%s:vpnonprem:%s", v.accountID, v.data.SourceIP)
+}
+
+func (v *VPNOnPrem) GetAccountID() string {
+	return v.accountID
+}
+
+func (v *VPNOnPrem) GetComponentType() string {
+	return "VPNOnPrem"
+}
+
+func (v *VPNOnPrem) GetVPCID() string {
+	return ""
+}
+
+func (v *VPNOnPrem) GetRegion() string {
+	return v.data.Region
+}
+
+func (v *VPNOnPrem) GetSubnetID() string {
+	return ""
+}
+
+func (v *VPNOnPrem) GetAvailabilityZone() string {
+	return ""
+}
@@ -5,6 +5,10 @@ type SecurityGroupData struct {
 	VPCID         string
 	InboundRules  []SecurityGroupRule
 	OutboundRules []SecurityGroupRule
+	// Region is the region the resource was described in. Components built
+	// from the data look up related resources with that region's client; empty
+	// means the account context's default region.
+	Region string
 }
 
 type SecurityGroupRule struct {
@@ -25,6 +29,7 @@ type SubnetData struct {
 	IPv6CIDRBlock    string
 	NaclID           string
 	RouteTableID     string
+	Region           string
 }
 
 type NACLData struct {
@@ -32,6 +37,7 @@ type NACLData struct {
 	VPCID         string
 	InboundRules  []NACLRule
 	OutboundRules []NACLRule
+	Region        string
 }
 
 type NACLRule struct {
@@ -45,9 +51,20 @@ type NACLRule struct {
 }
 
 type RouteTableData struct {
-	ID     string
-	VPCID  string
-	Routes []Route
+	ID           string
+	VPCID        string
+	Routes       []Route
+	Associations []RouteTableAssociation
+	Region       string
+}
+
+// RouteTableAssociation ties a route table to a subnet, to a gateway whose
+// inbound traffic it routes (an edge association), or to its VPC as the main
+// route table.
+type RouteTableAssociation struct {
+	SubnetID  string
+	GatewayID string
+	Main      bool
 }
 
 type Route struct {
@@ -57,13 +74,30 @@ type Route struct {
 	PrefixLength            int
 	TargetType              string
 	TargetID                string
+	// State is "active", or "blackhole" when the target was deleted. A
+	// blackhole route still wins longest-prefix match and drops the traffic.
+	State string
+	// Origin is "local" for the VPC's own CIDRs, "static" for routes added to
+	// the table and "propagated" for routes a virtual private gateway
+	// propagated into it.
+	Origin string
+	// PropagatedFrom is where a propagated route was learned: "direct-connect",
+	// "vpn-static" or "vpn-bgp". It is empty when the source is unknown.
+	PropagatedFrom string
+	// PropagationErr is why PropagatedFrom could not be determined, leaving
+	// the route's priority against others to the same prefix undecided.
+	PropagationErr error
 }
 
 type VPCData struct {
-	ID               string
-	CIDRBlock        string
-	IPv6CIDRBlock    string
-	MainRouteTableID string
+	ID        string
+	CIDRBlock string
+	// SecondaryCIDRBlocks are the IPv4 CIDRs associated with the VPC besides
+	// CIDRBlock.
+	SecondaryCIDRBlocks []string
+	IPv6CIDRBlocks      []string
+	MainRouteTableID    string
+	Region              string
 }
 
 type TGWRouteAttachment struct {
@@ -105,6 +139,7 @@ type TransitGatewayData struct {
 	ID          string
 	OwnerID     string
 	RouteTables []TGWRouteTableData
+	Region      string
 }
 
 type TGWAttachmentData struct {
@@ -115,22 +150,33 @@ type TGWAttachmentData struct {
 	SubnetIDs               []string
 	State                   string
 	PropagatedRouteTableIDs []string
+	// ApplianceModeSupport is set when the transit gateway keeps both
+	// directions of a flow on the same availability zone of the VPC, which
+	// stateful appliances in an inspection VPC rely on.
+	ApplianceModeSupport bool
+	Region               string
 }
 
 type EC2InstanceData struct {
 	ID             string
 	PrivateIP      string
+	IPv6Addresses  []string
 	SecurityGroups []string
 	SubnetID       string
+	VPCID          string
+	Region         string
 }
 
 type RDSInstanceData struct {
 	ID             string
 	Endpoint       string
 	PrivateIP      string
+	IPv6Addresses  []string
 	Port           int
 	SecurityGroups []string
 	SubnetIDs      []string
+	VPCID          string
+	Region         string
 }
 
 type LambdaFunctionData struct {
@@ -139,23 +185,33 @@ type LambdaFunctionData struct {
 	SubnetIDs      []string
 	SubnetCIDRs    []string
 	SecurityGroups []string
-	ENIIPs         []string
+	// ENIIPs are the addresses of the network interfaces the function sends
+	// from, IPv6 ones included only when it is dual-stack.
+	ENIIPs []string
+	// DualStack is set when the function may send IPv6 traffic.
+	DualStack bool
+	Region    string
 }
 
 type InternetGatewayData struct {
-	ID    string
-	VPCID string
+	ID     string
+	VPCID  string
+	Region string
 }
 
 type EgressOnlyInternetGatewayData struct {
-	ID    string
-	VPCID string
+	ID     string
+	VPCID  string
+	Region string
 }
 
 type NATGatewayData struct {
-	ID       string
-	SubnetID string
-	PublicIP string
+	ID               string
+	SubnetID         string
+	PublicIP         string
+	PrivateIP        string
+	ConnectivityType string // "public" or "private"
+	Region           string
 }
 
 type VPCEndpointData struct {
@@ -167,20 +223,25 @@ type VPCEndpointData struct {
 	SubnetIDs      []string
 	SecurityGroups []string
 	PolicyJSON     string
+	Region         string
 }
 
 type VPCPeeringData struct {
-	ID             string
-	RequesterVPC   string
-	RequesterOwner string
-	AccepterVPC    string
-	AccepterOwner  string
-	Status         string
+	ID              string
+	RequesterVPC    string
+	RequesterOwner  string
+	RequesterRegion string
+	AccepterVPC     string
+	AccepterOwner   string
+	AccepterRegion  string
+	Status          string
+	Region          string
 }
 
 type VirtualPrivateGatewayData struct {
-	ID    string
-	VPCID string
+	ID     string
+	VPCID  string
+	Region string
 }
 
 type VPNConnectionData struct {
@@ -188,13 +249,25 @@ type VPNConnectionData struct {
 	VGWID       string
 	State       string
 	HasUpTunnel bool
+	// StaticRoutesOnly is set when the connection uses static routes rather
+	// than BGP; StaticRoutes lists their destination CIDRs.
+	StaticRoutesOnly bool
+	StaticRoutes     []string
+	// AcceptedRouteCount is the number of routes the up tunnels learned from
+	// the customer gateway over BGP.
+	AcceptedRouteCount int
+	Region             string
 }
 
 type DirectConnectGatewayData struct {
-	ID              string
-	OwnerID         string
-	State           string
-	AllowedPrefixes []string
+	ID      string
+	OwnerID string
+	State   string
+	// AllowedPrefixes are the AWS prefixes the gateway advertises to
+	// on-premises networks, by the ID of the transit or virtual private
+	// gateway whose association they are configured on. Every associated
+	// gateway has an entry, even one that advertises nothing.
+	AllowedPrefixes map[string][]string
 }
 
 type TGWPeeringAttachmentData struct {
@@ -202,6 +275,31 @@ type TGWPeeringAttachmentData struct {
 	TransitGatewayID     string
 	PeerTransitGatewayID string
 	PeerAccountID        string
+	// PeerRegion is the region of the peer transit gateway, which differs
+	// from Region for inter-region peering.
+	PeerRegion string
+	Region     string
+}
+
+// TGWConnectAttachmentData is a transit gateway Connect attachment, which
+// runs GRE tunnels to appliances such as SD-WAN routers over a transport VPC
+// or Direct Connect attachment.
+type TGWConnectAttachmentData struct {
+	ID                    string
+	TransitGatewayID      string
+	TransportAttachmentID string
+	State                 string
+	Peers                 []TGWConnectPeerData
+	Region                string
+}
+
+// TGWConnectPeerData is one GRE tunnel of a Connect attachment. BGPUp is set
+// when at least one of its BGP sessions is established.
+type TGWConnectPeerData struct {
+	ID          string
+	State       string
+	PeerAddress string
+	BGPUp       bool
 }
 
 type ENIData struct {
@@ -210,6 +308,17 @@ type ENIData struct {
 	PrivateIPs     []string
 	SubnetID       string
 	SecurityGroups []string
+	VPCID          string
+	// PublicIP is the public IPv4 address or Elastic IP associated with the
+	// primary private address, if any.
+	PublicIP      string
+	IPv6Addresses []string
+	// InterfaceType is the EC2 interface type, e.g. "interface", "lambda" or
+	// "natGateway".
+	InterfaceType string
+	InstanceID    string
+	Description   string
+	Region        string
 }
 
 type ManagedPrefixListData struct {
@@ -231,7 +340,12 @@ type ALBData struct {
 	SubnetIDs       []string
 	SecurityGroups  []string
 	TargetGroupARNs []string
+	Listeners       []ListenerData
+	// ListenersLoaded reports whether Listeners was fetched. A load balancer
+	// whose listeners were fetched and found empty accepts no traffic.
+	ListenersLoaded bool
 	FrontendIPs     []string
+	Region          string
 }
 
 type NLBData struct {
@@ -242,7 +356,27 @@ type NLBData struct {
 	SubnetIDs       []string
 	SecurityGroups  []string
 	TargetGroupARNs []string
+	Listeners       []ListenerData
+	// ListenersLoaded reports whether Listeners was fetched. A load balancer
+	// whose listeners were fetched and found empty accepts no traffic.
+	ListenersLoaded bool
 	FrontendIPs     []string
+	// FrontendIPsByAZ is the private address of the load balancer's node in
+	// each Availability Zone it is enabled in.
+	FrontendIPsByAZ map[string]string
+	// CrossZone reports whether cross-zone load balancing is on, letting
+	// every node send to targets in every zone.
+	CrossZone bool
+	Region    string
+}
+
+// ListenerData is a load balancer listener and the target groups its default
+// action and forward rules send traffic to.
+type ListenerData struct {
+	ARN             string
+	Port            int
+	Protocol        string
+	TargetGroupARNs []string
 }
 
 type GWLBData struct {
@@ -251,6 +385,7 @@ type GWLBData struct {
 	VPCID           string
 	SubnetIDs       []string
 	TargetGroupARNs []string
+	Region          string
 }
 
 type CLBData struct {
@@ -262,6 +397,7 @@ type CLBData struct {
 	SecurityGroups []string
 	InstanceIDs    []string
 	FrontendIPs    []string
+	Region         string
 }
 
 type TargetGroupData struct {
@@ -272,12 +408,20 @@ type TargetGroupData struct {
 	Port       int
 	VPCID      string
 	Targets    []TargetData
+	// PreserveClientIP reports whether targets see the client's address rather
+	// than the load balancer's. Only network load balancers can turn it off.
+	PreserveClientIP bool
+	Region           string
 }
 
 type TargetData struct {
 	ID           string
 	Port         int
 	HealthStatus string
+	// AvailabilityZone is the target's zone as the target group reports it:
+	// set for IP targets, "all" for those outside the VPC, and empty for
+	// instance targets.
+	AvailabilityZone string
 }
 
 type IPTargetData struct {
@@ -293,6 +437,7 @@ type APIGatewayData struct {
 	VPCEndpointIDs []string
 	VPCLinkIDs     []string
 	PrivateIPs     []string
+	Region         string
 }
 
 type VPCLinkData struct {
@@ -305,6 +450,7 @@ type VPCLinkData struct {
 	SecurityGroups     []string
 	Status             string
 	IntegrationTargets []string
+	Region             string
 }
 
 type EKSPodData struct {
@@ -316,6 +462,8 @@ type EKSPodData struct {
 	ENIID          string
 	SecurityGroups []string
 	SubnetID       string
+	VPCID          string
+	Region         string
 }
 
 type ElastiCacheClusterData struct {
@@ -329,13 +477,26 @@ type ElastiCacheClusterData struct {
 	SecurityGroups []string
 	SubnetIDs      []string
 	VPCID          string
+	Region         string
 }
 
 type ElastiCacheNodeData struct {
 	ID        string
 	Endpoint  string
 	PrivateIP string
-	Port      int
+	// IPv6Addresses are the node's IPv6 addresses in a dual-stack or IPv6
+	// cluster.
+	IPv6Addresses []string
+	Port          int
+}
+
+// VPNOnPremData is an on-premises address sending traffic into a VPC over a
+// Site-to-Site VPN connection that terminates on the VPC's virtual private
+// gateway.
+type VPNOnPremData struct {
+	SourceIP string
+	VGWID    string
+	Region   string
 }
 
 type DirectConnectOnPremData struct {
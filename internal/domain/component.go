@@ -26,3 +26,48 @@ type MetadataProvider interface {
 	GetSubnetID() string
 	GetAvailabilityZone() string
 }
+
+// IngressProvider is implemented by destinations that sit behind their own
+// network interface. The returned hops are evaluated inbound once the
+// destination has been reached.
+type IngressProvider interface {
+	Component
+	GetIngressHops(analyzerCtx AnalyzerContext) ([]Component, error)
+}
+
+// StateKeyProvider is implemented by components whose next hops depend on more
+// than their ID, such as a security group chained to a particular next hop.
+// The traverser uses the key for loop detection and memoization.
+type StateKeyProvider interface {
+	GetStateKey() string
+}
+
+// SourceAgnostic is implemented by components whose next hops never depend on
+// the flow's source, such as route tables. Their expansions are memoized
+// without the source, so analyses from different sources share them.
+type SourceAgnostic interface {
+	IsSourceAgnostic() bool
+}
+
+// Translator is implemented by components that rewrite the addresses of the
+// traffic passing through them, such as a NAT gateway replacing the source with
+// its own address. The traverser hands the translated flow to the component's
+// next hops and records any address rewrite on its hop.
+type Translator interface {
+	Translate(target RoutingTarget) RoutingTarget
+}
+
+// Advisor is implemented by components that let a flow through but can see a
+// risk to it that the path alone does not show, such as replies that may come
+// back through a different appliance. The traverser records the advisory on
+// the component's hop.
+type Advisor interface {
+	Advise(target RoutingTarget, analyzerCtx AnalyzerContext) string
+}
+
+// DualStackComponent is implemented by components that can have IPv6
+// addresses as well as an IPv4 one. Flows over IPv6 start from and are
+// addressed to the first address returned.
+type DualStackComponent interface {
+	GetIPv6Addresses() []string
+}
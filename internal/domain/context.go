@@ -14,16 +14,21 @@ type AWSCredentials struct {
 
 type AccountContext interface {
 	AssumeRole(accountID string) (AWSCredentials, error)
-	GetClient(accountID string) (AWSClient, error)
+	// GetClient returns the client for accountID in region. An empty region
+	// means the default region the account context was configured with.
+	GetClient(accountID, region string) (AWSClient, error)
 }
 
 type AWSClient interface {
 	GetSecurityGroup(ctx context.Context, sgID string) (*SecurityGroupData, error)
 	GetSubnet(ctx context.Context, subnetID string) (*SubnetData, error)
+	GetSubnetByIP(ctx context.Context, ip, vpcID string) (*SubnetData, error)
 	GetNACL(ctx context.Context, naclID string) (*NACLData, error)
 	GetRouteTable(ctx context.Context, rtID string) (*RouteTableData, error)
+	GetGatewayRouteTable(ctx context.Context, gatewayID string) (*RouteTableData, error)
 	GetVPC(ctx context.Context, vpcID string) (*VPCData, error)
 	GetInternetGateway(ctx context.Context, igwID string) (*InternetGatewayData, error)
+	GetInternetGatewayByVPC(ctx context.Context, vpcID string) (*InternetGatewayData, error)
 	GetEgressOnlyInternetGateway(ctx context.Context, eigwID string) (*EgressOnlyInternetGatewayData, error)
 	GetNATGateway(ctx context.Context, natID string) (*NATGatewayData, error)
 	GetVPCEndpoint(ctx context.Context, endpointID string) (*VPCEndpointData, error)
@@ -42,10 +47,14 @@ type AWSClient interface {
 	GetVPNConnection(ctx context.Context, vpnID string) (*VPNConnectionData, error)
 	GetVPNConnectionsByVGW(ctx context.Context, vgwID string) ([]*VPNConnectionData, error)
 	GetDirectConnectGateway(ctx context.Context, dxgwID string) (*DirectConnectGatewayData, error)
-	GetTGWPeeringAttachment(ctx context.Context, attachmentID string) (*TGWPeeringAttachmentData, error)
+	// GetTGWPeeringAttachment returns a peering attachment as seen from
+	// tgwID, one of the two transit gateways it connects.
+	GetTGWPeeringAttachment(ctx context.Context, attachmentID, tgwID string) (*TGWPeeringAttachmentData, error)
+	GetTGWConnectAttachment(ctx context.Context, attachmentID string) (*TGWConnectAttachmentData, error)
 	GetNetworkInterface(ctx context.Context, eniID string) (*ENIData, error)
 
 	GetENIsBySecurityGroup(ctx context.Context, sgID string) ([]ENIData, error)
+	GetENIsByVPC(ctx context.Context, vpcID string) ([]ENIData, error)
 	GetNetworkInterfaceByPrivateIP(ctx context.Context, ip, vpcID string) (*ENIData, error)
 	GetEC2InstanceByPrivateIP(ctx context.Context, ip, vpcID string) (*EC2InstanceData, error)
 	GetRDSInstanceByPrivateIP(ctx context.Context, ip, vpcID string) (*RDSInstanceData, error)
@@ -68,6 +77,7 @@ type AWSClient interface {
 	GetVPCLinkV2(ctx context.Context, vpcLinkID string) (*VPCLinkData, error)
 	GetAPIGatewayByVPCEndpoint(ctx context.Context, vpceID string) (*APIGatewayData, error)
 	GetAPIGatewayByPrivateIP(ctx context.Context, ip, vpcID string) (*APIGatewayData, error)
+	GetAPIGatewaysByVPC(ctx context.Context, vpcID string) ([]APIGatewayData, error)
 
 	GetEKSPodByIP(ctx context.Context, ip, vpcID string) (*EKSPodData, error)
 
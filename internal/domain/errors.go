@@ -1,5 +1,15 @@
 package domain
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BlockingError means the network configuration denies the traffic. It is the
+// only error that makes a path definitively blocked; every other error means
+// the analysis could not decide.
 type BlockingError struct {
 	ComponentID string
 	Reason      string
@@ -8,3 +18,133 @@ type BlockingError struct {
 func (e *BlockingError) Error() string {
 	return e.Reason
 }
+
+// AccessDeniedError means the credentials in use may not read a resource,
+// either because a role could not be assumed or a describe call was refused.
+type AccessDeniedError struct {
+	Code string
+	Err  error
+}
+
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("access denied (%s): %v", e.Code, e.Err)
+}
+
+func (e *AccessDeniedError) Unwrap() error { return e.Err }
+
+// NotFoundError means a resource referenced by the configuration does not
+// exist or is not visible to the credentials in use.
+type NotFoundError struct {
+	Kind string
+	ID   string
+	Err  error
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Kind == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s %s not found", e.Kind, e.ID)
+}
+
+func (e *NotFoundError) Unwrap() error { return e.Err }
+
+// TransientError means a call failed for reasons that may not recur, such as
+// throttling, a service outage or a timeout.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error { return e.Err }
+
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"UnauthorizedOperation":       true,
+	"UnauthorizedAccess":          true,
+	"AuthFailure":                 true,
+	"InvalidClientTokenId":        true,
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"SignatureDoesNotMatch":       true,
+	"UnrecognizedClientException": true,
+}
+
+var transientCodes = map[string]bool{
+	"Throttling":                  true,
+	"ThrottlingException":         true,
+	"ThrottledException":          true,
+	"RequestLimitExceeded":        true,
+	"RequestThrottled":            true,
+	"RequestThrottledException":   true,
+	"TooManyRequestsException":    true,
+	"SlowDown":                    true,
+	"ServiceUnavailable":          true,
+	"ServiceUnavailableException": true,
+	"InternalError":               true,
+	"InternalFailure":             true,
+	"InternalServerError":         true,
+	"RequestTimeout":              true,
+	"RequestTimeoutException":     true,
+}
+
+// ClassifyError maps err onto the error taxonomy using the AWS error code it
+// carries. Errors that are already classified, and those that cannot be, are
+// returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		blocking  *BlockingError
+		denied    *AccessDeniedError
+		notFound  *NotFoundError
+		transient *TransientError
+	)
+	if errors.As(err, &blocking) || errors.As(err, &denied) || errors.As(err, &notFound) || errors.As(err, &transient) {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &TransientError{Err: err}
+	}
+
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) {
+		code := coded.ErrorCode()
+		switch {
+		case accessDeniedCodes[code]:
+			return &AccessDeniedError{Code: code, Err: err}
+		case transientCodes[code]:
+			return &TransientError{Err: err}
+		case strings.HasSuffix(code, "NotFound") || strings.HasSuffix(code, "NotFoundException") || strings.HasSuffix(code, "NotFoundFault"):
+			return &NotFoundError{Err: err}
+		}
+	}
+
+	var status interface{ HTTPStatusCode() int }
+	if errors.As(err, &status) && status.HTTPStatusCode() >= 500 {
+		return &TransientError{Err: err}
+	}
+
+	return err
+}
+
+// IsNotFound reports whether err, once classified, means the resource does
+// not exist.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(ClassifyError(err), &notFound)
+}
+
+// IsBlocking reports whether err is a configuration block rather than a failure
+// to analyze.
+func IsBlocking(err error) bool {
+	var blocking *BlockingError
+	return errors.As(err, &blocking)
+}
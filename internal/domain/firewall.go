@@ -9,6 +9,11 @@ type NetworkFirewallData struct {
 	StatelessRuleGroups []StatelessRuleGroup
 	StatefulRuleGroups  []StatefulRuleGroup
 	DefaultActions      FirewallDefaultActions
+	// Variables are the policy's IP set variables, by name without the $,
+	// which override those of the same name in its rule groups. HOME_NET
+	// defaults to the firewall VPC's CIDRs.
+	Variables map[string][]string
+	Region    string
 }
 
 type FirewallSubnetMapping struct {
@@ -58,6 +63,8 @@ type StatefulRuleGroup struct {
 	ARN       string
 	RuleOrder string
 	Rules     []StatefulRule
+	// Variables are the rule group's IP set variables, by name without the $.
+	Variables map[string][]string
 }
 
 type StatefulRule struct {
@@ -77,3 +84,7 @@ func (p PortRangeSpec) Contains(port int) bool {
 	}
 	return port >= p.From && port <= p.To
 }
+
+func (p PortRangeSpec) ContainsRange(from, to int) bool {
+	return p.Contains(from) && p.Contains(to)
+}
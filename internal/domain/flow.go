@@ -0,0 +1,65 @@
+package domain
+
+// FlowSpec describes the traffic being tested. A zero FlowSpec leaves the
+// destination's own routing target untouched.
+type FlowSpec struct {
+	Protocol string
+	// FromPort and ToPort bound the destination port range. ToPort may be left
+	// zero to test a single port.
+	FromPort   int
+	ToPort     int
+	SourcePort int
+	// Ephemeral is the client port range replies are addressed to when
+	// SourcePort is not set. The zero value means EphemeralDefault.
+	Ephemeral PortRangeSpec
+	// Family is the IP version the flow uses. The zero value means IPv4,
+	// unless the source or destination is itself an IPv6 address.
+	Family AddressFamily
+}
+
+// AddressFamily is an IP version. Resources are addressed by their private
+// IPv4 address over IPv4 and by their first IPv6 address over IPv6.
+type AddressFamily string
+
+const (
+	AddressFamilyIPv4 AddressFamily = "ipv4"
+	AddressFamilyIPv6 AddressFamily = "ipv6"
+)
+
+// Ephemeral port ranges used by common client operating systems. NAT gateways
+// and load balancers use EphemeralDefault.
+var (
+	EphemeralDefault = PortRangeSpec{From: 1024, To: 65535}
+	EphemeralLinux   = PortRangeSpec{From: 32768, To: 60999}
+	EphemeralWindows = PortRangeSpec{From: 49152, To: 65535}
+)
+
+func (f FlowSpec) Apply(target RoutingTarget) RoutingTarget {
+	if f.Protocol != "" {
+		target.Protocol = f.Protocol
+	}
+	if f.FromPort != 0 || f.ToPort != 0 {
+		target.Port = f.FromPort
+		target.PortTo = 0
+		if f.ToPort > f.FromPort {
+			target.PortTo = f.ToPort
+		}
+	}
+	if f.SourcePort != 0 {
+		target.SourcePort = f.SourcePort
+	}
+	return target
+}
+
+// ReplyPorts returns the destination port range of the reply traffic: the
+// client's source port if known, otherwise its ephemeral range.
+func (f FlowSpec) ReplyPorts() (int, int) {
+	if f.SourcePort != 0 {
+		return f.SourcePort, 0
+	}
+	ephemeral := f.Ephemeral
+	if ephemeral == (PortRangeSpec{}) {
+		ephemeral = EphemeralDefault
+	}
+	return ephemeral.From, ephemeral.To
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// DefaultConcurrency is the number of branches explored at once when Options
+// leaves Concurrency unset.
+const DefaultConcurrency = 8
+
+// Strategy selects how much of the network graph an analysis explores.
+type Strategy string
+
+const (
+	// StrategyFirstPath stops at the first path that reaches the destination.
+	StrategyFirstPath Strategy = "first-path"
+	// StrategyAllPaths explores every path to the destination.
+	StrategyAllPaths Strategy = "all-paths"
+)
+
+// Options bounds and tunes an analysis. The zero value tests the destination's
+// default routing target along the first working path, without limits.
+type Options struct {
+	Flow FlowSpec
+	// Strategy defaults to StrategyFirstPath.
+	Strategy Strategy
+	// MaxDepth caps the number of hops on a single path. Zero means no limit.
+	MaxDepth int
+	// MaxPaths caps the number of traces collected per direction when
+	// exploring all paths. Zero means no limit.
+	MaxPaths int
+	// PerCallTimeout bounds each component's AWS lookups. Zero means calls are
+	// only bounded by the context passed to the analysis.
+	PerCallTimeout time.Duration
+	// Concurrency bounds how many branches are explored at once across the
+	// whole analysis. Zero means DefaultConcurrency; one explores branches
+	// sequentially.
+	Concurrency int
+}
+
+func (o Options) EffectiveConcurrency() int {
+	if o.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return o.Concurrency
+}
@@ -25,11 +25,33 @@ type ComponentHop struct {
 	SourceID     string
 	SourceType   string
 	Relationship string
+	// CrossRegion is set when the hop is in a different region than the
+	// component traffic came from, such as the far side of an inter-region
+	// peering.
+	CrossRegion bool
 
 	Action  HopAction
 	Details string
+	// Error is the underlying error when the path stopped here because the
+	// analysis failed (access denied, missing resource, throttling) rather
+	// than because the configuration blocks the traffic.
+	Error error
 
 	RuleEvaluations []RuleEvaluation
+
+	// Translation is set when the component rewrote the flow's addresses.
+	// Hops after it see the translated flow.
+	Translation *Translation
+
+	// Advisory warns about a risk to the flow at this hop that does not block
+	// it, such as a return path that may not be symmetric.
+	Advisory string
+}
+
+// Translation is the flow before and after a component rewrote it.
+type Translation struct {
+	Before Flow
+	After  Flow
 }
 
 type PathTrace struct {
@@ -118,6 +140,7 @@ func (p *PathTrace) Depth() int {
 type HopLineage struct {
 	SourceID     string
 	SourceType   string
+	SourceRegion string
 	Relationship string
 }
 
@@ -147,6 +170,7 @@ func HopFromComponent(c Component, lineage HopLineage, action HopAction, details
 		hop.SubnetID = mp.GetSubnetID()
 		hop.AvailabilityZone = mp.GetAvailabilityZone()
 	}
+	hop.CrossRegion = lineage.SourceRegion != "" && hop.Region != "" && lineage.SourceRegion != hop.Region
 
 	return hop
 }
@@ -3,7 +3,7 @@ package domain
 import "context"
 
 type DestinationResolver interface {
-	ResolveByIP(ctx context.Context, accountID, vpcID, ip string) (Component, error)
+	ResolveByIP(ctx context.Context, accountID, region, vpcID, ip string) (Component, error)
 	ResolveByID(ctx context.Context, id string) (Component, error)
 }
 
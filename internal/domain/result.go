@@ -22,16 +22,93 @@ func (b BlockedResult) GetBlockingReason() string {
 	return fmt.Sprintf("Blocked at %s: %s", b.BlockingComponent.GetID(), b.Reason.Error())
 }
 
+// Verdict is the conclusion of an analysis.
+type Verdict string
+
+const (
+	VerdictReachable Verdict = "reachable"
+	// VerdictBlocked means the configuration denies the traffic.
+	VerdictBlocked Verdict = "blocked"
+	// VerdictUnknown means the analysis could not decide, because an AWS call
+	// failed or the analysis was cut short.
+	VerdictUnknown Verdict = "unknown"
+)
+
+// VerdictOf returns the verdict for a single leg.
+func VerdictOf(result PathResult) Verdict {
+	blocked, ok := result.(BlockedResult)
+	if !ok {
+		if result.IsBlocked() {
+			return VerdictUnknown
+		}
+		return VerdictReachable
+	}
+	if IsBlocking(blocked.Reason) {
+		return VerdictBlocked
+	}
+	return VerdictUnknown
+}
+
+// CombineVerdicts returns blocked if either leg is definitively blocked, since
+// the connection then fails regardless of what the other leg would do.
+func CombineVerdicts(forward, reply Verdict) Verdict {
+	switch {
+	case forward == VerdictBlocked || reply == VerdictBlocked:
+		return VerdictBlocked
+	case forward == VerdictUnknown || reply == VerdictUnknown:
+		return VerdictUnknown
+	default:
+		return VerdictReachable
+	}
+}
+
+// PathsVerdict returns the verdict for one direction of an all-paths analysis.
+// Without a successful path, the direction is only blocked if every path ended
+// on a configuration block and no limit cut the exploration short.
+func PathsVerdict(paths []*PathTrace, truncated bool) Verdict {
+	undecided := truncated
+	for _, p := range paths {
+		if p.Success {
+			return VerdictReachable
+		}
+		if p.BlockedAt == nil || p.BlockedAt.Error != nil {
+			undecided = true
+		}
+	}
+	if undecided || len(paths) == 0 {
+		return VerdictUnknown
+	}
+	return VerdictBlocked
+}
+
 type ReachabilityResult struct {
+	// Verdict separates traffic the configuration blocks from analyses that
+	// could not decide, e.g. because a describe call was denied.
+	Verdict             Verdict
 	SourceToDestination PathResult
+	// DestinationToSource is the reply leg of the connection.
 	DestinationToSource PathResult
-	OverallSuccess      bool
-	ForwardPath         *PathTrace
-	ReturnPath          *PathTrace
+	// OverallSuccess means the connection can be established: the request
+	// reaches the destination and its replies make it back to the source.
+	OverallSuccess bool
+	ForwardPath    *PathTrace
+	ReturnPath     *PathTrace
+	// Truncated is set when a limit or the context cut the analysis short, so
+	// a blocked result may only mean the path was not fully explored.
+	Truncated       bool
+	TruncatedReason string
+}
+
+// DualStackResult is the outcome of the same flow tested over IPv4 and over
+// IPv6.
+type DualStackResult struct {
+	IPv4 ReachabilityResult
+	IPv6 ReachabilityResult
 }
 
 func CombineResults(srcToDest, destToSrc PathResult) ReachabilityResult {
 	return ReachabilityResult{
+		Verdict:             CombineVerdicts(VerdictOf(srcToDest), VerdictOf(destToSrc)),
 		SourceToDestination: srcToDest,
 		DestinationToSource: destToSrc,
 		OverallSuccess:      !srcToDest.IsBlocked() && !destToSrc.IsBlocked(),
@@ -40,6 +117,7 @@ func CombineResults(srcToDest, destToSrc PathResult) ReachabilityResult {
 
 func CombineResultsWithTrace(srcToDest, destToSrc PathResult, forwardTrace, returnTrace *PathTrace) ReachabilityResult {
 	return ReachabilityResult{
+		Verdict:             CombineVerdicts(VerdictOf(srcToDest), VerdictOf(destToSrc)),
 		SourceToDestination: srcToDest,
 		DestinationToSource: destToSrc,
 		OverallSuccess:      !srcToDest.IsBlocked() && !destToSrc.IsBlocked(),
@@ -49,11 +127,16 @@ func CombineResultsWithTrace(srcToDest, destToSrc PathResult, forwardTrace, retu
 }
 
 type AllPathsResult struct {
+	Verdict                Verdict
 	ForwardPaths           []*PathTrace
 	ReturnPaths            []*PathTrace
 	SuccessfulForwardPaths int
 	SuccessfulReturnPaths  int
 	HasReachablePath       bool
+	// Truncated is set when a limit or the context cut the analysis short; the
+	// paths above are the ones found until then.
+	Truncated       bool
+	TruncatedReason string
 }
 
 func (r *AllPathsResult) GetSuccessfulPaths() []*PathTrace {
@@ -75,3 +158,135 @@ func (r *AllPathsResult) GetBlockedPaths() []*PathTrace {
 	}
 	return blocked
 }
+
+// MatrixCell is the result for one source, destination and flow of a matrix.
+// The indexes refer to the slices the matrix was requested with.
+type MatrixCell struct {
+	SourceIndex      int
+	DestinationIndex int
+	FlowIndex        int
+	Flow             FlowSpec
+	Result           ReachabilityResult
+	// Err is set when the source or destination could not be resolved, in
+	// which case Result is empty.
+	Err error
+}
+
+type MatrixSummary struct {
+	Cells     int
+	Reachable int
+	Blocked   int
+	Unknown   int
+	Failed    int
+}
+
+// MatrixResult holds one cell per source, destination and flow, ordered by
+// source, then destination, then flow.
+type MatrixResult struct {
+	Cells        []MatrixCell
+	Summary      MatrixSummary
+	Sources      int
+	Destinations int
+	Flows        int
+}
+
+// NewMatrixResult returns a result with a cell, and no outcome yet, for every
+// combination of sources, destinations and flows.
+func NewMatrixResult(sources, destinations int, flows []FlowSpec) MatrixResult {
+	result := MatrixResult{
+		Cells:        make([]MatrixCell, 0, sources*destinations*len(flows)),
+		Sources:      sources,
+		Destinations: destinations,
+		Flows:        len(flows),
+	}
+	for i := 0; i < sources; i++ {
+		for j := 0; j < destinations; j++ {
+			for k, flow := range flows {
+				result.Cells = append(result.Cells, MatrixCell{SourceIndex: i, DestinationIndex: j, FlowIndex: k, Flow: flow})
+			}
+		}
+	}
+	return result
+}
+
+// Cell returns the cell for the given indexes, or nil if they are out of range.
+func (m *MatrixResult) Cell(source, destination, flow int) *MatrixCell {
+	if source < 0 || source >= m.Sources || destination < 0 || destination >= m.Destinations || flow < 0 || flow >= m.Flows {
+		return nil
+	}
+	return &m.Cells[(source*m.Destinations+destination)*m.Flows+flow]
+}
+
+// Summarize recounts Summary from the cells.
+func (m *MatrixResult) Summarize() {
+	summary := MatrixSummary{Cells: len(m.Cells)}
+	for _, cell := range m.Cells {
+		switch {
+		case cell.Err != nil:
+			summary.Failed++
+		case cell.Result.Verdict == VerdictReachable:
+			summary.Reachable++
+		case cell.Result.Verdict == VerdictBlocked:
+			summary.Blocked++
+		default:
+			summary.Unknown++
+		}
+	}
+	m.Summary = summary
+}
+
+// SourcePath is a resource found by a reverse query together with the paths
+// that let it reach the destination and get replies back.
+type SourcePath struct {
+	SourceID   string
+	SourceType string
+	SourceIP   string
+	Verdict    Verdict
+	Path       *PathTrace
+	ReturnPath *PathTrace
+	// Reason says why an undecided source could not be decided when there
+	// is no path to show it.
+	Reason string
+}
+
+// SourcesResult lists the resources that can reach a destination. Candidates
+// that could not be decided, e.g. because a lookup was denied, are listed in
+// Undecided so an audit does not mistake them for blocked.
+type SourcesResult struct {
+	Sources   []SourcePath
+	Undecided []SourcePath
+	// Candidates is the number of resources that were traversed.
+	Candidates int
+}
+
+// ExposedPorts is a port range of a resource the internet can open a
+// connection to, with the paths that prove it.
+type ExposedPorts struct {
+	Protocol string
+	FromPort int
+	ToPort   int
+	// Source is the internet range the ports are open to, "0.0.0.0/0" or
+	// "::/0".
+	Source     string
+	Path       *PathTrace
+	ReturnPath *PathTrace
+	// Reason says why an undecided range could not be decided when there is
+	// no path to show it.
+	Reason string
+}
+
+// Exposure is a resource reachable from the internet and the ports it exposes.
+// Ports that could not be decided are listed in Undecided.
+type Exposure struct {
+	ResourceID   string
+	ResourceType string
+	PublicIP     string
+	Ports        []ExposedPorts
+	Undecided    []ExposedPorts
+}
+
+// ExposureResult lists the resources in a VPC that are reachable from the
+// internet.
+type ExposureResult struct {
+	Exposures []Exposure
+}
@@ -1,12 +1,68 @@
 package domain
 
+import "fmt"
+
+// RoutingTarget carries the flow being traced. IP and Port describe the
+// destination side of the 5-tuple, SourceIP and SourcePort the source side.
 type RoutingTarget struct {
-	IP       string
-	Port     int
-	Protocol string
-	// Direction indicates the traversal leg perspective for filters.
-	// Expected values: "outbound" or "inbound".
+	IP   string
+	Port int
+	// PortTo is the upper bound when the flow covers a port range; zero means
+	// the flow is a single port.
+	PortTo     int
+	SourceIP   string
+	SourcePort int
+	Protocol   string
+	// Direction indicates which rule set filters apply. "outbound" evaluates
+	// egress rules against IP, "inbound" evaluates ingress rules against SourceIP.
 	Direction string
 	// SourceIsPrivate indicates whether the source IP for this leg is private.
 	SourceIsPrivate bool
+	// Reply marks the flow as the response to a connection that was already
+	// permitted. Stateful filters let it through; stateless ones still evaluate it.
+	Reply bool
+	// ViaPeering is the VPC peering connection the flow crossed to reach the
+	// VPC it is in. Such traffic can only be delivered inside that VPC; it
+	// cannot leave again through a gateway or another peering connection.
+	ViaPeering string
+	// EnteredSubnet is the subnet whose NACL already admitted the flow on its
+	// way in, so the NACL is not evaluated again at the interface.
+	EnteredSubnet string
+}
+
+func (t RoutingTarget) PortRange() (int, int) {
+	if t.PortTo > t.Port {
+		return t.Port, t.PortTo
+	}
+	return t.Port, t.Port
+}
+
+func (t RoutingTarget) Flow() Flow {
+	return Flow{
+		SourceIP:        t.SourceIP,
+		DestinationIP:   t.IP,
+		SourcePort:      t.SourcePort,
+		DestinationPort: t.Port,
+		Protocol:        t.Protocol,
+	}
+}
+
+// Flow is the 5-tuple of the traffic being traced.
+type Flow struct {
+	SourceIP        string
+	DestinationIP   string
+	SourcePort      int
+	DestinationPort int
+	Protocol        string
+}
+
+func (f Flow) String() string {
+	source := f.SourceIP
+	if source == "" {
+		source = "*"
+	}
+	if f.SourcePort != 0 {
+		source = fmt.Sprintf("%s:%d", source, f.SourcePort)
+	}
+	return fmt.Sprintf("%s -> %s:%d/%s", source, f.DestinationIP, f.DestinationPort, f.Protocol)
 }
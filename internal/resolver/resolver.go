@@ -2,13 +2,17 @@ package resolver
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/eleven-am/argus/internal/components"
 	"github.com/eleven-am/argus/internal/domain"
 )
 
+// Resolver is safe for concurrent use by the branches of a traversal.
 type Resolver struct {
 	accountCtx domain.AccountContext
+	mu         sync.RWMutex
 	cacheIP    map[string]domain.Component
 	cacheID    map[string]domain.Component
 }
@@ -21,76 +25,116 @@ func NewResolver(accountCtx domain.AccountContext) *Resolver {
 	}
 }
 
-func (r *Resolver) ResolveByIP(ctx context.Context, accountID, vpcID, ip string) (domain.Component, error) {
+func (r *Resolver) ResolveByIP(ctx context.Context, accountID, region, vpcID, ip string) (domain.Component, error) {
 	if ip == "" {
 		return nil, nil
 	}
-	if comp, ok := r.cacheIP[ip]; ok {
+	// Private ranges are commonly reused across accounts, regions and VPCs,
+	// so the same address may resolve to different resources.
+	key := accountID + "/" + region + "/" + vpcID + "/" + ip
+	r.mu.RLock()
+	comp, ok := r.cacheIP[key]
+	r.mu.RUnlock()
+	if ok {
 		return comp, nil
 	}
-	client, err := r.accountCtx.GetClient(accountID)
+	client, err := r.accountCtx.GetClient(accountID, region)
 	if err != nil {
 		return nil, err
 	}
 
-	if eni, err := client.GetNetworkInterfaceByPrivateIP(ctx, ip, vpcID); err == nil && eni != nil {
-		comp := components.NewNetworkInterface(eni.ID, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if inst, err := client.GetEC2InstanceByPrivateIP(ctx, ip, vpcID); err == nil && inst != nil {
-		comp := components.NewEC2Instance(inst, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if rds, err := client.GetRDSInstanceByPrivateIP(ctx, ip, vpcID); err == nil && rds != nil {
-		comp := components.NewRDSInstance(rds, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if lambda, err := client.GetLambdaFunctionByENIIP(ctx, ip, vpcID); err == nil && lambda != nil {
-		comp := components.NewLambdaFunction(lambda, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if alb, err := client.GetALBByPrivateIP(ctx, ip, vpcID); err == nil && alb != nil {
-		comp := components.NewALB(alb, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if nlb, err := client.GetNLBByPrivateIP(ctx, ip, vpcID); err == nil && nlb != nil {
-		comp := components.NewNLB(nlb, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if clb, err := client.GetCLBByPrivateIP(ctx, ip, vpcID); err == nil && clb != nil {
-		comp := components.NewCLB(clb, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if apigw, err := client.GetAPIGatewayByPrivateIP(ctx, ip, vpcID); err == nil && apigw != nil {
-		comp := components.NewAPIGateway(apigw, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if eksPod, err := client.GetEKSPodByIP(ctx, ip, vpcID); err == nil && eksPod != nil {
-		comp := components.NewEKSPod(eksPod, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
-	}
-
-	if elasticache, err := client.GetElastiCacheClusterByPrivateIP(ctx, ip, vpcID); err == nil && elasticache != nil {
-		comp := components.NewElastiCacheCluster(elasticache, accountID)
-		r.cacheIP[ip] = comp
-		return comp, nil
+	// Lookups run from the most to the least specific kind of resource. One
+	// that fails for any reason other than the resource not existing leaves
+	// the address unresolved, rather than falling through to a kind that
+	// does not own it.
+	lookups := []func() (domain.Component, error){
+		func() (domain.Component, error) {
+			eni, err := client.GetNetworkInterfaceByPrivateIP(ctx, ip, vpcID)
+			if err != nil || eni == nil {
+				return nil, err
+			}
+			// Address the interface as looked up: ip may be one of its
+			// secondary or IPv6 addresses rather than its primary private IP.
+			addressed := *eni
+			addressed.PrivateIP = ip
+			return components.NewNetworkInterfaceFromData(&addressed, accountID), nil
+		},
+		func() (domain.Component, error) {
+			inst, err := client.GetEC2InstanceByPrivateIP(ctx, ip, vpcID)
+			if err != nil || inst == nil {
+				return nil, err
+			}
+			return components.NewEC2Instance(inst, accountID), nil
+		},
+		func() (domain.Component, error) {
+			rds, err := client.GetRDSInstanceByPrivateIP(ctx, ip, vpcID)
+			if err != nil || rds == nil {
+				return nil, err
+			}
+			return components.NewRDSInstance(rds, accountID), nil
+		},
+		func() (domain.Component, error) {
+			lambda, err := client.GetLambdaFunctionByENIIP(ctx, ip, vpcID)
+			if err != nil || lambda == nil {
+				return nil, err
+			}
+			return components.NewLambdaFunction(lambda, accountID), nil
+		},
+		func() (domain.Component, error) {
+			alb, err := client.GetALBByPrivateIP(ctx, ip, vpcID)
+			if err != nil || alb == nil {
+				return nil, err
+			}
+			return components.NewALB(alb, accountID), nil
+		},
+		func() (domain.Component, error) {
+			nlb, err := client.GetNLBByPrivateIP(ctx, ip, vpcID)
+			if err != nil || nlb == nil {
+				return nil, err
+			}
+			return components.NewNLB(nlb, accountID), nil
+		},
+		func() (domain.Component, error) {
+			clb, err := client.GetCLBByPrivateIP(ctx, ip, vpcID)
+			if err != nil || clb == nil {
+				return nil, err
+			}
+			return components.NewCLB(clb, accountID), nil
+		},
+		func() (domain.Component, error) {
+			apigw, err := client.GetAPIGatewayByPrivateIP(ctx, ip, vpcID)
+			if err != nil || apigw == nil {
+				return nil, err
+			}
+			return components.NewAPIGateway(apigw, accountID), nil
+		},
+		func() (domain.Component, error) {
+			eksPod, err := client.GetEKSPodByIP(ctx, ip, vpcID)
+			if err != nil || eksPod == nil {
+				return nil, err
+			}
+			return components.NewEKSPod(eksPod, accountID), nil
+		},
+		func() (domain.Component, error) {
+			elasticache, err := client.GetElastiCacheClusterByPrivateIP(ctx, ip, vpcID)
+			if err != nil || elasticache == nil {
+				return nil, err
+			}
+			return components.NewElastiCacheCluster(elasticache, accountID), nil
+		},
+	}
+
+	for _, lookup := range lookups {
+		comp, err := lookup()
+		if err != nil {
+			if domain.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("resolve %s: %w", ip, err)
+		}
+		if comp != nil {
+			return r.rememberIP(key, comp), nil
+		}
 	}
 
 	return nil, nil
@@ -100,8 +144,18 @@ func (r *Resolver) ResolveByID(ctx context.Context, id string) (domain.Component
 	if id == "" {
 		return nil, nil
 	}
-	if comp, ok := r.cacheID[id]; ok {
+	r.mu.RLock()
+	comp, ok := r.cacheID[id]
+	r.mu.RUnlock()
+	if ok {
 		return comp, nil
 	}
 	return nil, nil
 }
+
+func (r *Resolver) rememberIP(key string, comp domain.Component) domain.Component {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cacheIP[key] = comp
+	return comp
+}
@@ -18,6 +18,8 @@ type PathTrace = domain.PathTrace
 
 type ComponentHop = domain.ComponentHop
 
+type Translation = domain.Translation
+
 type HopAction = domain.HopAction
 
 const (
@@ -38,6 +40,62 @@ type RuleEvaluation = domain.RuleEvaluation
 
 type EvaluationResult = domain.EvaluationResult
 
+type FlowSpec = domain.FlowSpec
+
+type PortRangeSpec = domain.PortRangeSpec
+
+type AddressFamily = domain.AddressFamily
+
+const (
+	AddressFamilyIPv4 = domain.AddressFamilyIPv4
+	AddressFamilyIPv6 = domain.AddressFamilyIPv6
+)
+
+type DualStackResult = domain.DualStackResult
+
+type Options = domain.Options
+
+type Strategy = domain.Strategy
+
+const (
+	StrategyFirstPath = domain.StrategyFirstPath
+	StrategyAllPaths  = domain.StrategyAllPaths
+)
+
+type MatrixResult = domain.MatrixResult
+
+type MatrixCell = domain.MatrixCell
+
+type MatrixSummary = domain.MatrixSummary
+
+type SourcesResult = domain.SourcesResult
+
+type SourcePath = domain.SourcePath
+
+type ExposureResult = domain.ExposureResult
+
+type Exposure = domain.Exposure
+
+type ExposedPorts = domain.ExposedPorts
+
+const DefaultConcurrency = domain.DefaultConcurrency
+
+type Verdict = domain.Verdict
+
+const (
+	VerdictReachable = domain.VerdictReachable
+	VerdictBlocked   = domain.VerdictBlocked
+	VerdictUnknown   = domain.VerdictUnknown
+)
+
+type BlockingError = domain.BlockingError
+
+type AccessDeniedError = domain.AccessDeniedError
+
+type NotFoundError = domain.NotFoundError
+
+type TransientError = domain.TransientError
+
 type resourceType int
 
 const (
@@ -62,14 +120,24 @@ const (
 	resourceTypeDirectConnectGateway
 	resourceTypeCarrierGateway
 	resourceTypeLocalGateway
+	resourceTypeVPNOnPrem
 )
 
 type ResourceRef struct {
 	accountID    string
+	region       string
 	resourceID   string
 	resourceType resourceType
 }
 
+// InRegion returns a copy of the reference that looks the resource up in
+// region instead of the account context's default region.
+// Example: EC2(acct, "i-0abc123").InRegion("eu-west-1")
+func (r ResourceRef) InRegion(region string) ResourceRef {
+	r.region = region
+	return r
+}
+
 // EC2 creates a reference to an EC2 instance.
 // Use the instance ID (e.g., "i-0abc123def456").
 func EC2(accountID, instanceID string) ResourceRef {
@@ -154,6 +222,14 @@ func OnPremDirectConnect(accountID, dxgwID, sourceIP string) ResourceRef {
 	return ResourceRef{accountID: accountID, resourceID: dxgwID + "/" + sourceIP, resourceType: resourceTypeDirectConnectOnPrem}
 }
 
+// OnPremVPN creates a reference to an on-premises source via Site-to-Site VPN.
+// Use the virtual private gateway ID and the on-prem source IP address. Traffic
+// enters the VPC through the gateway and follows the gateway's edge-associated
+// route table when it has one.
+func OnPremVPN(accountID, vgwID, sourceIP string) ResourceRef {
+	return ResourceRef{accountID: accountID, resourceID: vgwID + "/" + sourceIP, resourceType: resourceTypeVPNOnPrem}
+}
+
 // GWLBEndpoint creates a reference to a Gateway Load Balancer Endpoint.
 // Use the VPC endpoint ID (e.g., "vpce-0abc123").
 func GWLBEndpoint(accountID, vpceID string) ResourceRef {
@@ -161,7 +237,12 @@ func GWLBEndpoint(accountID, vpceID string) ResourceRef {
 }
 
 // ExternalIP creates a reference to an external IP address (e.g., internet destination).
-// Use for testing connectivity to public IPs like "8.8.8.8" on a specific port.
+// Use for testing connectivity to public IPv4 or IPv6 addresses like "8.8.8.8" on
+// a specific port.
+// As a source, its traffic enters the destination's VPC through the internet
+// gateway, so ExternalIP("203.0.113.7", 0) to an instance checks that the
+// instance has a public address and that its NACL and security groups let the
+// address in. The port is ignored for sources.
 func ExternalIP(ip string, port int) ResourceRef {
 	return ResourceRef{accountID: "", resourceID: fmt.Sprintf("%s/%d", ip, port), resourceType: resourceTypeIPTarget}
 }
@@ -212,7 +293,7 @@ func (r ResourceRef) resolve(ctx context.Context, accountCtx *AccountContext) (d
 		return components.NewIPTarget(data, ""), nil
 	}
 
-	client, err := accountCtx.GetClient(r.accountID)
+	client, err := accountCtx.GetClient(r.accountID, r.region)
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +389,8 @@ func (r ResourceRef) resolve(ctx context.Context, accountCtx *AccountContext) (d
 		return components.NewVPCEndpoint(data, r.accountID), nil
 
 	case resourceTypeNetworkInterface:
-		return components.NewNetworkInterface(r.resourceID, r.accountID), nil
+		data := &domain.ENIData{ID: r.resourceID, Region: r.region}
+		return components.NewNetworkInterfaceFromData(data, r.accountID), nil
 
 	case resourceTypeDirectConnectOnPrem:
 		parts := splitResourceID(r.resourceID, 2)
@@ -322,6 +404,18 @@ func (r ResourceRef) resolve(ctx context.Context, accountCtx *AccountContext) (d
 		}
 		return components.NewDirectConnectOnPrem(data, r.accountID), nil
 
+	case resourceTypeVPNOnPrem:
+		parts := splitResourceID(r.resourceID, 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid VPN on-prem resource ID format, expected vgwID/sourceIP")
+		}
+		data := &domain.VPNOnPremData{
+			VGWID:    parts[0],
+			SourceIP: parts[1],
+			Region:   r.region,
+		}
+		return components.NewVPNOnPrem(data, r.accountID), nil
+
 	case resourceTypeGWLBEndpoint:
 		data, err := client.GetVPCEndpoint(ctx, r.resourceID)
 		if err != nil {
@@ -351,10 +445,10 @@ func (r ResourceRef) resolve(ctx context.Context, accountCtx *AccountContext) (d
 		return components.NewDirectConnectGateway(data, r.accountID), nil
 
 	case resourceTypeCarrierGateway:
-		return components.NewCarrierGateway(r.resourceID, r.accountID), nil
+		return components.NewCarrierGateway(r.resourceID, r.accountID, r.region), nil
 
 	case resourceTypeLocalGateway:
-		return components.NewLocalGateway(r.resourceID, r.accountID), nil
+		return components.NewLocalGateway(r.resourceID, r.accountID, r.region), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported resource type")